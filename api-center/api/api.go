@@ -1,5 +1,8 @@
 package api
 
 import (
+	_ "github.com/MortalSC/IM-System/api-center/api/group"
+	_ "github.com/MortalSC/IM-System/api-center/api/message"
+	_ "github.com/MortalSC/IM-System/api-center/api/presence"
 	_ "github.com/MortalSC/IM-System/api-center/api/user"
 )
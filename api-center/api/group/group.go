@@ -0,0 +1,383 @@
+package group
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	groupModel "github.com/MortalSC/IM-System/api-center/pkg/model/group"
+	groupServiceV1 "github.com/MortalSC/IM-System/group-service/pkg/service/group.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// 加群方式，与 group-service 一致
+const joinInvite = 2
+
+type HandlerGroup struct {
+}
+
+func New() *HandlerGroup {
+	return &HandlerGroup{}
+}
+
+// create 创建群组，当前账号成为群主
+// [POST] /project/groups/create
+func (h *HandlerGroup) create(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.CreateReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群名称不能为空"))
+		return
+	}
+	if req.JoinMode == 0 {
+		req.JoinMode = joinInvite
+	}
+
+	rsp, err := GroupServiceClient.CreateGroup(ctx, &groupServiceV1.CreateGroupMessage{
+		OwnerId:    auth.UserId(ctx),
+		Name:       req.Name,
+		Avatar:     req.Avatar,
+		Intro:      req.Intro,
+		JoinMode:   req.JoinMode,
+		MaxMembers: req.MaxMembers,
+		MemberIds:  req.MemberIds,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 CreateGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toGroup(rsp.Group)))
+}
+
+// dissolve 群主解散群组
+// [POST] /project/groups/dissolve
+func (h *HandlerGroup) dissolve(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.GroupReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	_, err := GroupServiceClient.DissolveGroup(ctx, &groupServiceV1.DissolveGroupMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 DissolveGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// invite 邀请成员，已在群内的用户被忽略
+// [POST] /project/groups/invite
+func (h *HandlerGroup) invite(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.InviteReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组和邀请的成员不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.InviteMembers(ctx, &groupServiceV1.InviteMembersMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+		UserIds:    req.UserIds,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 InviteMembers 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	added := rsp.Added
+	if added == nil {
+		added = []int64{}
+	}
+	ctx.JSON(http.StatusOK, result.Success(groupModel.InviteResult{Added: added, MemberCount: rsp.MemberCount}))
+}
+
+// join 主动加入公开群
+// [POST] /project/groups/join
+func (h *HandlerGroup) join(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.GroupReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.JoinGroup(ctx, &groupServiceV1.JoinGroupMessage{
+		GroupId: req.GroupId,
+		UserId:  auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 JoinGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toGroup(rsp.Group)))
+}
+
+// leave 退出群组，群主需先转让群组
+// [POST] /project/groups/leave
+func (h *HandlerGroup) leave(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.GroupReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	_, err := GroupServiceClient.LeaveGroup(ctx, &groupServiceV1.LeaveGroupMessage{
+		GroupId: req.GroupId,
+		UserId:  auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 LeaveGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// kick 移除成员：群主可移除任何人，管理员只能移除普通成员
+// [POST] /project/groups/kick
+func (h *HandlerGroup) kick(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.MemberReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组和成员不能为空"))
+		return
+	}
+
+	_, err := GroupServiceClient.KickMember(ctx, &groupServiceV1.KickMemberMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+		UserId:     req.UserId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 KickMember 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// setRole 群主设置或取消管理员
+// [POST] /project/groups/role
+func (h *HandlerGroup) setRole(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.RoleReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组、成员和角色不能为空"))
+		return
+	}
+
+	_, err := GroupServiceClient.SetMemberRole(ctx, &groupServiceV1.SetMemberRoleMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+		UserId:     req.UserId,
+		Role:       req.Role,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 SetMemberRole 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// transferOwner 群主将群组转让给其他成员，原群主成为管理员
+// [POST] /project/groups/transfer
+func (h *HandlerGroup) transferOwner(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.MemberReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组和新群主不能为空"))
+		return
+	}
+
+	_, err := GroupServiceClient.TransferOwner(ctx, &groupServiceV1.TransferOwnerMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+		UserId:     req.UserId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 TransferOwner 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// update 群主或管理员修改群资料和加群方式
+// [POST] /project/groups/update
+func (h *HandlerGroup) update(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.UpdateReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.UpdateGroup(ctx, &groupServiceV1.UpdateGroupMessage{
+		GroupId:    req.GroupId,
+		OperatorId: auth.UserId(ctx),
+		Name:       req.Name,
+		Avatar:     req.Avatar,
+		Intro:      req.Intro,
+		JoinMode:   req.JoinMode,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 UpdateGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toGroup(rsp.Group)))
+}
+
+// setAnnouncement 群主或管理员发布群公告，内容为空时清除
+// [POST] /project/groups/announcement
+func (h *HandlerGroup) setAnnouncement(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.AnnouncementReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.SetAnnouncement(ctx, &groupServiceV1.SetAnnouncementMessage{
+		GroupId:      req.GroupId,
+		OperatorId:   auth.UserId(ctx),
+		Announcement: req.Announcement,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 SetAnnouncement 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toGroup(rsp.Group)))
+}
+
+// info 查询群资料和当前账号在群内的角色，非成员也可查看以便加入
+// [GET] /project/groups/info
+func (h *HandlerGroup) info(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.GroupReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.GetGroup(ctx, &groupServiceV1.GetGroupMessage{
+		GroupId: req.GroupId,
+		UserId:  auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 GetGroup 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toGroup(rsp.Group)))
+}
+
+// members 成员按用户 ID 翻页查询群成员
+// [GET] /project/groups/members
+func (h *HandlerGroup) members(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req groupModel.MembersReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "群组不能为空"))
+		return
+	}
+
+	rsp, err := GroupServiceClient.ListMembers(ctx, &groupServiceV1.ListMembersMessage{
+		GroupId:     req.GroupId,
+		UserId:      auth.UserId(ctx),
+		AfterUserId: req.AfterUserId,
+		Limit:       req.Limit,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListMembers 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	page := groupModel.MemberPage{Members: make([]groupModel.Member, 0, len(rsp.Members)), HasMore: rsp.HasMore}
+	for _, m := range rsp.Members {
+		page.Members = append(page.Members, groupModel.Member{
+			UserId:    m.UserId,
+			Role:      m.Role,
+			InviterId: m.InviterId,
+			JoinTime:  m.JoinTime,
+		})
+	}
+	ctx.JSON(http.StatusOK, result.Success(page))
+}
+
+// list 查询当前账号加入的群组
+// [GET] /project/groups/list
+func (h *HandlerGroup) list(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := GroupServiceClient.ListUserGroups(ctx, &groupServiceV1.ListUserGroupsMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListUserGroups 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	list := make([]groupModel.Group, 0, len(rsp.Groups))
+	for _, g := range rsp.Groups {
+		list = append(list, toGroup(g))
+	}
+	ctx.JSON(http.StatusOK, result.Success(list))
+}
+
+func toGroup(g *groupServiceV1.GroupInfo) groupModel.Group {
+	return groupModel.Group{
+		GroupId:          g.GroupId,
+		Name:             g.Name,
+		Avatar:           g.Avatar,
+		Intro:            g.Intro,
+		Announcement:     g.Announcement,
+		AnnouncementTime: g.AnnouncementTime,
+		AnnouncementBy:   g.AnnouncementBy,
+		OwnerId:          g.OwnerId,
+		MemberCount:      g.MemberCount,
+		MaxMembers:       g.MaxMembers,
+		JoinMode:         g.JoinMode,
+		Fanout:           g.Fanout,
+		CreateTime:       g.CreateTime,
+		Role:             g.Role,
+		JoinTime:         g.JoinTime,
+	}
+}
@@ -0,0 +1,43 @@
+package group
+
+import (
+	"github.com/MortalSC/IM-System/api-center/internal/router"
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+// 与 auth-service 开放平台的授权范围一致
+const scopeGroupsManage = "groups:manage"
+
+func init() {
+	log.Println("init group router")
+	rg := &RouterGroup{}
+	router.Register(rg)
+}
+
+// RouterGroup ： 群组路由
+type RouterGroup struct {
+}
+
+// Router 方法实现路由注册逻辑
+func (rg *RouterGroup) Router(r *gin.Engine) {
+	// 初始化grpc客户端连接
+	InitRpcGroupClient()
+
+	// 第三方应用和 API Key 需要对应的授权范围；群内权限（群主、管理员）由 group-service 校验
+	h := New()
+	groups := router.Scoped(r, "/project/groups", scopeGroupsManage)
+	groups.POST("/create", h.create)
+	groups.POST("/dissolve", h.dissolve)
+	groups.POST("/invite", h.invite)
+	groups.POST("/join", h.join)
+	groups.POST("/leave", h.leave)
+	groups.POST("/kick", h.kick)
+	groups.POST("/role", h.setRole)
+	groups.POST("/transfer", h.transferOwner)
+	groups.POST("/update", h.update)
+	groups.POST("/announcement", h.setAnnouncement)
+	groups.GET("/info", h.info)
+	groups.GET("/members", h.members)
+	groups.GET("/list", h.list)
+}
@@ -0,0 +1,27 @@
+package group
+
+import (
+	"github.com/MortalSC/IM-System/api-center/config"
+	groupServiceV1 "github.com/MortalSC/IM-System/group-service/pkg/service/group.service.v1"
+	"github.com/MortalSC/IM-System/lib/discovery"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"log"
+)
+
+var GroupServiceClient groupServiceV1.GroupServiceClient
+
+// InitRpcGroupClient 通过 etcd 发现 group-service，按轮询选择节点
+func InitRpcGroupClient() {
+	resolver.Register(discovery.NewResolver(config.Cfg.EtcdCfg.Addrs, libLog.IMLog))
+	conn, err := grpc.Dial(discovery.BuildResolverUrl(config.Cfg.GroupCfg.Service),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	GroupServiceClient = groupServiceV1.NewGroupServiceClient(conn)
+}
@@ -0,0 +1,270 @@
+package message
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	messageModel "github.com/MortalSC/IM-System/api-center/pkg/model/message"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// 会话类型，与 message-service 一致
+const conversationSingle = 1
+
+type HandlerMessage struct {
+}
+
+func New() *HandlerMessage {
+	return &HandlerMessage{}
+}
+
+// send 以当前账号发送消息，返回服务端分配的序号；相同的 clientMsgId 重复提交时返回首次发送的结果
+// [POST] /project/messages
+func (h *HandlerMessage) send(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req messageModel.SendReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "接收方、消息类型和内容不能为空"))
+		return
+	}
+	if req.ConversationType == 0 {
+		req.ConversationType = conversationSingle
+	}
+
+	rsp, err := MessageServiceClient.Send(ctx, &messageServiceV1.SendMessage{
+		SenderId:         auth.UserId(ctx),
+		ClientMsgId:      req.ClientMsgId,
+		ConversationType: req.ConversationType,
+		TargetId:         req.TargetId,
+		ContentType:      req.ContentType,
+		Content:          []byte(req.Content),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Send 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(messageModel.SendAck{
+		ClientMsgId:    rsp.ClientMsgId,
+		MsgId:          rsp.MsgId,
+		Seq:            rsp.Seq,
+		ConversationId: rsp.ConversationId,
+		SendTime:       rsp.SendTime,
+		Duplicate:      rsp.Duplicate,
+	}))
+}
+
+// history 分页查询与对方的历史消息，从最新一条向前翻页
+// [GET] /project/messages/history
+func (h *HandlerMessage) history(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req messageModel.HistoryReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "会话对象不能为空"))
+		return
+	}
+	if req.ConversationType == 0 {
+		req.ConversationType = conversationSingle
+	}
+
+	rsp, err := MessageServiceClient.History(ctx, &messageServiceV1.HistoryMessage{
+		UserId:           auth.UserId(ctx),
+		ConversationType: req.ConversationType,
+		TargetId:         req.TargetId,
+		BeforeSeq:        req.BeforeSeq,
+		Limit:            req.Limit,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 History 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	page := messageModel.HistoryPage{
+		Messages:       make([]messageModel.Message, 0, len(rsp.Messages)),
+		HasMore:        rsp.HasMore,
+		ConversationId: rsp.ConversationId,
+	}
+	for _, m := range rsp.Messages {
+		page.Messages = append(page.Messages, toMessage(m))
+	}
+	ctx.JSON(http.StatusOK, result.Success(page))
+}
+
+// sync 同步当前设备离线期间的消息和会话列表增量，游标按设备保存在服务端
+// [POST] /project/messages/sync
+func (h *HandlerMessage) sync(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req messageModel.SyncReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "同步参数不正确"))
+		return
+	}
+	// 登录令牌中的设备优先，API Key 等不带设备的凭证使用请求中的设备
+	deviceId := auth.DeviceId(ctx)
+	if deviceId == "" {
+		deviceId = req.DeviceId
+	}
+	if deviceId == "" {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "设备不能为空"))
+		return
+	}
+
+	msg := &messageServiceV1.SyncMessage{
+		UserId:              auth.UserId(ctx),
+		DeviceId:            deviceId,
+		Resume:              req.Resume,
+		Cursor:              req.Cursor,
+		ConversationCursors: make([]*messageServiceV1.ConversationCursor, 0, len(req.ConversationCursors)),
+		ConversationVersion: req.ConversationVersion,
+		Limit:               req.Limit,
+	}
+	for _, c := range req.ConversationCursors {
+		msg.ConversationCursors = append(msg.ConversationCursors, &messageServiceV1.ConversationCursor{ConversationId: c.ConversationId, Seq: c.Seq})
+	}
+	rsp, err := MessageServiceClient.Sync(ctx, msg)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Sync 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+
+	page := messageModel.SyncPage{
+		Messages:            make([]messageModel.Message, 0, len(rsp.Messages)),
+		Cursor:              rsp.Cursor,
+		HasMore:             rsp.HasMore,
+		Reset:               rsp.Reset_,
+		ConversationCursors: make([]messageModel.ConversationCursor, 0, len(rsp.ConversationCursors)),
+		Conversations:       make([]messageModel.Conversation, 0, len(rsp.Conversations)),
+		ConversationVersion: rsp.ConversationVersion,
+	}
+	for _, m := range rsp.Messages {
+		page.Messages = append(page.Messages, toMessage(m))
+	}
+	for _, c := range rsp.ConversationCursors {
+		page.ConversationCursors = append(page.ConversationCursors, messageModel.ConversationCursor{ConversationId: c.ConversationId, Seq: c.Seq, Reset: c.Reset_})
+	}
+	for _, c := range rsp.Conversations {
+		conv := messageModel.Conversation{
+			ConversationType: c.ConversationType,
+			ConversationId:   c.ConversationId,
+			TargetId:         c.TargetId,
+			MaxSeq:           c.MaxSeq,
+			Unread:           c.Unread,
+			Version:          c.Version,
+		}
+		if c.LastMessage != nil {
+			last := toMessage(c.LastMessage)
+			conv.LastMessage = &last
+		}
+		page.Conversations = append(page.Conversations, conv)
+	}
+	ctx.JSON(http.StatusOK, result.Success(page))
+}
+
+// ack 确认会话中的消息已送达或已读，已读时扣减未读数并通知发送方
+// [POST] /project/messages/ack
+func (h *HandlerMessage) ack(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req messageModel.AckReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "确认参数不正确"))
+		return
+	}
+	rsp, err := MessageServiceClient.Ack(ctx, &messageServiceV1.AckMessage{
+		UserId:         auth.UserId(ctx),
+		DeviceId:       auth.DeviceId(ctx),
+		Type:           req.Type,
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Ack 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(messageModel.AckResult{
+		DeliveredSeq: rsp.DeliveredSeq,
+		ReadSeq:      rsp.ReadSeq,
+		Unread:       rsp.Unread,
+	}))
+}
+
+// unread 查询各会话的未读数和总数
+// [GET] /project/messages/unread
+func (h *HandlerMessage) unread(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := MessageServiceClient.Unread(ctx, &messageServiceV1.UnreadMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Unread 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	summary := messageModel.UnreadSummary{Conversations: make([]messageModel.UnreadCount, 0, len(rsp.Conversations)), Total: rsp.Total}
+	for _, c := range rsp.Conversations {
+		summary.Conversations = append(summary.Conversations, messageModel.UnreadCount{ConversationId: c.ConversationId, Unread: c.Unread})
+	}
+	ctx.JSON(http.StatusOK, result.Success(summary))
+}
+
+// receipts 查看自己发送的消息的送达和已读情况
+// [GET] /project/messages/receipts
+func (h *HandlerMessage) receipts(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req messageModel.ReceiptsReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "会话和消息序号不能为空"))
+		return
+	}
+	rsp, err := MessageServiceClient.Receipts(ctx, &messageServiceV1.ReceiptsMessage{
+		UserId:         auth.UserId(ctx),
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Receipts 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	readBy := rsp.ReadBy
+	if readBy == nil {
+		readBy = make([]int64, 0)
+	}
+	ctx.JSON(http.StatusOK, result.Success(messageModel.Receipts{
+		MemberCount:    rsp.MemberCount,
+		DeliveredCount: rsp.DeliveredCount,
+		ReadCount:      rsp.ReadCount,
+		ReadBy:         readBy,
+	}))
+}
+
+func toMessage(m *messageServiceV1.MessageInfo) messageModel.Message {
+	return messageModel.Message{
+		MsgId:            m.MsgId,
+		Seq:              m.Seq,
+		ConversationType: m.ConversationType,
+		ConversationId:   m.ConversationId,
+		SenderId:         m.SenderId,
+		SenderDevice:     m.SenderDevice,
+		TargetId:         m.TargetId,
+		ContentType:      m.ContentType,
+		Content:          string(m.Content),
+		ClientMsgId:      m.ClientMsgId,
+		SendTime:         m.SendTime,
+	}
+}
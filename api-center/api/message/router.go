@@ -0,0 +1,40 @@
+package message
+
+import (
+	"github.com/MortalSC/IM-System/api-center/internal/router"
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+// 与 auth-service 开放平台的授权范围一致
+const (
+	scopeMessagesRead = "messages:read"
+	scopeMessagesSend = "messages:send"
+)
+
+func init() {
+	log.Println("init message router")
+	rm := &RouterMessage{}
+	router.Register(rm)
+}
+
+// RouterMessage ： 消息路由
+type RouterMessage struct {
+}
+
+// Router 方法实现路由注册逻辑
+func (rm *RouterMessage) Router(r *gin.Engine) {
+	// 初始化grpc客户端连接
+	InitRpcMessageClient()
+
+	// 第三方应用和 API Key 需要对应的授权范围
+	h := New()
+	send := router.Scoped(r, "/project/messages", scopeMessagesSend)
+	send.POST("", h.send)
+	read := router.Scoped(r, "/project/messages", scopeMessagesRead)
+	read.GET("/history", h.history)
+	read.POST("/sync", h.sync)
+	read.POST("/ack", h.ack)
+	read.GET("/unread", h.unread)
+	read.GET("/receipts", h.receipts)
+}
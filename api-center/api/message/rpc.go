@@ -0,0 +1,27 @@
+package message
+
+import (
+	"github.com/MortalSC/IM-System/api-center/config"
+	"github.com/MortalSC/IM-System/lib/discovery"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"log"
+)
+
+var MessageServiceClient messageServiceV1.MessageServiceClient
+
+// InitRpcMessageClient 通过 etcd 发现 message-service，按轮询选择节点
+func InitRpcMessageClient() {
+	resolver.Register(discovery.NewResolver(config.Cfg.EtcdCfg.Addrs, libLog.IMLog))
+	conn, err := grpc.Dial(discovery.BuildResolverUrl(config.Cfg.MsgCfg.Service),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	MessageServiceClient = messageServiceV1.NewMessageServiceClient(conn)
+}
@@ -0,0 +1,162 @@
+package presence
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	presenceModel "github.com/MortalSC/IM-System/api-center/pkg/model/presence"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HandlerPresence struct {
+}
+
+func New() *HandlerPresence {
+	return &HandlerPresence{}
+}
+
+// query 批量查询用户的在线状态，最后在线时间按对方的隐私设置返回
+// [GET] /project/presence/query
+func (h *HandlerPresence) query(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req presenceModel.UsersReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "用户不能为空"))
+		return
+	}
+	rsp, err := PresenceServiceClient.Query(ctx, &presenceServiceV1.QueryMessage{
+		UserId:    auth.UserId(ctx),
+		TargetIds: req.UserIds,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Query 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toPresences(rsp.Presences)))
+}
+
+// subscribe 订阅联系人的在线状态变化，变化通过实时连接推送，返回联系人当前的状态
+// [POST] /project/presence/subscribe
+func (h *HandlerPresence) subscribe(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req presenceModel.UsersReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "用户不能为空"))
+		return
+	}
+	rsp, err := PresenceServiceClient.Subscribe(ctx, &presenceServiceV1.SubscribeMessage{
+		UserId:    auth.UserId(ctx),
+		TargetIds: req.UserIds,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Subscribe 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toPresences(rsp.Presences)))
+}
+
+// unsubscribe 取消订阅
+// [POST] /project/presence/unsubscribe
+func (h *HandlerPresence) unsubscribe(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req presenceModel.UsersReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "用户不能为空"))
+		return
+	}
+	_, err := PresenceServiceClient.Unsubscribe(ctx, &presenceServiceV1.UnsubscribeMessage{
+		UserId:    auth.UserId(ctx),
+		TargetIds: req.UserIds,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Unsubscribe 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// subscriptions 查询订阅的全部联系人
+// [GET] /project/presence/subscriptions
+func (h *HandlerPresence) subscriptions(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := PresenceServiceClient.Subscriptions(ctx, &presenceServiceV1.SubscriptionsMessage{
+		UserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Subscriptions 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	userIds := rsp.TargetIds
+	if userIds == nil {
+		userIds = make([]int64, 0)
+	}
+	ctx.JSON(http.StatusOK, result.Success(presenceModel.Subscriptions{UserIds: userIds}))
+}
+
+// settings 查询在线状态的隐私设置
+// [GET] /project/presence/settings
+func (h *HandlerPresence) settings(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := PresenceServiceClient.GetSettings(ctx, &presenceServiceV1.GetSettingsMessage{
+		UserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 GetSettings 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toSettings(rsp.Settings)))
+}
+
+// updateSettings 设置谁可以查看最后在线时间
+// [POST] /project/presence/settings
+func (h *HandlerPresence) updateSettings(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req presenceModel.SettingsReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "可见范围不能为空"))
+		return
+	}
+	rsp, err := PresenceServiceClient.UpdateSettings(ctx, &presenceServiceV1.UpdateSettingsMessage{
+		UserId:             auth.UserId(ctx),
+		LastSeenVisibility: req.LastSeenVisibility,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 UpdateSettings 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toSettings(rsp.Settings)))
+}
+
+func toPresences(infos []*presenceServiceV1.PresenceInfo) []presenceModel.Presence {
+	presences := make([]presenceModel.Presence, 0, len(infos))
+	for _, p := range infos {
+		presences = append(presences, presenceModel.Presence{UserId: p.UserId, Status: p.Status, LastSeen: p.LastSeen})
+	}
+	return presences
+}
+
+func toSettings(s *presenceServiceV1.SettingsInfo) presenceModel.Settings {
+	return presenceModel.Settings{LastSeenVisibility: s.GetLastSeenVisibility(), UpdateTime: s.GetUpdateTime()}
+}
@@ -0,0 +1,33 @@
+package presence
+
+import (
+	"github.com/MortalSC/IM-System/api-center/internal/router"
+	"github.com/gin-gonic/gin"
+	"log"
+)
+
+func init() {
+	log.Println("init presence router")
+	rp := &RouterPresence{}
+	router.Register(rp)
+}
+
+// RouterPresence ： 在线状态路由
+type RouterPresence struct {
+}
+
+// Router 方法实现路由注册逻辑
+func (rp *RouterPresence) Router(r *gin.Engine) {
+	// 初始化grpc客户端连接
+	InitRpcPresenceClient()
+
+	// 在线状态和联系人订阅涉及隐私，没有对应的授权范围，只开放给第一方登录令牌
+	h := New()
+	presences := router.Authenticated(r, "/project/presence")
+	presences.GET("/query", h.query)
+	presences.POST("/subscribe", h.subscribe)
+	presences.POST("/unsubscribe", h.unsubscribe)
+	presences.GET("/subscriptions", h.subscriptions)
+	presences.GET("/settings", h.settings)
+	presences.POST("/settings", h.updateSettings)
+}
@@ -0,0 +1,27 @@
+package presence
+
+import (
+	"github.com/MortalSC/IM-System/api-center/config"
+	"github.com/MortalSC/IM-System/lib/discovery"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"log"
+)
+
+var PresenceServiceClient presenceServiceV1.PresenceServiceClient
+
+// InitRpcPresenceClient 通过 etcd 发现 presence-service，按轮询选择节点
+func InitRpcPresenceClient() {
+	resolver.Register(discovery.NewResolver(config.Cfg.EtcdCfg.Addrs, libLog.IMLog))
+	conn, err := grpc.Dial(discovery.BuildResolverUrl(config.Cfg.PresenceCfg.Service),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	PresenceServiceClient = presenceServiceV1.NewPresenceServiceClient(conn)
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// changeMobile 更换绑定的手机号，需提供当前手机号和新手机号收到的验证码
+// [POST] /project/account/mobile
+func (h *HandlerUser) changeMobile(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ChangeMobileReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "新手机号和验证码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.ChangeMobile(ctx, &loginServiceV1.ChangeMobileMessage{
+		UserId:     auth.UserId(ctx),
+		OldCaptcha: req.OldCaptcha,
+		Mobile:     req.Mobile,
+		Captcha:    req.Captcha,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ChangeMobile 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toMember(rsp.Member)))
+}
+
+// exportAccountData 以附件形式下载账号数据（JSON），建议在申请注销前导出
+// [GET] /project/account/export
+func (h *HandlerUser) exportAccountData(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ExportAccountData(ctx, &loginServiceV1.ExportAccountDataMessage{
+		UserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ExportAccountData 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	name := fmt.Sprintf("account-%d-%s.json", auth.UserId(ctx), time.UnixMilli(rsp.ExportTime).Format("20060102150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", []byte(rsp.Data))
+}
+
+// deleteAccount 申请注销账号，全部设备立即下线，冷静期结束后清除账号数据
+// [POST] /project/account/delete
+func (h *HandlerUser) deleteAccount(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.DeleteAccountReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "验证码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.RequestAccountDeletion(ctx, &loginServiceV1.RequestAccountDeletionMessage{
+		UserId:  auth.UserId(ctx),
+		Captcha: req.Captcha,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RequestAccountDeletion 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.AccountDeletion{PurgeTime: rsp.PurgeTime}))
+}
+
+// cancelAccountDeletion 在冷静期内撤销注销
+// [POST] /project/account/delete/cancel
+func (h *HandlerUser) cancelAccountDeletion(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	_, err := LoginServiceClient.CancelAccountDeletion(ctx, &loginServiceV1.CancelAccountDeletionMessage{
+		UserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 CancelAccountDeletion 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
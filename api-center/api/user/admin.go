@@ -0,0 +1,144 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// queryAuditEvents 按时间、账号、事件类型和结果查询审计事件，需要 audit:read 权限
+// [GET] /project/admin/audit
+func (h *HandlerUser) queryAuditEvents(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.QueryAuditEventsReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "查询参数不合法"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.QueryAuditEvents(ctx, &loginServiceV1.QueryAuditEventsMessage{
+		From:    req.From,
+		To:      req.To,
+		UserId:  req.UserId,
+		Type:    req.Type,
+		Outcome: req.Outcome,
+		Limit:   req.Limit,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 QueryAuditEvents 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	events := make([]userModel.AuditEvent, 0, len(rsp.Events))
+	for _, e := range rsp.Events {
+		events = append(events, userModel.AuditEvent{
+			Id:         e.Id,
+			Time:       e.Time,
+			Type:       e.Type,
+			Outcome:    e.Outcome,
+			ActorId:    e.ActorId,
+			TargetId:   e.TargetId,
+			Account:    e.Account,
+			ClientId:   e.ClientId,
+			DeviceId:   e.DeviceId,
+			Ip:         e.Ip,
+			UserAgent:  e.UserAgent,
+			ReasonCode: e.ReasonCode,
+			Reason:     e.Reason,
+		})
+	}
+	ctx.JSON(http.StatusOK, result.Success(events))
+}
+
+// listUserRoles 查询账号的全部角色，需要 roles:manage 权限
+// [GET] /project/admin/roles
+func (h *HandlerUser) listUserRoles(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ListUserRolesReq
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "账号 ID 不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.ListUserRoles(ctx, &loginServiceV1.ListUserRolesMessage{UserId: req.UserId})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListUserRoles 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	roles := make([]userModel.UserRole, 0, len(rsp.Roles))
+	for _, r := range rsp.Roles {
+		roles = append(roles, toUserRole(r))
+	}
+	ctx.JSON(http.StatusOK, result.Success(roles))
+}
+
+// assignRole 为账号分配角色，操作者需在该范围内拥有角色的全部权限；新角色在目标账号刷新令牌后生效
+// 租户管理员只能分配本租户范围内的角色，由 auth-service 根据请求中的范围检查
+// [POST] /project/admin/roles/assign
+func (h *HandlerUser) assignRole(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.UserRoleReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "账号 ID 和角色不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.AssignRole(ctx, &loginServiceV1.AssignRoleMessage{
+		UserId: req.UserId,
+		Role:   req.Role,
+		Scope:  req.Scope,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 AssignRole 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toUserRole(rsp.Role)))
+}
+
+// revokeRole 收回账号的角色，权限要求与分配相同
+// [POST] /project/admin/roles/revoke
+func (h *HandlerUser) revokeRole(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.UserRoleReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "账号 ID 和角色不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.RevokeRole(ctx, &loginServiceV1.RevokeRoleMessage{
+		UserId: req.UserId,
+		Role:   req.Role,
+		Scope:  req.Scope,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RevokeRole 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+func toUserRole(r *loginServiceV1.UserRoleMessage) userModel.UserRole {
+	return userModel.UserRole{
+		UserId:     r.UserId,
+		Role:       r.Role,
+		Scope:      r.Scope,
+		GrantedBy:  r.GrantedBy,
+		CreateTime: r.CreateTime,
+	}
+}
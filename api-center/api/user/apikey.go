@@ -0,0 +1,213 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// createApiKey 为自己或自己的服务账号创建 API Key，完整的 Key 只在本次响应中返回
+// [POST] /project/apikeys
+func (h *HandlerUser) createApiKey(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ApiKeyReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "名称和授权范围不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.CreateApiKey(ctx, &loginServiceV1.CreateApiKeyMessage{
+		UserId:    auth.UserId(ctx),
+		AccountId: req.AccountId,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpireIn:  req.ExpireIn,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 CreateApiKey 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	key := toApiKey(rsp.Key)
+	key.Key = rsp.Secret
+	ctx.JSON(http.StatusOK, result.Success(key))
+}
+
+// listApiKeys 列出自己或自己的服务账号的 API Key，包括已吊销和已过期的
+// [GET] /project/apikeys
+func (h *HandlerUser) listApiKeys(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ListApiKeysReq
+	_ = ctx.ShouldBindQuery(&req)
+
+	rsp, err := LoginServiceClient.ListApiKeys(ctx, &loginServiceV1.ListApiKeysMessage{
+		UserId:    auth.UserId(ctx),
+		AccountId: req.AccountId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListApiKeys 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	keys := make([]userModel.ApiKey, 0, len(rsp.Keys))
+	for _, k := range rsp.Keys {
+		keys = append(keys, toApiKey(k))
+	}
+	ctx.JSON(http.StatusOK, result.Success(keys))
+}
+
+// rotateApiKey 轮换 API Key，旧的 Key 立即失效
+// [POST] /project/apikeys/rotate
+func (h *HandlerUser) rotateApiKey(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ApiKeyPrefixReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "Key 前缀不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.RotateApiKey(ctx, &loginServiceV1.ApiKeyPrefixMessage{
+		UserId: auth.UserId(ctx),
+		Prefix: req.Prefix,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RotateApiKey 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	key := toApiKey(rsp.Key)
+	key.Key = rsp.Secret
+	ctx.JSON(http.StatusOK, result.Success(key))
+}
+
+// revokeApiKey 吊销 API Key
+// [POST] /project/apikeys/revoke
+func (h *HandlerUser) revokeApiKey(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ApiKeyPrefixReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "Key 前缀不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.RevokeApiKey(ctx, &loginServiceV1.ApiKeyPrefixMessage{
+		UserId: auth.UserId(ctx),
+		Prefix: req.Prefix,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RevokeApiKey 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// createServiceAccount 创建服务账号，之后通过 /project/apikeys 为其创建 API Key
+// [POST] /project/serviceAccounts
+func (h *HandlerUser) createServiceAccount(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ServiceAccountReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "名称不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.CreateServiceAccount(ctx, &loginServiceV1.CreateServiceAccountMessage{
+		UserId: auth.UserId(ctx),
+		Name:   req.Name,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 CreateServiceAccount 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toServiceAccount(rsp.Account)))
+}
+
+// listServiceAccounts 列出自己创建的服务账号
+// [GET] /project/serviceAccounts
+func (h *HandlerUser) listServiceAccounts(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListServiceAccounts(ctx, &loginServiceV1.ListServiceAccountsMessage{
+		UserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListServiceAccounts 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	accounts := make([]userModel.ServiceAccount, 0, len(rsp.Accounts))
+	for _, a := range rsp.Accounts {
+		accounts = append(accounts, toServiceAccount(a))
+	}
+	ctx.JSON(http.StatusOK, result.Success(accounts))
+}
+
+// deleteServiceAccount 注销服务账号，它的 API Key 一并吊销
+// [POST] /project/serviceAccounts/delete
+func (h *HandlerUser) deleteServiceAccount(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ServiceAccountIdReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "服务账号 ID 不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.DeleteServiceAccount(ctx, &loginServiceV1.DeleteServiceAccountMessage{
+		UserId:    auth.UserId(ctx),
+		AccountId: req.AccountId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 DeleteServiceAccount 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+func toApiKey(k *loginServiceV1.ApiKeyMessage) userModel.ApiKey {
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return userModel.ApiKey{
+		Prefix:       k.Prefix,
+		OwnerId:      k.OwnerId,
+		Name:         k.Name,
+		Scopes:       scopes,
+		ExpireTime:   k.ExpireTime,
+		LastUsedTime: k.LastUsedTime,
+		LastUsedIp:   k.LastUsedIp,
+		CreateTime:   k.CreateTime,
+		RotateTime:   k.RotateTime,
+		RevokeTime:   k.RevokeTime,
+	}
+}
+
+func toServiceAccount(a *loginServiceV1.ServiceAccountMessage) userModel.ServiceAccount {
+	return userModel.ServiceAccount{
+		Id:         a.Id,
+		Name:       a.Name,
+		CreateTime: a.CreateTime,
+	}
+}
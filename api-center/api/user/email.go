@@ -0,0 +1,49 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// bindEmail 通过邮件验证码绑定或更换邮箱，绑定后可用于找回密码
+// [POST] /project/email/bind
+func (h *HandlerUser) bindEmail(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.BindEmailReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "邮箱和验证码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.BindEmail(ctx, &loginServiceV1.BindEmailMessage{
+		UserId:  auth.UserId(ctx),
+		Email:   req.Email,
+		Captcha: req.Captcha,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 BindEmail 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toMember(rsp.Member)))
+}
+
+// locale 邮件语言，优先取表单参数，其次取 Accept-Language 中的首选语言
+func locale(ctx *gin.Context) string {
+	if l := ctx.PostForm("locale"); l != "" {
+		return l
+	}
+	first, _, _ := strings.Cut(ctx.GetHeader("Accept-Language"), ",")
+	tag, _, _ := strings.Cut(first, ";")
+	return strings.TrimSpace(tag)
+}
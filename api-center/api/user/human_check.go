@@ -0,0 +1,77 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// fingerprintHeader 客户端上报设备指纹的请求头，用于人机验证的风险评估
+const fingerprintHeader = "X-Device-Fingerprint"
+
+// createHumanCheck 获取人机验证题，风险越高的客户端验证越严格
+// [POST] /project/login/humanCheck
+func (h *HandlerUser) createHumanCheck(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.CreateHumanCheck(ctx, &loginServiceV1.HumanCheckMessage{
+		Ip:          ctx.ClientIP(),
+		Fingerprint: fingerprint(ctx),
+		UserAgent:   ctx.GetHeader("User-Agent"),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 CreateHumanCheck 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.HumanCheck{
+		Id:       rsp.Id,
+		Kind:     rsp.Kind,
+		Image:    rsp.Image,
+		Piece:    rsp.Piece,
+		PieceY:   rsp.PieceY,
+		Width:    rsp.Width,
+		Height:   rsp.Height,
+		ExpireAt: rsp.ExpireAt,
+	}))
+}
+
+// verifyHumanCheck 提交人机验证答案，通过后返回获取短信验证码所需的凭证
+// [POST] /project/login/humanCheck/verify
+func (h *HandlerUser) verifyHumanCheck(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.VerifyHumanCheckReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "验证 ID 和答案不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.VerifyHumanCheck(ctx, &loginServiceV1.VerifyHumanCheckMessage{
+		Id:          req.Id,
+		Answer:      req.Answer,
+		Ip:          ctx.ClientIP(),
+		Fingerprint: fingerprint(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 VerifyHumanCheck 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.HumanCheckPass{Ticket: rsp.Ticket, ExpireAt: rsp.ExpireAt}))
+}
+
+// fingerprint 客户端设备指纹，优先取请求头，其次取表单参数
+func fingerprint(ctx *gin.Context) string {
+	if fp := ctx.GetHeader(fingerprintHeader); fp != "" {
+		return fp
+	}
+	return ctx.PostForm("fingerprint")
+}
@@ -0,0 +1,393 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// oauthErrors auth-service 的 OAuth2 错误码 -> RFC 6749 错误类型
+var oauthErrors = map[int]string{
+	2601: "invalid_client",
+	2602: "invalid_grant",
+	2603: "invalid_scope",
+	2604: "unsupported_grant_type",
+	2605: "invalid_request",
+	2606: "unauthorized_client",
+	2607: "access_denied",
+	2608: "invalid_request",
+	2609: "invalid_client",
+}
+
+// oauthToken 令牌端点，供第三方应用用授权码、客户端凭证或刷新令牌换取访问令牌
+// 请求和响应遵循 RFC 6749，不使用统一的 HttpResult 包装
+// [POST] /oauth/token
+func (h *HandlerUser) oauthToken(ctx *gin.Context) {
+	var req userModel.OAuthTokenReq
+	_ = ctx.ShouldBind(&req)
+	clientId, clientSecret, ok := clientCredentials(ctx, req.ClientId, req.ClientSecret)
+	if !ok {
+		oauthFailed(ctx, 2605, "客户端凭证只能通过一种方式传递")
+		return
+	}
+	if req.GrantType == "" {
+		oauthFailed(ctx, 2605, "缺少 grant_type")
+		return
+	}
+
+	rsp, err := LoginServiceClient.OAuthToken(ctx, &loginServiceV1.OAuthTokenMessage{
+		GrantType:    req.GrantType,
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Code:         req.Code,
+		RedirectUri:  req.RedirectUri,
+		CodeVerifier: req.CodeVerifier,
+		RefreshToken: req.RefreshToken,
+		Scope:        req.Scope,
+	})
+	if err != nil {
+		code, msg := libErrors.ParseGrpcError(err)
+		if _, known := oauthErrors[code]; !known {
+			libLog.IMLog.Error(fmt.Sprintf("调用 OAuthToken 出错：%v", err))
+		}
+		oauthFailed(ctx, code, msg)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+	ctx.JSON(http.StatusOK, userModel.OAuthTokenRsp{
+		AccessToken:  rsp.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(time.UnixMilli(rsp.AccessTokenExp)).Seconds()),
+		RefreshToken: rsp.RefreshToken,
+		Scope:        rsp.Scope,
+	})
+}
+
+// oauthIntrospect 令牌内省端点（RFC 7662），第三方应用只能查询自己获得的令牌
+// [POST] /oauth/introspect
+func (h *HandlerUser) oauthIntrospect(ctx *gin.Context) {
+	var req userModel.OAuthIntrospectReq
+	_ = ctx.ShouldBind(&req)
+	clientId, clientSecret, ok := clientCredentials(ctx, req.ClientId, req.ClientSecret)
+	if !ok {
+		oauthFailed(ctx, 2605, "客户端凭证只能通过一种方式传递")
+		return
+	}
+	if req.Token == "" {
+		oauthFailed(ctx, 2605, "缺少 token")
+		return
+	}
+
+	rsp, err := LoginServiceClient.IntrospectToken(ctx, &loginServiceV1.IntrospectTokenMessage{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Token:        req.Token,
+	})
+	if err != nil {
+		code, msg := libErrors.ParseGrpcError(err)
+		if _, known := oauthErrors[code]; !known {
+			libLog.IMLog.Error(fmt.Sprintf("调用 IntrospectToken 出错：%v", err))
+		}
+		oauthFailed(ctx, code, msg)
+		return
+	}
+	res := userModel.OAuthIntrospectRsp{Active: rsp.Active}
+	if rsp.Active {
+		res.ClientId, res.Scope, res.TokenType, res.Jti = rsp.ClientId, rsp.Scope, rsp.TokenType, rsp.TokenId
+		res.Iat, res.Exp = rsp.IssuedAt/1000, rsp.ExpireAt/1000
+		if rsp.UserId != 0 {
+			res.Sub = strconv.FormatInt(rsp.UserId, 10)
+		}
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, res)
+}
+
+// oauthMe 当前令牌或 API Key 代表的调用方，第三方应用和脚本可用于确认账号和授权范围
+// [GET] /oauth/me
+func (h *HandlerUser) oauthMe(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	id := auth.Current(ctx)
+	scopes := id.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.OAuthMe{
+		UserId:   id.UserId,
+		ClientId: id.ClientId,
+		KeyId:    id.KeyId,
+		Scopes:   scopes,
+		ExpireAt: id.ExpireAt,
+	}))
+}
+
+// getOAuthConsent 授权页：校验第三方应用的授权请求，返回应用名称和申请的授权范围
+// [GET] /project/oauth/authorize
+func (h *HandlerUser) getOAuthConsent(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthAuthorizeReq
+	_ = ctx.ShouldBindQuery(&req)
+	if req.ClientId == "" || req.ResponseType != "code" {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "client_id 不能为空且 response_type 须为 code"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.GetOAuthConsent(ctx, toOAuthAuthorizeMessage(auth.UserId(ctx), &req))
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 GetOAuthConsent 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	info := userModel.OAuthConsentInfo{
+		ClientId:    rsp.ClientId,
+		ClientName:  rsp.ClientName,
+		RedirectUri: rsp.RedirectUri,
+		NeedConsent: rsp.NeedConsent,
+		Scopes:      make([]userModel.OAuthScope, 0, len(rsp.Scopes)),
+	}
+	for _, s := range rsp.Scopes {
+		info.Scopes = append(info.Scopes, userModel.OAuthScope{Scope: s.Scope, Description: s.Description, Granted: s.Granted})
+	}
+	ctx.JSON(http.StatusOK, result.Success(info))
+}
+
+// approveOAuthConsent 用户确认或拒绝授权，返回回到第三方应用的地址（同意时携带授权码）
+// [POST] /project/oauth/authorize
+func (h *HandlerUser) approveOAuthConsent(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthApproveReq
+	if err := ctx.ShouldBind(&req); err != nil || req.ClientId == "" || req.ResponseType != "code" {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "client_id 不能为空且 response_type 须为 code"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.ApproveOAuthConsent(ctx, &loginServiceV1.ApproveOAuthConsentMessage{
+		Request:  toOAuthAuthorizeMessage(auth.UserId(ctx), &req.OAuthAuthorizeReq),
+		Approved: req.Approved,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ApproveOAuthConsent 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.OAuthApproveRsp{RedirectUrl: rsp.RedirectUrl}))
+}
+
+// registerOAuthClient 注册第三方应用，客户端密钥只在本次响应中返回
+// [POST] /project/oauth/clients
+func (h *HandlerUser) registerOAuthClient(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthClientReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "应用名称和授权范围不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.RegisterOAuthClient(ctx, &loginServiceV1.RegisterOAuthClientMessage{
+		UserId:       auth.UserId(ctx),
+		Name:         req.Name,
+		RedirectUris: req.RedirectUris,
+		Scopes:       req.Scopes,
+		GrantTypes:   req.GrantTypes,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RegisterOAuthClient 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	client := toOAuthClient(rsp.Client)
+	client.ClientSecret = rsp.ClientSecret
+	ctx.JSON(http.StatusOK, result.Success(client))
+}
+
+// listOAuthClients 列出账号注册的第三方应用
+// [GET] /project/oauth/clients
+func (h *HandlerUser) listOAuthClients(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListOAuthClients(ctx, &loginServiceV1.ListOAuthClientsMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListOAuthClients 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	clients := make([]userModel.OAuthClient, 0, len(rsp.Clients))
+	for _, c := range rsp.Clients {
+		clients = append(clients, toOAuthClient(c))
+	}
+	ctx.JSON(http.StatusOK, result.Success(clients))
+}
+
+// rotateOAuthClientSecret 重置应用的客户端密钥，旧密钥立即失效
+// [POST] /project/oauth/clients/rotate
+func (h *HandlerUser) rotateOAuthClientSecret(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthClientIdReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "客户端 ID 不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.RotateOAuthClientSecret(ctx, &loginServiceV1.OAuthClientIdMessage{
+		UserId:   auth.UserId(ctx),
+		ClientId: req.ClientId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RotateOAuthClientSecret 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.OAuthClient{ClientId: req.ClientId, ClientSecret: rsp.ClientSecret}))
+}
+
+// deleteOAuthClient 删除应用，所有用户的授权和已签发的令牌一并失效
+// [POST] /project/oauth/clients/delete
+func (h *HandlerUser) deleteOAuthClient(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthClientIdReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "客户端 ID 不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.DeleteOAuthClient(ctx, &loginServiceV1.OAuthClientIdMessage{
+		UserId:   auth.UserId(ctx),
+		ClientId: req.ClientId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 DeleteOAuthClient 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// listOAuthConsents 列出账号授权过的第三方应用
+// [GET] /project/oauth/consents
+func (h *HandlerUser) listOAuthConsents(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListOAuthConsents(ctx, &loginServiceV1.ListOAuthConsentsMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListOAuthConsents 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	consents := make([]userModel.OAuthConsent, 0, len(rsp.Consents))
+	for _, c := range rsp.Consents {
+		consents = append(consents, userModel.OAuthConsent{
+			ClientId:   c.ClientId,
+			ClientName: c.ClientName,
+			Scopes:     c.Scopes,
+			CreateTime: c.CreateTime,
+			UpdateTime: c.UpdateTime,
+		})
+	}
+	ctx.JSON(http.StatusOK, result.Success(consents))
+}
+
+// revokeOAuthConsent 撤销对第三方应用的授权，该应用持有的令牌全部失效
+// [POST] /project/oauth/consents/revoke
+func (h *HandlerUser) revokeOAuthConsent(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OAuthClientIdReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "客户端 ID 不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.RevokeOAuthConsent(ctx, &loginServiceV1.OAuthClientIdMessage{
+		UserId:   auth.UserId(ctx),
+		ClientId: req.ClientId,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RevokeOAuthConsent 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// clientCredentials 从 HTTP Basic 或请求体中取客户端凭证，两种方式同时使用时返回 false（RFC 6749 2.3.1）
+func clientCredentials(ctx *gin.Context, formId, formSecret string) (string, string, bool) {
+	id, secret, basic := ctx.Request.BasicAuth()
+	if !basic {
+		return formId, formSecret, true
+	}
+	if formSecret != "" {
+		return "", "", false
+	}
+	// Basic 认证中的凭证先经过 application/x-www-form-urlencoded 编码
+	if v, err := url.QueryUnescape(id); err == nil {
+		id = v
+	}
+	if v, err := url.QueryUnescape(secret); err == nil {
+		secret = v
+	}
+	return id, secret, true
+}
+
+// oauthFailed 按 RFC 6749 5.2 返回错误，客户端认证失败为 401，其余业务错误为 400
+func oauthFailed(ctx *gin.Context, code int, msg string) {
+	ctx.Header("Cache-Control", "no-store")
+	errType, ok := oauthErrors[code]
+	switch {
+	case !ok && code < 1000:
+		ctx.JSON(http.StatusServiceUnavailable, userModel.OAuthErrorRsp{Error: "temporarily_unavailable", ErrorDescription: "认证服务不可用"})
+	case !ok:
+		ctx.JSON(http.StatusInternalServerError, userModel.OAuthErrorRsp{Error: "server_error", ErrorDescription: msg})
+	case errType == "invalid_client":
+		ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		ctx.JSON(http.StatusUnauthorized, userModel.OAuthErrorRsp{Error: errType, ErrorDescription: msg})
+	default:
+		ctx.JSON(http.StatusBadRequest, userModel.OAuthErrorRsp{Error: errType, ErrorDescription: msg})
+	}
+}
+
+func toOAuthAuthorizeMessage(userId int64, req *userModel.OAuthAuthorizeReq) *loginServiceV1.OAuthAuthorizeMessage {
+	return &loginServiceV1.OAuthAuthorizeMessage{
+		UserId:              userId,
+		ClientId:            req.ClientId,
+		RedirectUri:         req.RedirectUri,
+		Scope:               req.Scope,
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}
+}
+
+func toOAuthClient(c *loginServiceV1.OAuthClientMessage) userModel.OAuthClient {
+	return userModel.OAuthClient{
+		ClientId:     c.ClientId,
+		Name:         c.Name,
+		RedirectUris: c.RedirectUris,
+		Scopes:       c.Scopes,
+		GrantTypes:   c.GrantTypes,
+		CreateTime:   c.CreateTime,
+	}
+}
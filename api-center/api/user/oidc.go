@@ -0,0 +1,177 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const (
+	oidcBindingCookie = "oidc_binding"        // 发起第三方登录或绑定的浏览器绑定值
+	oidcBindingPath   = "/project/login/oidc" // 只在回调时携带
+)
+
+// setOidcBinding 写入或清除浏览器绑定 cookie；身份提供方以顶级跳转回调，SameSite 取 Lax 才能带上
+func setOidcBinding(ctx *gin.Context, binding string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcBindingCookie, binding, maxAge, oidcBindingPath, "", ctx.Request.TLS != nil, true)
+}
+
+// listOidcProviders 列出可用的第三方登录方式
+// [GET] /project/login/oidc/providers
+func (h *HandlerUser) listOidcProviders(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListOidcProviders(ctx, &loginServiceV1.ListOidcProvidersMessage{})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListOidcProviders 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	providers := make([]userModel.OidcProvider, 0, len(rsp.Providers))
+	for _, p := range rsp.Providers {
+		providers = append(providers, userModel.OidcProvider{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	ctx.JSON(http.StatusOK, result.Success(providers))
+}
+
+// oidcAuthorize 发起第三方登录，重定向到身份提供方的授权页
+// [GET] /project/login/oidc/:provider
+func (h *HandlerUser) oidcAuthorize(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OidcAuthorizeReq
+	_ = ctx.ShouldBindQuery(&req)
+
+	rsp, err := LoginServiceClient.OidcAuthorize(ctx, &loginServiceV1.OidcAuthorizeMessage{
+		Provider:   ctx.Param("provider"),
+		DeviceId:   req.DeviceId,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 OidcAuthorize 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	setOidcBinding(ctx, rsp.Binding, 0)
+	ctx.Redirect(http.StatusFound, rsp.AuthorizeUrl)
+}
+
+// oidcCallback 身份提供方授权后的回调，完成登录或绑定
+// [GET] /project/login/oidc/:provider/callback
+func (h *HandlerUser) oidcCallback(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OidcCallbackReq
+	_ = ctx.ShouldBindQuery(&req)
+	if req.State == "" || (req.Code == "" && req.Error == "") {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "回调参数不完整"))
+		return
+	}
+
+	// 绑定值只用一次，无论回调结果如何都清除
+	binding, _ := ctx.Cookie(oidcBindingCookie)
+	setOidcBinding(ctx, "", -1)
+	rsp, err := LoginServiceClient.OidcCallback(ctx, &loginServiceV1.OidcCallbackMessage{
+		Provider: ctx.Param("provider"),
+		State:    req.State,
+		Code:     req.Code,
+		Error:    req.Error,
+		Ip:       ctx.ClientIP(),
+		Binding:  binding,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 OidcCallback 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	res := userModel.OidcCallbackRsp{Provider: rsp.Provider, Bound: rsp.Bound}
+	if rsp.Login != nil {
+		login := toLoginRsp(rsp.Login)
+		res.Login = &login
+	}
+	ctx.JSON(http.StatusOK, result.Success(res))
+}
+
+// bindOidc 已登录账号绑定第三方账号，返回授权地址，授权完成后由回调接口完成绑定
+// [POST] /project/oidc/bind
+func (h *HandlerUser) bindOidc(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OidcProviderReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "身份提供方不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.OidcAuthorize(ctx, &loginServiceV1.OidcAuthorizeMessage{
+		Provider:   req.Provider,
+		BindUserId: auth.UserId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 OidcAuthorize 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	setOidcBinding(ctx, rsp.Binding, 0)
+	ctx.JSON(http.StatusOK, result.Success(userModel.OidcBindRsp{AuthorizeUrl: rsp.AuthorizeUrl}))
+}
+
+// listIdentities 列出账号关联的第三方账号
+// [GET] /project/oidc/identities
+func (h *HandlerUser) listIdentities(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListIdentities(ctx, &loginServiceV1.ListIdentitiesMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListIdentities 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	identities := make([]userModel.Identity, 0, len(rsp.Identities))
+	for _, i := range rsp.Identities {
+		identities = append(identities, userModel.Identity{
+			Provider:   i.Provider,
+			Subject:    i.Subject,
+			Email:      i.Email,
+			CreateTime: i.CreateTime,
+		})
+	}
+	ctx.JSON(http.StatusOK, result.Success(identities))
+}
+
+// unlinkIdentity 解除账号与第三方账号的关联
+// [POST] /project/oidc/unlink
+func (h *HandlerUser) unlinkIdentity(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.OidcProviderReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "身份提供方不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.UnlinkIdentity(ctx, &loginServiceV1.UnlinkIdentityMessage{
+		UserId:   auth.UserId(ctx),
+		Provider: req.Provider,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 UnlinkIdentity 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
@@ -1,7 +1,10 @@
 package user
 
 import (
+	"github.com/MortalSC/IM-System/api-center/config"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
 	"github.com/MortalSC/IM-System/api-center/internal/router"
+	"github.com/MortalSC/IM-System/lib/rbac"
 	"github.com/gin-gonic/gin"
 	"log"
 )
@@ -20,7 +23,92 @@ type RouterUser struct {
 func (ru *RouterUser) Router(r *gin.Engine) {
 	// 初始化grpc客户端连接
 	InitRpcUserClient()
+	// 令牌由 auth-service 签发，认证中间件使用同一个客户端校验
+	auth.SetVerifier(auth.NewVerifier(config.Cfg.AuthCfg.Mode, config.Cfg.AuthCfg.KeyRefresh, LoginServiceClient))
+	auth.SetApiKeyVerifier(auth.NewApiKeyVerifier(LoginServiceClient))
+	// 路由通过 auth.Require 声明所需权限，令牌中的角色按该策略展开
+	policy, err := rbac.NewPolicy(config.Cfg.RbacCfg.Roles)
+	if err != nil {
+		log.Fatalf("rbac policy: %v", err)
+	}
+	auth.SetPolicy(policy)
 
 	h := New()
-	r.POST("/project/login/getCaptcha", h.getCaptcha)
+	public := router.Public(r, "/project/login")
+	public.POST("/humanCheck", h.createHumanCheck)
+	public.POST("/humanCheck/verify", h.verifyHumanCheck)
+	public.POST("/getCaptcha", h.getCaptcha)
+	public.POST("", h.login)
+	public.POST("/register", h.register)
+	public.POST("/password", h.passwordLogin)
+	public.POST("/resetPassword", h.resetPassword)
+	public.POST("/2fa", h.verifyTwoFactor)
+	public.POST("/refreshToken", h.refreshToken)
+	public.GET("/oidc/providers", h.listOidcProviders)
+	public.GET("/oidc/:provider", h.oidcAuthorize)
+	public.GET("/oidc/:provider/callback", h.oidcCallback)
+
+	authed := router.Authenticated(r, "/project")
+	authed.POST("/logout", h.logout)
+
+	email := router.Authenticated(r, "/project/email")
+	email.POST("/bind", h.bindEmail)
+
+	// 冷静期内重新登录后可撤销注销
+	account := router.Authenticated(r, "/project/account")
+	account.POST("/mobile", h.changeMobile)
+	account.GET("/export", h.exportAccountData)
+	account.POST("/delete", h.deleteAccount)
+	account.POST("/delete/cancel", h.cancelAccountDeletion)
+
+	sessions := router.Authenticated(r, "/project/session")
+	sessions.GET("/list", h.listSessions)
+	sessions.POST("/kick", h.kickDevice)
+	sessions.POST("/logoutAll", h.logoutAll)
+
+	twoFactor := router.Authenticated(r, "/project/2fa")
+	twoFactor.POST("/totp/enroll", h.enrollTotp)
+	twoFactor.POST("/totp/confirm", h.confirmTotp)
+	twoFactor.POST("/totp/disable", h.disableTotp)
+
+	identities := router.Authenticated(r, "/project/oidc")
+	identities.POST("/bind", h.bindOidc)
+	identities.GET("/identities", h.listIdentities)
+	identities.POST("/unlink", h.unlinkIdentity)
+
+	// 开放平台：令牌端点和内省端点由第三方应用以客户端凭证调用
+	oauthPublic := router.Public(r, "/oauth")
+	oauthPublic.POST("/token", h.oauthToken)
+	oauthPublic.POST("/introspect", h.oauthIntrospect)
+	oauthScoped := router.Scoped(r, "/oauth")
+	oauthScoped.GET("/me", h.oauthMe)
+
+	oauthAuthed := router.Authenticated(r, "/project/oauth")
+	oauthAuthed.GET("/authorize", h.getOAuthConsent)
+	oauthAuthed.POST("/authorize", h.approveOAuthConsent)
+	oauthAuthed.POST("/clients", h.registerOAuthClient)
+	oauthAuthed.GET("/clients", h.listOAuthClients)
+	oauthAuthed.POST("/clients/rotate", h.rotateOAuthClientSecret)
+	oauthAuthed.POST("/clients/delete", h.deleteOAuthClient)
+	oauthAuthed.GET("/consents", h.listOAuthConsents)
+	oauthAuthed.POST("/consents/revoke", h.revokeOAuthConsent)
+
+	// API Key 和服务账号只能用登录令牌管理，API Key 不能管理自己
+	apiKeys := router.Authenticated(r, "/project/apikeys")
+	apiKeys.POST("", h.createApiKey)
+	apiKeys.GET("", h.listApiKeys)
+	apiKeys.POST("/rotate", h.rotateApiKey)
+	apiKeys.POST("/revoke", h.revokeApiKey)
+
+	serviceAccounts := router.Authenticated(r, "/project/serviceAccounts")
+	serviceAccounts.POST("", h.createServiceAccount)
+	serviceAccounts.GET("", h.listServiceAccounts)
+	serviceAccounts.POST("/delete", h.deleteServiceAccount)
+
+	// 管理后台：auth-service 同样会根据透传的访问令牌检查权限
+	admin := router.Authenticated(r, "/project/admin")
+	admin.GET("/audit", auth.Require("audit:read"), h.queryAuditEvents)
+	admin.GET("/roles", auth.Require("roles:manage"), h.listUserRoles)
+	admin.POST("/roles/assign", auth.RequireAny("roles:manage"), h.assignRole)
+	admin.POST("/roles/revoke", auth.RequireAny("roles:manage"), h.revokeRole)
 }
@@ -1,18 +1,48 @@
 package user
 
 import (
+	"context"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
 	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	"github.com/MortalSC/IM-System/lib/rbac"
+	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"log"
 )
 
+// 与 auth-service 审计日志读取的 metadata 保持一致
+const (
+	metadataClientIp        = "x-client-ip"
+	metadataClientUserAgent = "x-client-user-agent"
+)
+
 var LoginServiceClient loginServiceV1.LoginServiceClient
 
 func InitRpcUserClient() {
-	conn, err := grpc.Dial("127.0.0.1:8881", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial("127.0.0.1:8881",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(forwardClientInfo),
+	)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
 	LoginServiceClient = loginServiceV1.NewLoginServiceClient(conn)
 }
+
+// forwardClientInfo 处理函数直接以 gin.Context 发起调用时，将终端用户的 IP 和 User-Agent 透传给 auth-service 用于审计
+// 以访问令牌认证的请求同时透传令牌，auth-service 据此检查管理类方法的权限
+func forwardClientInfo(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if c, ok := ctx.(*gin.Context); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			metadataClientIp, c.ClientIP(),
+			metadataClientUserAgent, c.Request.UserAgent(),
+		)
+		if id := auth.Current(c); id != nil && id.Token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, rbac.MetadataAuthorization, "Bearer "+id.Token)
+		}
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// enrollTotp 绑定验证器：生成 TOTP 密钥和 otpauth 地址，需调用 confirm 校验一次动态码后才启用
+// [POST] /project/2fa/totp/enroll
+func (h *HandlerUser) enrollTotp(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.EnrollTotp(ctx, &loginServiceV1.EnrollTotpMessage{UserId: auth.UserId(ctx)})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 EnrollTotp 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.EnrollTotpRsp{Secret: rsp.Secret, OtpauthUri: rsp.OtpauthUri}))
+}
+
+// confirmTotp 校验动态码后启用两步验证，返回恢复码
+// [POST] /project/2fa/totp/confirm
+func (h *HandlerUser) confirmTotp(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.TotpCodeReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "验证码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.ConfirmTotp(ctx, &loginServiceV1.ConfirmTotpMessage{UserId: auth.UserId(ctx), Code: req.Code})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ConfirmTotp 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.ConfirmTotpRsp{RecoveryCodes: rsp.RecoveryCodes}))
+}
+
+// disableTotp 校验动态码或恢复码后关闭两步验证
+// [POST] /project/2fa/totp/disable
+func (h *HandlerUser) disableTotp(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.TotpCodeReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "验证码不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.DisableTotp(ctx, &loginServiceV1.DisableTotpMessage{UserId: auth.UserId(ctx), Code: req.Code})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 DisableTotp 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
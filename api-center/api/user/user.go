@@ -2,13 +2,19 @@ package user
 
 import (
 	"fmt"
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
 	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	userModel "github.com/MortalSC/IM-System/api-center/pkg/model/user"
 	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
 	libLog "github.com/MortalSC/IM-System/lib/log"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
+// 与 auth-service 的会话错误码保持一致
+const codeSessionNotExist = 2201
+
 // HandlerUser 是用户业务处理器结构体
 type HandlerUser struct {
 }
@@ -17,24 +23,345 @@ func New() *HandlerUser {
 	return &HandlerUser{}
 }
 
-// getCaptcha 是一个用于获取验证码的 HTTP API 实现
+// getCaptcha 是一个用于获取验证码的 HTTP API 实现，需携带人机验证通过凭证 ticket
+// channel 为 email 时向 email 发送邮件验证码，邮件语言取 locale，未填写时取 Accept-Language；否则向 mobile 发送短信验证码
 // [POST] /project/login/getCaptcha
 func (h *HandlerUser) getCaptcha(ctx *gin.Context) {
 	result := model.HttpResult{}
 
+	channel := ctx.PostForm("channel")
 	mobile := ctx.PostForm("mobile")
+	email := ctx.PostForm("email")
 
-	if mobile == "" {
+	if channel == "email" && email == "" {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "邮箱不能为空"))
+		return
+	}
+	if channel != "email" && mobile == "" {
 		ctx.JSON(http.StatusOK, result.Failed(2002, "手机号不能为空"))
 		return
 	}
 
-	_, err := LoginServiceClient.GetCaptcha(ctx, &loginServiceV1.CaptchaMessage{Mobile: mobile})
+	rsp, err := LoginServiceClient.GetCaptcha(ctx, &loginServiceV1.CaptchaMessage{
+		Mobile:      mobile,
+		Ip:          ctx.ClientIP(),
+		Ticket:      ctx.PostForm("ticket"),
+		Fingerprint: fingerprint(ctx),
+		Channel:     channel,
+		Email:       email,
+		Locale:      locale(ctx),
+	})
 	if err != nil {
 		libLog.IMLog.Error(fmt.Sprintf("调用 GetCaptcha 出错：%v", err))
-		ctx.JSON(http.StatusOK, result.Failed(2001, err.Error()))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	// 验证码仅在 auth-service 开发模式下返回，其余情况为空
+	ctx.JSON(http.StatusOK, result.Success(rsp.Code))
+
+}
+
+// login 使用手机号 + 验证码登录
+// [POST] /project/login
+func (h *HandlerUser) login(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.LoginReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "手机号和验证码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.Login(ctx, &loginServiceV1.LoginMessage{
+		Mobile:     req.Mobile,
+		Captcha:    req.Captcha,
+		Ip:         ctx.ClientIP(),
+		DeviceId:   req.DeviceId,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Login 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Success(toLoginRsp(rsp)))
+}
+
+// register 使用用户名 + 密码注册，需验证手机号或邮箱（未填写手机号时），注册成功后直接登录
+// [POST] /project/login/register
+func (h *HandlerUser) register(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.RegisterReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "用户名、验证码和密码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.Register(ctx, &loginServiceV1.RegisterMessage{
+		Username:   req.Username,
+		Email:      req.Email,
+		Mobile:     req.Mobile,
+		Captcha:    req.Captcha,
+		Password:   req.Password,
+		Ip:         ctx.ClientIP(),
+		DeviceId:   req.DeviceId,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 Register 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toLoginRsp(rsp)))
+}
+
+// passwordLogin 使用用户名、邮箱或手机号 + 密码登录
+// [POST] /project/login/password
+func (h *HandlerUser) passwordLogin(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.PasswordLoginReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "账号和密码不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.PasswordLogin(ctx, &loginServiceV1.PasswordLoginMessage{
+		Account:    req.Account,
+		Password:   req.Password,
+		Ip:         ctx.ClientIP(),
+		DeviceId:   req.DeviceId,
+		DeviceName: req.DeviceName,
+		Platform:   req.Platform,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 PasswordLogin 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toLoginRsp(rsp)))
+}
+
+// resetPassword 通过手机验证码或已验证邮箱的验证码重置密码，验证码通过 /project/login/getCaptcha 获取
+// [POST] /project/login/resetPassword
+func (h *HandlerUser) resetPassword(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.ResetPasswordReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "验证码和新密码不能为空"))
+		return
+	}
+	if req.Mobile == "" && req.Email == "" {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "手机号和邮箱不能同时为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.ResetPassword(ctx, &loginServiceV1.ResetPasswordMessage{
+		Mobile:   req.Mobile,
+		Email:    req.Email,
+		Captcha:  req.Captcha,
+		Password: req.Password,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ResetPassword 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// verifyTwoFactor 已启用两步验证的账号登录第二步：校验挑战令牌和动态码（或恢复码）
+// [POST] /project/login/2fa
+func (h *HandlerUser) verifyTwoFactor(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.VerifyTwoFactorReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "挑战令牌和验证码不能为空"))
 		return
 	}
-	ctx.JSON(http.StatusOK, result.Success(result.Code))
 
+	rsp, err := LoginServiceClient.VerifyTwoFactor(ctx, &loginServiceV1.VerifyTwoFactorMessage{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 VerifyTwoFactor 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toLoginRsp(rsp)))
+}
+
+// refreshToken 使用刷新令牌换取新的令牌
+// [POST] /project/login/refreshToken
+func (h *HandlerUser) refreshToken(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.RefreshTokenReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "刷新令牌不能为空"))
+		return
+	}
+
+	rsp, err := LoginServiceClient.RefreshToken(ctx, &loginServiceV1.RefreshTokenMessage{RefreshToken: req.RefreshToken})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 RefreshToken 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(toTokenList(rsp)))
+}
+
+// logout 退出登录，下线当前设备，设备持有的访问令牌和刷新令牌一并失效
+// [POST] /project/logout
+func (h *HandlerUser) logout(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	id := auth.Current(ctx)
+	_, err := LoginServiceClient.KickDevice(ctx, &loginServiceV1.KickDeviceMessage{UserId: id.UserId, DeviceId: id.DeviceId})
+	if err != nil {
+		code, msg := libErrors.ParseGrpcError(err)
+		if code != codeSessionNotExist {
+			libLog.IMLog.Error(fmt.Sprintf("调用 KickDevice 出错：%v", err))
+			ctx.JSON(http.StatusOK, result.Failed(code, msg))
+			return
+		}
+		// 会话记录已不存在（如已被其他设备下线），仅吊销当前访问令牌
+		if _, err = LoginServiceClient.RevokeToken(ctx, &loginServiceV1.RevokeTokenMessage{Token: id.Token}); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("调用 RevokeToken 出错：%v", err))
+			code, msg = libErrors.ParseGrpcError(err)
+			ctx.JSON(http.StatusOK, result.Failed(code, msg))
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// listSessions 列出当前账号的在线设备
+// [GET] /project/session/list
+func (h *HandlerUser) listSessions(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	rsp, err := LoginServiceClient.ListSessions(ctx, &loginServiceV1.ListSessionsMessage{
+		UserId:   auth.UserId(ctx),
+		DeviceId: auth.DeviceId(ctx),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 ListSessions 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	sessions := make([]userModel.Session, 0, len(rsp.Sessions))
+	for _, s := range rsp.Sessions {
+		sessions = append(sessions, userModel.Session{
+			DeviceId:   s.DeviceId,
+			DeviceName: s.DeviceName,
+			Platform:   s.Platform,
+			Ip:         s.Ip,
+			LoginTime:  s.LoginTime,
+			LastSeen:   s.LastSeen,
+			Current:    s.Current,
+		})
+	}
+	ctx.JSON(http.StatusOK, result.Success(sessions))
+}
+
+// kickDevice 下线当前账号的某个设备
+// [POST] /project/session/kick
+func (h *HandlerUser) kickDevice(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.KickDeviceReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "设备 ID 不能为空"))
+		return
+	}
+
+	_, err := LoginServiceClient.KickDevice(ctx, &loginServiceV1.KickDeviceMessage{UserId: auth.UserId(ctx), DeviceId: req.DeviceId})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 KickDevice 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(nil))
+}
+
+// logoutAll 下线当前账号的所有设备，keepCurrent 为 true 时保留当前设备
+// [POST] /project/session/logoutAll
+func (h *HandlerUser) logoutAll(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var req userModel.LogoutAllReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusOK, result.Failed(2002, "参数格式错误"))
+		return
+	}
+
+	in := &loginServiceV1.LogoutAllMessage{UserId: auth.UserId(ctx)}
+	if req.KeepCurrent {
+		in.ExceptDeviceId = auth.DeviceId(ctx)
+	}
+	rsp, err := LoginServiceClient.LogoutAll(ctx, in)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("调用 LogoutAll 出错：%v", err))
+		code, msg := libErrors.ParseGrpcError(err)
+		ctx.JSON(http.StatusOK, result.Failed(code, msg))
+		return
+	}
+	ctx.JSON(http.StatusOK, result.Success(userModel.LogoutAllRsp{Count: rsp.Count}))
+}
+
+func toLoginRsp(rsp *loginServiceV1.LoginResponse) userModel.LoginRsp {
+	if rsp.TwoFactorRequired {
+		return userModel.LoginRsp{
+			TwoFactorRequired: true,
+			ChallengeToken:    rsp.ChallengeToken,
+			ChallengeExpire:   rsp.ChallengeExpire,
+		}
+	}
+	tokenList := toTokenList(rsp.TokenList)
+	return userModel.LoginRsp{
+		Member:    toMember(rsp.Member),
+		TokenList: &tokenList,
+		DeviceId:  rsp.DeviceId,
+	}
+}
+
+func toMember(m *loginServiceV1.MemberMessage) *userModel.Member {
+	return &userModel.Member{
+		Id:            m.Id,
+		Name:          m.Name,
+		Mobile:        m.Mobile,
+		CreateTime:    m.CreateTime,
+		LastLoginTime: m.LastLoginTime,
+		Username:      m.Username,
+		Email:         m.Email,
+		EmailVerified: m.EmailVerified,
+		PurgeTime:     m.PurgeTime,
+	}
+}
+
+func toTokenList(t *loginServiceV1.TokenMessage) userModel.TokenList {
+	return userModel.TokenList{
+		AccessToken:     t.AccessToken,
+		AccessTokenExp:  t.AccessTokenExp,
+		TokenType:       t.TokenType,
+		RefreshToken:    t.RefreshToken,
+		RefreshTokenExp: t.RefreshTokenExp,
+	}
 }
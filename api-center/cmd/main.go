@@ -1,6 +1,9 @@
 package main
 
 import (
+	_ "github.com/MortalSC/IM-System/api-center/api/group"
+	_ "github.com/MortalSC/IM-System/api-center/api/message"
+	_ "github.com/MortalSC/IM-System/api-center/api/presence"
 	_ "github.com/MortalSC/IM-System/api-center/api/user"
 	"github.com/MortalSC/IM-System/api-center/config"
 	"github.com/MortalSC/IM-System/api-center/internal/router"
@@ -2,16 +2,24 @@ package config
 
 import (
 	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/rbac"
 	"github.com/spf13/viper"
 	"log"
 	"os"
+	"time"
 )
 
 var Cfg = InitConfig()
 
 type Config struct {
-	viper  *viper.Viper
-	SrvCfg *ServerConfig
+	viper       *viper.Viper
+	SrvCfg      *ServerConfig
+	AuthCfg     *AuthConfig
+	RbacCfg     *RbacConfig
+	EtcdCfg     *EtcdConfig
+	MsgCfg      *MessageConfig
+	GroupCfg    *GroupConfig
+	PresenceCfg *PresenceConfig
 }
 
 func InitConfig() *Config {
@@ -35,6 +43,18 @@ func InitConfig() *Config {
 	conf.InitServerConfig()
 	// 读取日志配置
 	conf.InitZapLog()
+	// 读取认证配置
+	conf.InitAuthConfig()
+	// 读取角色权限配置
+	conf.InitRbacConfig()
+	// 读取服务发现配置
+	conf.InitEtcdConfig()
+	// 读取消息服务配置
+	conf.InitMessageConfig()
+	// 读取群组服务配置
+	conf.InitGroupConfig()
+	// 读取在线状态服务配置
+	conf.InitPresenceConfig()
 
 	return conf
 }
@@ -67,3 +87,73 @@ func (c *Config) InitZapLog() {
 		log.Fatalln(err)
 	}
 }
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	Mode       string        // rpc：调用 auth-service VerifyToken 校验；local：使用缓存的公钥本地验签
+	KeyRefresh time.Duration // local 模式下公钥的刷新间隔
+}
+
+func (c *Config) InitAuthConfig() {
+	ac := &AuthConfig{}
+	ac.Mode = c.viper.GetString("auth.mode")
+	ac.KeyRefresh = c.viper.GetDuration("auth.keyRefresh")
+	c.AuthCfg = ac
+}
+
+// RbacConfig 角色权限配置，角色定义需与 auth-service 保持一致
+type RbacConfig struct {
+	Roles []rbac.Role
+}
+
+func (c *Config) InitRbacConfig() {
+	rc := &RbacConfig{}
+	if err := c.viper.UnmarshalKey("rbac.roles", &rc.Roles); err != nil {
+		log.Fatalf("rbac.roles: %v", err)
+	}
+	c.RbacCfg = rc
+}
+
+// EtcdConfig 服务发现配置
+type EtcdConfig struct {
+	Addrs []string
+}
+
+func (c *Config) InitEtcdConfig() {
+	ec := &EtcdConfig{}
+	ec.Addrs = c.viper.GetStringSlice("etcd.addrs")
+	c.EtcdCfg = ec
+}
+
+// MessageConfig 消息服务配置
+type MessageConfig struct {
+	Service string // message-service 注册到 etcd 的服务名
+}
+
+func (c *Config) InitMessageConfig() {
+	mc := &MessageConfig{}
+	mc.Service = c.viper.GetString("message.service")
+	c.MsgCfg = mc
+}
+
+// GroupConfig 群组服务配置
+type GroupConfig struct {
+	Service string // group-service 注册到 etcd 的服务名
+}
+
+func (c *Config) InitGroupConfig() {
+	gc := &GroupConfig{}
+	gc.Service = c.viper.GetString("group.service")
+	c.GroupCfg = gc
+}
+
+// PresenceConfig 在线状态服务配置
+type PresenceConfig struct {
+	Service string // presence-service 注册到 etcd 的服务名
+}
+
+func (c *Config) InitPresenceConfig() {
+	pc := &PresenceConfig{}
+	pc.Service = c.viper.GetString("presence.service")
+	c.PresenceCfg = pc
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/lib/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ctxKeyUserId   = "userId"
+	ctxKeyDeviceId = "deviceId"
+	ctxKeyIdentity = "identity"
+)
+
+type identityKey struct{}
+
+// Identity 已认证的调用方
+type Identity struct {
+	UserId   int64
+	DeviceId string
+	TokenId  string
+	ExpireAt int64 // 令牌过期时间（毫秒时间戳）
+	Token    string
+	ClientId string   // OAuth2 令牌所属的第三方应用，第一方登录令牌为空
+	Scopes   []string // OAuth2 令牌的授权范围
+	Roles    []string // 第一方令牌签发时账号拥有的角色，格式见 rbac.Grant
+	KeyId    string   // 以 API Key 认证时为 Key 的前缀，此时 Token 为空
+	Service  bool     // API Key 是否属于服务账号
+}
+
+// FirstParty 是否为用户在本系统客户端登录获得的令牌，第三方应用令牌和 API Key 都不是
+func (id *Identity) FirstParty() bool {
+	return id.ClientId == "" && id.KeyId == ""
+}
+
+// HasScope 令牌或 API Key 是否拥有授权范围，第一方令牌拥有全部权限
+func (id *Identity) HasScope(scope string) bool {
+	if id.FirstParty() {
+		return true
+	}
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Grants 调用方的角色授权，第三方应用令牌和 API Key 只受授权范围限制，不拥有角色
+func (id *Identity) Grants() []rbac.Grant {
+	if !id.FirstParty() {
+		return nil
+	}
+	return rbac.ParseGrants(id.Roles)
+}
+
+// WithIdentity 将调用方身份写入 context
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext 从 context 中读取调用方身份，用于 gRPC 调用等拿不到 gin.Context 的场景
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
+
+// setIdentity 同时写入 gin.Context 和请求的 context
+func setIdentity(c *gin.Context, id *Identity) {
+	c.Set(ctxKeyIdentity, id)
+	c.Set(ctxKeyUserId, id.UserId)
+	c.Set(ctxKeyDeviceId, id.DeviceId)
+	c.Request = c.Request.WithContext(WithIdentity(c.Request.Context(), id))
+}
+
+// Current 当前请求的调用方身份，只能在认证路由组中使用
+func Current(c *gin.Context) *Identity {
+	v, ok := c.Get(ctxKeyIdentity)
+	if !ok {
+		return nil
+	}
+	return v.(*Identity)
+}
+
+// UserId 当前请求的账号 ID
+func UserId(c *gin.Context) int64 {
+	return c.GetInt64(ctxKeyUserId)
+}
+
+// DeviceId 当前请求的设备 ID
+func DeviceId(c *gin.Context) string {
+	return c.GetString(ctxKeyDeviceId)
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// 与 auth-service 的令牌错误码保持一致
+const (
+	codeNoToken      = 2100 // 缺少令牌
+	codeTokenInvalid = 2101 // 令牌无效
+	codeTokenExpired = 2102 // 令牌已过期
+	codeAuthBusy     = 2199 // 认证服务不可用
+
+	codeFirstPartyOnly    = 2110 // 第三方应用令牌不能访问该接口
+	codeInsufficientScope = 2111 // 令牌缺少所需的授权范围
+
+	codePermissionDenied = 2701 // 权限不足，与 auth-service 管理接口返回的错误码一致
+)
+
+// Authorization 支持的认证方式，不区分大小写
+const (
+	schemeBearer = "Bearer"
+	schemeApiKey = "ApiKey"
+)
+
+var (
+	verifier       Verifier
+	apiKeyVerifier ApiKeyVerifier
+)
+
+// SetVerifier 设置认证中间件使用的令牌校验器，需在服务启动前调用
+func SetVerifier(v Verifier) {
+	verifier = v
+}
+
+// SetApiKeyVerifier 设置认证中间件使用的 API Key 校验器，未设置时不接受 API Key
+func SetApiKeyVerifier(v ApiKeyVerifier) {
+	apiKeyVerifier = v
+}
+
+// Middleware 认证中间件：从 Authorization: Bearer <token> 中提取访问令牌，或从 Authorization: ApiKey <key> 中提取 API Key，
+// 校验通过后将调用方身份写入上下文
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result := model.HttpResult{}
+
+		scheme, credential := authorization(c.GetHeader("Authorization"))
+		if credential == "" || scheme != schemeBearer && scheme != schemeApiKey {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.Failed(codeNoToken, "未登录"))
+			return
+		}
+
+		// 两种认证方式相互独立，只检查本次请求使用的校验器
+		var (
+			id  *Identity
+			err error
+		)
+		if scheme == schemeApiKey {
+			if apiKeyVerifier == nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, result.Failed(codeTokenInvalid, "不支持 API Key 认证"))
+				return
+			}
+			id, err = apiKeyVerifier.VerifyApiKey(c.Request.Context(), credential, c.ClientIP())
+		} else {
+			if verifier == nil {
+				libLog.IMLog.Error("认证中间件未设置令牌校验器")
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, result.Failed(codeAuthBusy, "认证服务不可用"))
+				return
+			}
+			id, err = verifier.Verify(c.Request.Context(), credential)
+		}
+		if err != nil {
+			code, msg := verifyError(err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.Failed(code, msg))
+			return
+		}
+		setIdentity(c, id)
+		c.Next()
+	}
+}
+
+// FirstPartyOnly 只允许用户在本系统客户端登录获得的令牌，需放在认证中间件之后
+// 账号、会话、授权管理等接口不对第三方应用和 API Key 开放
+func FirstPartyOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id := Current(c); id == nil || !id.FirstParty() {
+			result := model.HttpResult{}
+			c.AbortWithStatusJSON(http.StatusForbidden, result.Failed(codeFirstPartyOnly, "该接口只接受登录令牌"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope 要求令牌拥有全部指定的授权范围，第一方令牌不受限制，需放在认证中间件之后
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := Current(c)
+		for _, s := range scopes {
+			if id == nil || !id.HasScope(s) {
+				result := model.HttpResult{}
+				c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(scopes, " ")))
+				c.AbortWithStatusJSON(http.StatusForbidden, result.Failed(codeInsufficientScope, "授权范围不足: "+s))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// authorization 解析 Authorization 头，返回规范化的 scheme 和凭证，不支持的 scheme 原样返回
+func authorization(header string) (string, string) {
+	scheme, credential, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok {
+		return "", ""
+	}
+	for _, s := range []string{schemeBearer, schemeApiKey} {
+		if strings.EqualFold(scheme, s) {
+			scheme = s
+		}
+	}
+	return scheme, strings.TrimSpace(credential)
+}
+
+// verifyError 将本地验签错误或 auth-service 返回的 gRPC 错误转换为响应码
+func verifyError(err error) (int, string) {
+	switch {
+	case errors.Is(err, jwts.ErrTokenExpired):
+		return codeTokenExpired, "令牌已过期"
+	case errors.Is(err, jwts.ErrTokenInvalid), errors.Is(err, jwts.ErrUnknownKey):
+		return codeTokenInvalid, "令牌无效"
+	}
+	code, msg := libErrors.ParseGrpcError(err)
+	if code < 1000 {
+		// gRPC 标准状态码，非业务错误（如 auth-service 不可达）
+		libLog.IMLog.Error(fmt.Sprintf("校验令牌出错：%v", err))
+		return codeAuthBusy, "认证服务不可用"
+	}
+	return code, msg
+}
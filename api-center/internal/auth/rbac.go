@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"github.com/MortalSC/IM-System/api-center/pkg/model"
+	"github.com/MortalSC/IM-System/lib/rbac"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var enforcer *rbac.GinEnforcer
+
+// SetPolicy 设置权限检查使用的角色策略，需在注册路由前调用
+func SetPolicy(p *rbac.Policy) {
+	enforcer = rbac.NewGinEnforcer(p, grants, denied)
+}
+
+// Require 声明路由需要的权限，需放在认证中间件之后：group.GET(path, auth.Require("audit:read"), handler)
+// scopes 从请求中解析目标租户或群组，如 rbac.ParamScope(rbac.ScopeGroup, "groupId")，不传时只有全局角色生效
+func Require(perm string, scopes ...rbac.GinScope) gin.HandlerFunc {
+	if enforcer == nil {
+		panic("auth: SetPolicy must be called before Require")
+	}
+	return enforcer.Require(perm, scopes...)
+}
+
+// RequireAny 声明路由需要在任一租户或群组内拥有权限，用于目标范围在请求体中、由后端服务精确检查的接口
+func RequireAny(perm string) gin.HandlerFunc {
+	if enforcer == nil {
+		panic("auth: SetPolicy must be called before RequireAny")
+	}
+	return enforcer.RequireAny(perm)
+}
+
+func grants(c *gin.Context) []rbac.Grant {
+	id := Current(c)
+	if id == nil {
+		return nil
+	}
+	return id.Grants()
+}
+
+func denied(c *gin.Context, perm string) {
+	result := model.HttpResult{}
+	c.AbortWithStatusJSON(http.StatusForbidden, result.Failed(codePermissionDenied, "权限不足: "+perm))
+}
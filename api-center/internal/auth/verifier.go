@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ModeRpc   = "rpc"
+	ModeLocal = "local"
+
+	minKeyRefreshInterval = 10 * time.Second // 遇到未知 kid 时两次拉取公钥的最小间隔
+)
+
+// Verifier 校验访问令牌并返回调用方身份
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Identity, error)
+}
+
+// ApiKeyVerifier 校验 API Key 并返回调用方身份，ip 为调用方 IP，记录为 Key 的最近使用 IP
+type ApiKeyVerifier interface {
+	VerifyApiKey(ctx context.Context, key, ip string) (*Identity, error)
+}
+
+// NewApiKeyVerifier 创建 API Key 校验器，API Key 没有可本地验签的格式，总是调用 auth-service 校验
+func NewApiKeyVerifier(client loginServiceV1.LoginServiceClient) ApiKeyVerifier {
+	return &RpcVerifier{client: client}
+}
+
+// NewVerifier 按配置创建令牌校验器
+func NewVerifier(mode string, keyRefresh time.Duration, client loginServiceV1.LoginServiceClient) Verifier {
+	rpc := &RpcVerifier{client: client}
+	if mode != ModeLocal {
+		return rpc
+	}
+	return NewLocalVerifier(client, keyRefresh, rpc)
+}
+
+// RpcVerifier 每次调用 auth-service VerifyToken 校验，能感知令牌吊销
+type RpcVerifier struct {
+	client loginServiceV1.LoginServiceClient
+}
+
+func (v *RpcVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	rsp, err := v.client.VerifyToken(ctx, &loginServiceV1.VerifyTokenMessage{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		UserId:   rsp.UserId,
+		DeviceId: rsp.DeviceId,
+		TokenId:  rsp.TokenId,
+		ExpireAt: rsp.ExpireAt,
+		Token:    token,
+		ClientId: rsp.ClientId,
+		Scopes:   strings.Fields(rsp.Scope),
+		Roles:    rsp.Roles,
+	}, nil
+}
+
+func (v *RpcVerifier) VerifyApiKey(ctx context.Context, key, ip string) (*Identity, error) {
+	rsp, err := v.client.VerifyApiKey(ctx, &loginServiceV1.VerifyApiKeyMessage{Key: key, Ip: ip})
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		UserId:   rsp.UserId,
+		ExpireAt: rsp.ExpireAt,
+		Scopes:   rsp.Scopes,
+		KeyId:    rsp.Prefix,
+		Service:  rsp.ServiceAccount,
+	}, nil
+}
+
+// LocalVerifier 使用从 auth-service 拉取并缓存的公钥本地验签，不感知吊销，依赖访问令牌的短有效期
+// auth-service 使用对称算法（未提供公钥）时退化为 RPC 校验
+type LocalVerifier struct {
+	client   loginServiceV1.LoginServiceClient
+	keys     *jwts.KeySet
+	fallback Verifier
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+}
+
+func NewLocalVerifier(client loginServiceV1.LoginServiceClient, keyRefresh time.Duration, fallback Verifier) *LocalVerifier {
+	v := &LocalVerifier{client: client, keys: jwts.NewKeySet(), fallback: fallback}
+	if keyRefresh > 0 {
+		go func() {
+			for range time.Tick(keyRefresh) {
+				if err := v.refresh(context.Background(), true); err != nil {
+					libLog.IMLog.Warn(fmt.Sprintf("刷新令牌公钥出错：%v", err))
+				}
+			}
+		}()
+	}
+	return v
+}
+
+func (v *LocalVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	if v.keys.Len() == 0 {
+		if err := v.refresh(ctx, false); err != nil {
+			return nil, err
+		}
+		if v.keys.Len() == 0 {
+			return v.fallback.Verify(ctx, token)
+		}
+	}
+
+	claims, err := v.keys.Parse(token, jwts.TypeAccess)
+	if errors.Is(err, jwts.ErrUnknownKey) {
+		// 签发方可能已轮换密钥，重新拉取公钥后再试一次
+		if err = v.refresh(ctx, false); err != nil {
+			return nil, err
+		}
+		claims, err = v.keys.Parse(token, jwts.TypeAccess)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		UserId:   claims.UserId,
+		DeviceId: claims.DeviceId,
+		TokenId:  claims.ID,
+		ExpireAt: claims.ExpiresAt.UnixMilli(),
+		Token:    token,
+		ClientId: claims.ClientId,
+		Scopes:   claims.Scopes(),
+		Roles:    claims.Roles,
+	}, nil
+}
+
+// refresh 从 auth-service 拉取公钥，force 为 false 时受最小刷新间隔限制
+func (v *LocalVerifier) refresh(ctx context.Context, force bool) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !force && time.Since(v.lastRefresh) < minKeyRefreshInterval {
+		return nil
+	}
+
+	rsp, err := v.client.GetPublicKeys(ctx, &loginServiceV1.PublicKeysMessage{})
+	if err != nil {
+		return err
+	}
+	keys := make([]*jwts.Keys, 0, len(rsp.Keys))
+	for _, pk := range rsp.Keys {
+		k, err := jwts.NewVerifyKeys(pk.Algorithm, pk.KeyId, pk.Issuer, []byte(pk.PublicKey))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	v.keys.Replace(keys)
+	v.lastRefresh = time.Now()
+	return nil
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/MortalSC/IM-System/api-center/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// Public 声明无需认证的路由组
+func Public(r *gin.Engine, relativePath string) *gin.RouterGroup {
+	return r.Group(relativePath)
+}
+
+// Authenticated 声明需要认证的路由组，组内路由先经过认证中间件，可通过 auth.UserId/auth.DeviceId 获取调用方
+// 只接受第一方登录令牌，第三方应用的 OAuth2 令牌和 API Key 需通过 Scoped 声明的路由访问
+func Authenticated(r *gin.Engine, relativePath string) *gin.RouterGroup {
+	return r.Group(relativePath, auth.Middleware(), auth.FirstPartyOnly())
+}
+
+// Scoped 声明同时对第三方应用和 API Key 开放的路由组，OAuth2 令牌和 API Key 需拥有全部 scopes，第一方登录令牌不受限制
+// 客户端凭证模式的令牌不代表任何账号，auth.UserId 为 0
+func Scoped(r *gin.Engine, relativePath string, scopes ...string) *gin.RouterGroup {
+	return r.Group(relativePath, auth.Middleware(), auth.RequireScope(scopes...))
+}
@@ -0,0 +1,96 @@
+package group
+
+// CreateReq 创建群组请求参数，joinMode 为空时为仅邀请，maxMembers 为空时使用默认上限
+type CreateReq struct {
+	Name       string  `json:"name" binding:"required"`
+	Avatar     string  `json:"avatar"`
+	Intro      string  `json:"intro"`
+	JoinMode   int32   `json:"joinMode"`
+	MaxMembers int32   `json:"maxMembers"`
+	MemberIds  []int64 `json:"memberIds"`
+}
+
+// GroupReq 只需要群组 ID 的请求参数
+type GroupReq struct {
+	GroupId int64 `json:"groupId" form:"groupId" binding:"required"`
+}
+
+// InviteReq 邀请成员请求参数
+type InviteReq struct {
+	GroupId int64   `json:"groupId" binding:"required"`
+	UserIds []int64 `json:"userIds" binding:"required"`
+}
+
+// MemberReq 对指定成员操作（移除、转让群主）的请求参数
+type MemberReq struct {
+	GroupId int64 `json:"groupId" binding:"required"`
+	UserId  int64 `json:"userId" binding:"required"`
+}
+
+// RoleReq 设置成员角色请求参数，role 为 2 管理员 3 成员
+type RoleReq struct {
+	GroupId int64 `json:"groupId" binding:"required"`
+	UserId  int64 `json:"userId" binding:"required"`
+	Role    int32 `json:"role" binding:"required"`
+}
+
+// UpdateReq 修改群资料请求参数，未填写的字段不修改
+type UpdateReq struct {
+	GroupId  int64   `json:"groupId" binding:"required"`
+	Name     *string `json:"name"`
+	Avatar   *string `json:"avatar"`
+	Intro    *string `json:"intro"`
+	JoinMode *int32  `json:"joinMode"`
+}
+
+// AnnouncementReq 发布群公告请求参数，announcement 为空时清除公告
+type AnnouncementReq struct {
+	GroupId      int64  `json:"groupId" binding:"required"`
+	Announcement string `json:"announcement"`
+}
+
+// MembersReq 成员列表查询参数，afterUserId 为上一页最后一个成员的用户 ID
+type MembersReq struct {
+	GroupId     int64 `form:"groupId" binding:"required"`
+	AfterUserId int64 `form:"afterUserId"`
+	Limit       int32 `form:"limit"`
+}
+
+// Group 群资料，role 和 joinTime 为当前账号在群内的角色和加入时间，非成员为 0
+type Group struct {
+	GroupId          int64  `json:"groupId"`
+	Name             string `json:"name"`
+	Avatar           string `json:"avatar,omitempty"`
+	Intro            string `json:"intro,omitempty"`
+	Announcement     string `json:"announcement,omitempty"`
+	AnnouncementTime int64  `json:"announcementTime,omitempty"`
+	AnnouncementBy   int64  `json:"announcementBy,omitempty"`
+	OwnerId          int64  `json:"ownerId"`
+	MemberCount      int32  `json:"memberCount"`
+	MaxMembers       int32  `json:"maxMembers"`
+	JoinMode         int32  `json:"joinMode"`
+	Fanout           int32  `json:"fanout"`
+	CreateTime       int64  `json:"createTime"`
+	Role             int32  `json:"role,omitempty"`
+	JoinTime         int64  `json:"joinTime,omitempty"`
+}
+
+// Member 群成员
+type Member struct {
+	UserId    int64 `json:"userId"`
+	Role      int32 `json:"role"`
+	InviterId int64 `json:"inviterId,omitempty"`
+	JoinTime  int64 `json:"joinTime"`
+}
+
+// InviteResult 邀请结果，added 为本次新加入的成员
+type InviteResult struct {
+	Added       []int64 `json:"added"`
+	MemberCount int32   `json:"memberCount"`
+}
+
+// MemberPage 一页成员，按用户 ID 升序；hasMore 为 true 时以最后一个成员的 userId 作为 afterUserId 查询下一页
+type MemberPage struct {
+	Members []Member `json:"members"`
+	HasMore bool     `json:"hasMore"`
+}
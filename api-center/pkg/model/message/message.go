@@ -0,0 +1,130 @@
+package message
+
+// SendReq 发送消息请求参数，content 为消息内容原文
+type SendReq struct {
+	ClientMsgId      string `json:"clientMsgId"`
+	ConversationType int32  `json:"conversationType"` // 为空时为单聊
+	TargetId         int64  `json:"targetId" binding:"required"`
+	ContentType      int32  `json:"contentType" binding:"required"`
+	Content          string `json:"content" binding:"required"`
+}
+
+// SendAck 服务端确认
+type SendAck struct {
+	ClientMsgId    string `json:"clientMsgId,omitempty"`
+	MsgId          int64  `json:"msgId"`
+	Seq            int64  `json:"seq"`
+	ConversationId int64  `json:"conversationId"`
+	SendTime       int64  `json:"sendTime"`
+	Duplicate      bool   `json:"duplicate"`
+}
+
+// HistoryReq 历史消息查询参数，beforeSeq 为空时从最新一条开始
+type HistoryReq struct {
+	ConversationType int32 `form:"conversationType"`
+	TargetId         int64 `form:"targetId" binding:"required"`
+	BeforeSeq        int64 `form:"beforeSeq"`
+	Limit            int32 `form:"limit"`
+}
+
+// Message 一条消息
+type Message struct {
+	MsgId            int64  `json:"msgId"`
+	Seq              int64  `json:"seq"`
+	ConversationType int32  `json:"conversationType"`
+	ConversationId   int64  `json:"conversationId"`
+	SenderId         int64  `json:"senderId"`
+	SenderDevice     string `json:"senderDevice,omitempty"`
+	TargetId         int64  `json:"targetId"`
+	ContentType      int32  `json:"contentType"`
+	Content          string `json:"content"`
+	ClientMsgId      string `json:"clientMsgId,omitempty"`
+	SendTime         int64  `json:"sendTime"`
+}
+
+// HistoryPage 一页历史消息，按序号升序；hasMore 为 true 时以 messages[0].seq 作为 beforeSeq 查询上一页
+type HistoryPage struct {
+	Messages       []Message `json:"messages"`
+	HasMore        bool      `json:"hasMore"`
+	ConversationId int64     `json:"conversationId"`
+}
+
+// SyncReq 离线同步请求参数：游标为上一页返回的值，表示设备已收到该位置之前的全部消息
+// resume 为 true 时忽略游标，从服务端保存的位置继续；登录令牌不带设备时需填写 deviceId
+type SyncReq struct {
+	DeviceId            string               `json:"deviceId"`
+	Resume              bool                 `json:"resume"`
+	Cursor              int64                `json:"cursor"`
+	ConversationCursors []ConversationCursor `json:"conversationCursors"`
+	ConversationVersion int64                `json:"conversationVersion"`
+	Limit               int32                `json:"limit"`
+}
+
+// ConversationCursor 读扩散群聊的会话游标，reset 为 true 时早于保留期的消息已跳过
+type ConversationCursor struct {
+	ConversationId int64 `json:"conversationId"`
+	Seq            int64 `json:"seq"`
+	Reset          bool  `json:"reset,omitempty"`
+}
+
+// Conversation 会话列表中的一项，targetId 单聊为对方用户，群聊为群组
+type Conversation struct {
+	ConversationType int32    `json:"conversationType"`
+	ConversationId   int64    `json:"conversationId"`
+	TargetId         int64    `json:"targetId"`
+	MaxSeq           int64    `json:"maxSeq"`
+	Unread           int64    `json:"unread"`
+	LastMessage      *Message `json:"lastMessage,omitempty"`
+	Version          int64    `json:"version"`
+}
+
+// SyncPage 一页同步结果，hasMore 为 true 时以返回的游标继续同步；reset 为 true 时需清空本地状态，会话列表为全量
+type SyncPage struct {
+	Messages            []Message            `json:"messages"`
+	Cursor              int64                `json:"cursor"`
+	HasMore             bool                 `json:"hasMore"`
+	Reset               bool                 `json:"reset"`
+	ConversationCursors []ConversationCursor `json:"conversationCursors"`
+	Conversations       []Conversation       `json:"conversations"`
+	ConversationVersion int64                `json:"conversationVersion"`
+}
+
+// AckReq 确认送达或已读，seq 及之前的全部消息一并确认；type 1 送达 2 已读
+type AckReq struct {
+	Type           int32 `json:"type" binding:"required"`
+	ConversationId int64 `json:"conversationId" binding:"required"`
+	Seq            int64 `json:"seq" binding:"required"`
+}
+
+// AckResult 确认后的位置和该会话的未读数
+type AckResult struct {
+	DeliveredSeq int64 `json:"deliveredSeq"`
+	ReadSeq      int64 `json:"readSeq"`
+	Unread       int64 `json:"unread"`
+}
+
+// UnreadCount 一个会话的未读数
+type UnreadCount struct {
+	ConversationId int64 `json:"conversationId"`
+	Unread         int64 `json:"unread"`
+}
+
+// UnreadSummary 各会话的未读数和总数，只含有未读消息的会话
+type UnreadSummary struct {
+	Conversations []UnreadCount `json:"conversations"`
+	Total         int64         `json:"total"`
+}
+
+// ReceiptsReq 查看自己发送的一条消息的回执
+type ReceiptsReq struct {
+	ConversationId int64 `form:"conversationId" binding:"required"`
+	Seq            int64 `form:"seq" binding:"required"`
+}
+
+// Receipts 消息的回执汇总，不含发送方自己；readBy 按已读时间升序，数量有上限
+type Receipts struct {
+	MemberCount    int32   `json:"memberCount"`
+	DeliveredCount int32   `json:"deliveredCount"`
+	ReadCount      int32   `json:"readCount"`
+	ReadBy         []int64 `json:"readBy"`
+}
@@ -0,0 +1,29 @@
+package presence
+
+// UsersReq 批量查询、订阅和取消订阅的请求参数
+type UsersReq struct {
+	UserIds []int64 `json:"userIds" form:"userIds" binding:"required"`
+}
+
+// SettingsReq 设置最后在线时间的可见范围：1 所有人 2 仅联系人（有过单聊的用户）3 所有人不可见
+type SettingsReq struct {
+	LastSeenVisibility int32 `json:"lastSeenVisibility" binding:"required"`
+}
+
+// Presence 用户的在线状态：0 未知（对方的隐私设置不允许查看）1 在线 2 离开 3 忙碌 4 离线
+type Presence struct {
+	UserId   int64 `json:"userId"`
+	Status   int32 `json:"status"`
+	LastSeen int64 `json:"lastSeen"` // 最后在线时间（毫秒时间戳），对方设置为不可见时为 0
+}
+
+// Subscriptions 订阅的用户
+type Subscriptions struct {
+	UserIds []int64 `json:"userIds"`
+}
+
+// Settings 在线状态的隐私设置
+type Settings struct {
+	LastSeenVisibility int32 `json:"lastSeenVisibility"`
+	UpdateTime         int64 `json:"updateTime"` // 没有设置过时为 0
+}
@@ -0,0 +1,443 @@
+package user
+
+// LoginReq 登录请求参数
+type LoginReq struct {
+	Mobile     string `form:"mobile" binding:"required"`
+	Captcha    string `form:"captcha" binding:"required"`
+	DeviceId   string `form:"deviceId"`
+	DeviceName string `form:"deviceName"`
+	Platform   string `form:"platform"`
+}
+
+// HumanCheck 人机验证题，图片为 PNG 的 data URI
+// 滑块题（kind=slider）需将 piece 水平拖到背景缺口处，提交拼图块左边缘的横坐标；图片题（kind=image）提交图中字符
+type HumanCheck struct {
+	Id       string `json:"id"`
+	Kind     string `json:"kind"`
+	Image    string `json:"image"`
+	Piece    string `json:"piece,omitempty"`
+	PieceY   int32  `json:"pieceY,omitempty"`
+	Width    int32  `json:"width"`
+	Height   int32  `json:"height"`
+	ExpireAt int64  `json:"expireAt"`
+}
+
+// VerifyHumanCheckReq 提交人机验证答案请求参数
+type VerifyHumanCheckReq struct {
+	Id     string `form:"id" binding:"required"`
+	Answer string `form:"answer" binding:"required"`
+}
+
+// HumanCheckPass 人机验证通过凭证，获取短信验证码时作为 ticket 参数提交，只能使用一次
+type HumanCheckPass struct {
+	Ticket   string `json:"ticket"`
+	ExpireAt int64  `json:"expireAt"`
+}
+
+// RegisterReq 用户名 + 密码注册请求参数
+type RegisterReq struct {
+	Username   string `form:"username" binding:"required"`
+	Email      string `form:"email"`
+	Mobile     string `form:"mobile"` // 与邮箱至少填写一个，填写时验证码发送到手机
+	Captcha    string `form:"captcha" binding:"required"`
+	Password   string `form:"password" binding:"required"`
+	DeviceId   string `form:"deviceId"`
+	DeviceName string `form:"deviceName"`
+	Platform   string `form:"platform"`
+}
+
+// PasswordLoginReq 密码登录请求参数
+type PasswordLoginReq struct {
+	Account    string `form:"account" binding:"required"` // 用户名、邮箱或手机号
+	Password   string `form:"password" binding:"required"`
+	DeviceId   string `form:"deviceId"`
+	DeviceName string `form:"deviceName"`
+	Platform   string `form:"platform"`
+}
+
+// ResetPasswordReq 重置密码请求参数
+type ResetPasswordReq struct {
+	Mobile   string `form:"mobile"`
+	Email    string `form:"email"` // 未填写手机号时通过已验证的邮箱找回
+	Captcha  string `form:"captcha" binding:"required"`
+	Password string `form:"password" binding:"required"`
+}
+
+// BindEmailReq 绑定邮箱请求参数，验证码通过 /project/login/getCaptcha 以 email 渠道获取
+type BindEmailReq struct {
+	Email   string `form:"email" binding:"required"`
+	Captcha string `form:"captcha" binding:"required"`
+}
+
+// ChangeMobileReq 更换手机号请求参数，两个验证码都通过 /project/login/getCaptcha 获取
+type ChangeMobileReq struct {
+	OldCaptcha string `form:"oldCaptcha"` // 当前手机号收到的验证码，账号未绑定手机号时可为空
+	Mobile     string `form:"mobile" binding:"required"`
+	Captcha    string `form:"captcha" binding:"required"`
+}
+
+// DeleteAccountReq 申请注销账号请求参数，验证码发送到账号绑定的手机号，未绑定时发送到已验证的邮箱
+type DeleteAccountReq struct {
+	Captcha string `form:"captcha" binding:"required"`
+}
+
+// AccountDeletion 注销申请结果
+type AccountDeletion struct {
+	PurgeTime int64 `json:"purgeTime"` // 计划清除数据的时间（毫秒时间戳），此前可登录并撤销注销
+}
+
+// VerifyTwoFactorReq 登录两步验证请求参数
+type VerifyTwoFactorReq struct {
+	ChallengeToken string `form:"challengeToken" binding:"required"`
+	Code           string `form:"code" binding:"required"` // 动态码或恢复码
+}
+
+// TotpCodeReq 启用/关闭两步验证请求参数
+type TotpCodeReq struct {
+	Code string `form:"code" binding:"required"`
+}
+
+// EnrollTotpRsp 绑定验证器响应
+type EnrollTotpRsp struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauthUri"`
+}
+
+// ConfirmTotpRsp 启用两步验证响应，恢复码只返回这一次
+type ConfirmTotpRsp struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// RefreshTokenReq 刷新令牌请求参数
+type RefreshTokenReq struct {
+	RefreshToken string `form:"refreshToken" binding:"required"`
+}
+
+// LoginRsp 登录响应
+// 账号启用两步验证时只返回 TwoFactorRequired 和挑战令牌，需调用 /project/login/2fa 完成登录
+type LoginRsp struct {
+	Member            *Member    `json:"member,omitempty"`
+	TokenList         *TokenList `json:"tokenList,omitempty"`
+	DeviceId          string     `json:"deviceId,omitempty"`
+	TwoFactorRequired bool       `json:"twoFactorRequired"`
+	ChallengeToken    string     `json:"challengeToken,omitempty"`
+	ChallengeExpire   int64      `json:"challengeExpire,omitempty"`
+}
+
+// Member 账号信息
+type Member struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	Mobile        string `json:"mobile"`
+	CreateTime    int64  `json:"createTime"`
+	LastLoginTime int64  `json:"lastLoginTime"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+	PurgeTime     int64  `json:"purgeTime,omitempty"` // 已申请注销时为计划清除数据的时间，客户端据此提示撤销注销
+}
+
+// TokenList 登录凭证
+type TokenList struct {
+	AccessToken     string `json:"accessToken"`
+	AccessTokenExp  int64  `json:"accessTokenExp"`
+	TokenType       string `json:"tokenType"`
+	RefreshToken    string `json:"refreshToken"`
+	RefreshTokenExp int64  `json:"refreshTokenExp"`
+}
+
+// Session 在线设备
+type Session struct {
+	DeviceId   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	Platform   string `json:"platform"`
+	Ip         string `json:"ip"`
+	LoginTime  int64  `json:"loginTime"`
+	LastSeen   int64  `json:"lastSeen"`
+	Current    bool   `json:"current"`
+}
+
+// KickDeviceReq 下线设备请求参数
+type KickDeviceReq struct {
+	DeviceId string `form:"deviceId" binding:"required"`
+}
+
+// LogoutAllReq 下线所有设备请求参数
+type LogoutAllReq struct {
+	KeepCurrent bool `form:"keepCurrent"` // 是否保留当前设备
+}
+
+// LogoutAllRsp 下线所有设备响应
+type LogoutAllRsp struct {
+	Count int32 `json:"count"`
+}
+
+// OidcProvider 第三方登录的身份提供方
+type OidcProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// OidcAuthorizeReq 发起第三方登录请求参数（查询参数）
+type OidcAuthorizeReq struct {
+	DeviceId   string `form:"deviceId"`
+	DeviceName string `form:"deviceName"`
+	Platform   string `form:"platform"`
+}
+
+// OidcCallbackReq 身份提供方回调参数
+type OidcCallbackReq struct {
+	State string `form:"state"`
+	Code  string `form:"code"`
+	Error string `form:"error"`
+}
+
+// OidcCallbackRsp 第三方登录回调响应，绑定流程只返回 Bound
+type OidcCallbackRsp struct {
+	Provider string    `json:"provider"`
+	Bound    bool      `json:"bound"`
+	Login    *LoginRsp `json:"login,omitempty"`
+}
+
+// OidcProviderReq 绑定/解绑第三方账号请求参数
+type OidcProviderReq struct {
+	Provider string `form:"provider" binding:"required"`
+}
+
+// OidcBindRsp 绑定第三方账号响应，客户端跳转到授权地址完成绑定
+type OidcBindRsp struct {
+	AuthorizeUrl string `json:"authorizeUrl"`
+}
+
+// Identity 账号关联的第三方身份
+type Identity struct {
+	Provider   string `json:"provider"`
+	Subject    string `json:"subject"`
+	Email      string `json:"email"`
+	CreateTime int64  `json:"createTime"`
+}
+
+// OAuthClientReq 注册第三方应用请求参数
+type OAuthClientReq struct {
+	Name         string   `json:"name" binding:"required"`
+	RedirectUris []string `json:"redirectUris"`
+	Scopes       []string `json:"scopes" binding:"required"`
+	GrantTypes   []string `json:"grantTypes"` // 为空时默认 authorization_code + refresh_token
+}
+
+// OAuthClient 第三方应用
+type OAuthClient struct {
+	ClientId     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret,omitempty"` // 只在注册和重置密钥时返回
+	Name         string   `json:"name"`
+	RedirectUris []string `json:"redirectUris"`
+	Scopes       []string `json:"scopes"`
+	GrantTypes   []string `json:"grantTypes"`
+	CreateTime   int64    `json:"createTime,omitempty"`
+}
+
+// OAuthClientIdReq 按客户端 ID 操作应用或授权的请求参数
+type OAuthClientIdReq struct {
+	ClientId string `json:"clientId" form:"clientId" binding:"required"`
+}
+
+// OAuthAuthorizeReq 授权请求参数，与 OAuth2 授权端点的查询参数一致
+type OAuthAuthorizeReq struct {
+	ResponseType        string `json:"response_type" form:"response_type"`
+	ClientId            string `json:"client_id" form:"client_id"`
+	RedirectUri         string `json:"redirect_uri" form:"redirect_uri"`
+	Scope               string `json:"scope" form:"scope"`
+	State               string `json:"state" form:"state"`
+	CodeChallenge       string `json:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" form:"code_challenge_method"`
+}
+
+// OAuthApproveReq 用户确认或拒绝授权
+type OAuthApproveReq struct {
+	OAuthAuthorizeReq
+	Approved bool `json:"approved"`
+}
+
+// OAuthScope 授权页展示的授权范围
+type OAuthScope struct {
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+	Granted     bool   `json:"granted"` // 此前已同意
+}
+
+// OAuthConsentInfo 授权页信息，NeedConsent 为 false 时客户端可直接确认
+type OAuthConsentInfo struct {
+	ClientId    string       `json:"clientId"`
+	ClientName  string       `json:"clientName"`
+	RedirectUri string       `json:"redirectUri"`
+	Scopes      []OAuthScope `json:"scopes"`
+	NeedConsent bool         `json:"needConsent"`
+}
+
+// OAuthApproveRsp 确认授权响应，客户端跳转到该地址回到第三方应用
+type OAuthApproveRsp struct {
+	RedirectUrl string `json:"redirectUrl"`
+}
+
+// OAuthConsent 账号授权过的第三方应用
+type OAuthConsent struct {
+	ClientId   string   `json:"clientId"`
+	ClientName string   `json:"clientName"`
+	Scopes     []string `json:"scopes"`
+	CreateTime int64    `json:"createTime"`
+	UpdateTime int64    `json:"updateTime"`
+}
+
+// OAuthTokenReq 令牌端点参数（application/x-www-form-urlencoded），客户端凭证也可通过 HTTP Basic 传递
+type OAuthTokenReq struct {
+	GrantType    string `form:"grant_type"`
+	ClientId     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Code         string `form:"code"`
+	RedirectUri  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	Scope        string `form:"scope"`
+}
+
+// OAuthTokenRsp 令牌端点响应（RFC 6749 5.1）
+type OAuthTokenRsp struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // 秒
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthErrorRsp 令牌端点和内省端点的错误响应（RFC 6749 5.2）
+type OAuthErrorRsp struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// OAuthIntrospectReq 内省端点参数
+type OAuthIntrospectReq struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientId      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// OAuthIntrospectRsp 内省端点响应（RFC 7662 2.2），令牌无效时只返回 active=false
+type OAuthIntrospectRsp struct {
+	Active    bool   `json:"active"`
+	ClientId  string `json:"client_id,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"` // access 或 refresh
+	Jti       string `json:"jti,omitempty"`
+	Iat       int64  `json:"iat,omitempty"` // 秒
+	Exp       int64  `json:"exp,omitempty"`
+}
+
+// OAuthMe 当前令牌代表的调用方
+type OAuthMe struct {
+	UserId   int64    `json:"userId"`
+	ClientId string   `json:"clientId,omitempty"`
+	KeyId    string   `json:"keyId,omitempty"` // 以 API Key 调用时为 Key 的前缀
+	Scopes   []string `json:"scopes"`
+	ExpireAt int64    `json:"expireAt"`
+}
+
+// QueryAuditEventsReq 审计事件查询参数，零值表示不限制
+type QueryAuditEventsReq struct {
+	From    int64  `form:"from"` // 毫秒时间戳，包含
+	To      int64  `form:"to"`   // 毫秒时间戳，不包含
+	UserId  int64  `form:"userId"`
+	Type    string `form:"type"`
+	Outcome string `form:"outcome"`
+	Limit   int32  `form:"limit"`
+}
+
+// AuditEvent 审计事件
+type AuditEvent struct {
+	Id         string `json:"id"`
+	Time       int64  `json:"time"`
+	Type       string `json:"type"`
+	Outcome    string `json:"outcome"`
+	ActorId    int64  `json:"actorId,omitempty"`
+	TargetId   int64  `json:"targetId,omitempty"`
+	Account    string `json:"account,omitempty"`
+	ClientId   string `json:"clientId,omitempty"`
+	DeviceId   string `json:"deviceId,omitempty"`
+	Ip         string `json:"ip,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	ReasonCode int32  `json:"reasonCode,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// UserRoleReq 分配或收回角色的请求参数，scope 为空表示全局，否则为 tenant:<id> 或 group:<id>
+type UserRoleReq struct {
+	UserId int64  `json:"userId" form:"userId" binding:"required"`
+	Role   string `json:"role" form:"role" binding:"required"`
+	Scope  string `json:"scope" form:"scope"`
+}
+
+// ListUserRolesReq 查询账号角色的请求参数
+type ListUserRolesReq struct {
+	UserId int64 `form:"userId" binding:"required"`
+}
+
+// UserRole 账号拥有的角色
+type UserRole struct {
+	UserId     int64  `json:"userId"`
+	Role       string `json:"role"`
+	Scope      string `json:"scope"`
+	GrantedBy  int64  `json:"grantedBy"`
+	CreateTime int64  `json:"createTime"`
+}
+
+// ServiceAccountReq 创建服务账号请求参数
+type ServiceAccountReq struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ServiceAccountIdReq 按 ID 操作服务账号的请求参数
+type ServiceAccountIdReq struct {
+	AccountId int64 `json:"accountId" form:"accountId" binding:"required"`
+}
+
+// ServiceAccount 服务账号
+type ServiceAccount struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	CreateTime int64  `json:"createTime"`
+}
+
+// ApiKeyReq 创建 API Key 请求参数
+type ApiKeyReq struct {
+	AccountId int64    `json:"accountId"` // 服务账号 ID，为空时为自己创建
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required"`
+	ExpireIn  int64    `json:"expireIn"` // 有效期（秒），为空时使用默认有效期
+}
+
+// ListApiKeysReq 查询 API Key 的请求参数
+type ListApiKeysReq struct {
+	AccountId int64 `form:"accountId"` // 服务账号 ID，为空时查询自己的 Key
+}
+
+// ApiKeyPrefixReq 按前缀操作 API Key 的请求参数
+type ApiKeyPrefixReq struct {
+	Prefix string `json:"prefix" form:"prefix" binding:"required"`
+}
+
+// ApiKey API Key 信息，Key 只在创建和轮换时返回
+type ApiKey struct {
+	Prefix       string   `json:"prefix"`
+	Key          string   `json:"key,omitempty"`
+	OwnerId      int64    `json:"ownerId"`
+	Name         string   `json:"name"`
+	Scopes       []string `json:"scopes"`
+	ExpireTime   int64    `json:"expireTime"`
+	LastUsedTime int64    `json:"lastUsedTime"`
+	LastUsedIp   string   `json:"lastUsedIp"`
+	CreateTime   int64    `json:"createTime"`
+	RotateTime   int64    `json:"rotateTime"`
+	RevokeTime   int64    `json:"revokeTime"`
+}
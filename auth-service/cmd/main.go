@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"github.com/MortalSC/IM-System/auth-service/config"
+	"github.com/MortalSC/IM-System/auth-service/internal/database"
 	"github.com/MortalSC/IM-System/auth-service/internal/router"
+	libLog "github.com/MortalSC/IM-System/lib/log"
 	srv "github.com/MortalSC/IM-System/lib/router"
 	"github.com/gin-gonic/gin"
+	"log"
 )
 
 func main() {
@@ -13,9 +18,27 @@ func main() {
 	// 路由
 	router.InitRouter(r)
 
-	gc := router.RegisterGrpc()
+	// 数据库：连接后先执行尚未执行的表结构迁移
+	db, err := database.Open(config.Cfg.DbCfg)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	if db != nil {
+		applied, err := database.Migrate(context.Background(), db)
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		if len(applied) > 0 {
+			libLog.IMLog.Info(fmt.Sprintf("数据库迁移完成，执行版本: %v", applied))
+		}
+	}
+
+	gc := router.RegisterGrpc(db)
 	stop := func() {
 		gc.Stop()
+		if db != nil {
+			_ = db.Close()
+		}
 	}
 
 	// 启动服务/中止 + grpc服务停止
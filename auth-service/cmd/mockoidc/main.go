@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"github.com/MortalSC/IM-System/auth-service/internal/oidc/mock"
+	"log"
+	"net/http"
+)
+
+// 本地模拟 OIDC 身份提供方，默认参数与 auth-service/config/config.yaml 中的 mock 提供方一致
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9098", "监听地址")
+	issuer := flag.String("issuer", "http://127.0.0.1:9098", "签发方")
+	clientId := flag.String("client-id", "im-system", "客户端 ID")
+	clientSecret := flag.String("client-secret", "im-system-secret", "客户端密钥")
+	redirectUrl := flag.String("redirect-url", "", "允许的回调地址，为空时不限制")
+	flag.Parse()
+
+	s, err := mock.New(&mock.Config{
+		Issuer:       *issuer,
+		ClientId:     *clientId,
+		ClientSecret: *clientSecret,
+		RedirectUrl:  *redirectUrl,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize mock oidc provider: %v", err)
+	}
+	log.Printf("mock oidc provider listening on %s, issuer %s", *addr, *issuer)
+	log.Fatal(http.ListenAndServe(*addr, s.Handler()))
+}
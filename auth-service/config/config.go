@@ -1,6 +1,24 @@
 package config
 
 import (
+	"github.com/MortalSC/IM-System/auth-service/internal/account"
+	"github.com/MortalSC/IM-System/auth-service/internal/apikey"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/captcha"
+	"github.com/MortalSC/IM-System/auth-service/internal/humancheck"
+	"github.com/MortalSC/IM-System/auth-service/internal/mail"
+	"github.com/MortalSC/IM-System/auth-service/internal/oauth"
+	"github.com/MortalSC/IM-System/auth-service/internal/oidc"
+	"github.com/MortalSC/IM-System/auth-service/internal/password"
+	"github.com/MortalSC/IM-System/auth-service/internal/phone"
+	"github.com/MortalSC/IM-System/auth-service/internal/role"
+	"github.com/MortalSC/IM-System/auth-service/internal/session"
+	"github.com/MortalSC/IM-System/auth-service/internal/sms"
+	"github.com/MortalSC/IM-System/auth-service/internal/totp"
+	gatewayPush "github.com/MortalSC/IM-System/gateway-service/pkg/push"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	libDatabase "github.com/MortalSC/IM-System/lib/database"
+	"github.com/MortalSC/IM-System/lib/jwts"
 	libLog "github.com/MortalSC/IM-System/lib/log"
 	"github.com/go-redis/redis/v8"
 	"github.com/spf13/viper"
@@ -11,9 +29,29 @@ import (
 var Cfg = InitConfig()
 
 type Config struct {
-	viper  *viper.Viper
-	SrvCfg *ServerConfig
-	GC     *GrpcConfig
+	viper       *viper.Viper
+	SrvCfg      *ServerConfig
+	GC          *GrpcConfig
+	CaptchaCfg  *captcha.Config
+	SmsCfg      *sms.Config
+	MailCfg     *mail.Config
+	JwtCfg      *jwts.Config
+	SessionCfg  *session.Config
+	PasswordCfg *password.Config
+	DbCfg       *libDatabase.Config
+	TotpCfg     *totp.Config
+	PhoneCfg    *phone.Config
+	OidcCfg     *oidc.Config
+	OAuthCfg    *oauth.Config
+	HumanCfg    *humancheck.Config
+	AuditCfg    *audit.Config
+	RoleCfg     *role.Config
+	ApiKeyCfg   *apikey.Config
+	AccountCfg  *account.Config
+	RouteCfg    *route.Config
+	GatewayCfg  *gatewayPush.Config
+	EtcdCfg     *EtcdConfig
+	PurgeCfg    *PurgeConfig
 }
 
 func InitConfig() *Config {
@@ -37,10 +75,48 @@ func InitConfig() *Config {
 	conf.InitServerConfig()
 	// 读取日志配置
 	conf.InitZapLog()
+	// 读取审计日志配置
+	conf.InitAuditConfig()
 	// 读取redis配置
 	conf.InitRedisOptions()
 	// 读取grpc配置
 	conf.InitGrpcConfig()
+	// 读取验证码配置
+	conf.InitCaptchaConfig()
+	// 读取人机验证配置
+	conf.InitHumanCheckConfig()
+	// 读取短信配置
+	conf.InitSmsConfig()
+	// 读取邮件配置
+	conf.InitMailConfig()
+	// 读取令牌配置
+	conf.InitJwtConfig()
+	// 读取会话配置，会话有效期与刷新令牌一致，需在令牌配置之后读取
+	conf.InitSessionConfig()
+	// 读取密码配置
+	conf.InitPasswordConfig()
+	// 读取数据库配置
+	conf.InitDatabaseConfig()
+	// 读取两步验证配置
+	conf.InitTotpConfig()
+	// 读取手机号配置
+	conf.InitPhoneConfig()
+	// 读取第三方登录配置
+	conf.InitOidcConfig()
+	// 读取开放平台授权配置
+	conf.InitOAuthConfig()
+	// 读取角色权限配置
+	conf.InitRoleConfig()
+	// 读取 API Key 配置
+	conf.InitApiKeyConfig()
+	// 读取账号注销配置
+	conf.InitAccountConfig()
+	// 读取网关路由表配置，下线设备时断开设备的长连接
+	conf.InitGatewayConfig()
+	// 读取服务发现配置
+	conf.InitEtcdConfig()
+	// 读取账号清除时需要同时清除数据的服务
+	conf.InitPurgeConfig()
 
 	return conf
 }
@@ -74,6 +150,20 @@ func (c *Config) InitZapLog() {
 	}
 }
 
+func (c *Config) InitAuditConfig() {
+	ac := &audit.Config{}
+	ac.Log = libLog.AuditConfig{
+		FileName:   c.viper.GetString("audit.fileName"),
+		MaxSize:    c.viper.GetInt("audit.maxSize"),
+		MaxAge:     c.viper.GetInt("audit.maxAge"),
+		MaxBackups: c.viper.GetInt("audit.maxBackups"),
+		Compress:   c.viper.GetBool("audit.compress"),
+	}
+	ac.QueryLimit = c.viper.GetInt("audit.queryLimit")
+	ac.QueryMaxLimit = c.viper.GetInt("audit.queryMaxLimit")
+	c.AuditCfg = ac
+}
+
 func (c *Config) InitRedisOptions() *redis.Options {
 	return &redis.Options{
 		Addr:     c.viper.GetString("redis.host") + ":" + c.viper.GetString("redis.port"),
@@ -93,3 +183,245 @@ func (c *Config) InitGrpcConfig() {
 	gc.Addr = c.viper.GetString("grpc.addr")
 	c.GC = gc
 }
+
+func (c *Config) InitCaptchaConfig() {
+	cc := &captcha.Config{}
+	cc.Length = c.viper.GetInt("captcha.length")
+	if cc.Length != 4 && cc.Length != 6 {
+		log.Fatalf("captcha.length must be 4 or 6, got %d", cc.Length)
+	}
+	cc.Expire = c.viper.GetDuration("captcha.expire")
+	cc.DevMode = c.viper.GetBool("captcha.devMode")
+	cc.Interval = c.viper.GetDuration("captcha.interval")
+	cc.MobileDailyLimit = c.viper.GetInt64("captcha.mobileDailyLimit")
+	cc.IpDailyLimit = c.viper.GetInt64("captcha.ipDailyLimit")
+	cc.MaxAttempts = c.viper.GetInt64("captcha.maxAttempts")
+	// 上限为 0 或负数时频控和错误次数限制会失效，启动时直接报错
+	for key, v := range map[string]int64{
+		"captcha.mobileDailyLimit": cc.MobileDailyLimit,
+		"captcha.ipDailyLimit":     cc.IpDailyLimit,
+		"captcha.maxAttempts":      cc.MaxAttempts,
+	} {
+		if v <= 0 {
+			log.Fatalf("%s must be positive, got %d", key, v)
+		}
+	}
+	c.CaptchaCfg = cc
+}
+
+func (c *Config) InitSmsConfig() {
+	sc := &sms.Config{}
+	sc.Provider = c.viper.GetString("sms.provider")
+	sc.SpoolFile = c.viper.GetString("sms.spoolFile")
+	sc.HttpSink = c.viper.GetString("sms.httpSink")
+	sc.Timeout = c.viper.GetDuration("sms.timeout")
+	sc.Retry = c.viper.GetInt("sms.retry")
+	sc.Backoff = c.viper.GetDuration("sms.backoff")
+	sc.Templates = c.viper.GetStringMapString("sms.templates")
+	c.SmsCfg = sc
+}
+
+func (c *Config) InitMailConfig() {
+	mc := &mail.Config{}
+	mc.Provider = c.viper.GetString("mail.provider")
+	mc.Maildir = c.viper.GetString("mail.maildir")
+	mc.From = c.viper.GetString("mail.from")
+	mc.DefaultLocale = c.viper.GetString("mail.defaultLocale")
+	mc.Timeout = c.viper.GetDuration("mail.timeout")
+	mc.Retry = c.viper.GetInt("mail.retry")
+	mc.Backoff = c.viper.GetDuration("mail.backoff")
+	mc.TemplateDir = c.viper.GetString("mail.templateDir")
+	mc.Smtp = mail.SmtpConfig{
+		Host:     c.viper.GetString("mail.smtp.host"),
+		Port:     c.viper.GetInt("mail.smtp.port"),
+		Username: c.viper.GetString("mail.smtp.username"),
+		Password: c.viper.GetString("mail.smtp.password"),
+		Tls:      c.viper.GetString("mail.smtp.tls"),
+	}
+	c.MailCfg = mc
+}
+
+func (c *Config) InitJwtConfig() {
+	jc := &jwts.Config{}
+	jc.Algorithm = c.viper.GetString("jwt.algorithm")
+	jc.Secret = c.viper.GetString("jwt.secret")
+	jc.PrivateKeyFile = c.viper.GetString("jwt.privateKeyFile")
+	jc.PublicKeyFile = c.viper.GetString("jwt.publicKeyFile")
+	jc.KeyId = c.viper.GetString("jwt.keyId")
+	jc.Issuer = c.viper.GetString("jwt.issuer")
+	jc.AccessExpire = c.viper.GetDuration("jwt.accessExpire")
+	jc.RefreshExpire = c.viper.GetDuration("jwt.refreshExpire")
+	c.JwtCfg = jc
+}
+
+func (c *Config) InitSessionConfig() {
+	sc := &session.Config{}
+	sc.Expire = c.viper.GetDuration("jwt.refreshExpire")
+	sc.TouchInterval = c.viper.GetDuration("session.touchInterval")
+	sc.DefaultLimit = c.viper.GetInt("session.defaultLimit")
+	sc.Limits = make(map[string]int)
+	for group := range c.viper.GetStringMap("session.limits") {
+		sc.Limits[group] = c.viper.GetInt("session.limits." + group)
+	}
+	sc.Groups = c.viper.GetStringMapString("session.groups")
+	c.SessionCfg = sc
+}
+
+func (c *Config) InitPasswordConfig() {
+	pc := &password.Config{}
+	pc.Algorithm = c.viper.GetString("password.algorithm")
+	pc.Argon2Memory = c.viper.GetUint32("password.argon2Memory")
+	pc.Argon2Time = c.viper.GetUint32("password.argon2Time")
+	pc.Argon2Threads = uint8(c.viper.GetUint("password.argon2Threads"))
+	pc.BcryptCost = c.viper.GetInt("password.bcryptCost")
+	pc.MinLength = c.viper.GetInt("password.minLength")
+	pc.MaxLength = c.viper.GetInt("password.maxLength")
+	pc.MinClasses = c.viper.GetInt("password.minClasses")
+	pc.Blacklist = c.viper.GetStringSlice("password.blacklist")
+	pc.MaxAttempts = c.viper.GetInt64("password.maxAttempts")
+	pc.FailWindow = c.viper.GetDuration("password.failWindow")
+	pc.LockDuration = c.viper.GetDuration("password.lockDuration")
+	c.PasswordCfg = pc
+}
+
+func (c *Config) InitDatabaseConfig() {
+	dc := &libDatabase.Config{}
+	dc.Driver = c.viper.GetString("database.driver")
+	dc.Dsn = c.viper.GetString("database.dsn")
+	dc.MaxOpenConns = c.viper.GetInt("database.maxOpenConns")
+	dc.MaxIdleConns = c.viper.GetInt("database.maxIdleConns")
+	dc.ConnMaxLifetime = c.viper.GetDuration("database.connMaxLifetime")
+	c.DbCfg = dc
+}
+
+func (c *Config) InitTotpConfig() {
+	tc := &totp.Config{}
+	tc.Issuer = c.viper.GetString("totp.issuer")
+	tc.SecretKey = c.viper.GetString("totp.secretKey")
+	tc.DevMode = c.viper.GetBool("totp.devMode")
+	tc.Skew = c.viper.GetInt("totp.skew")
+	tc.RecoveryCodes = c.viper.GetInt("totp.recoveryCodes")
+	tc.ChallengeExpire = c.viper.GetDuration("totp.challengeExpire")
+	tc.ChallengeAttempts = c.viper.GetInt64("totp.challengeAttempts")
+	c.TotpCfg = tc
+}
+
+func (c *Config) InitPhoneConfig() {
+	pc := &phone.Config{}
+	pc.DefaultRegion = c.viper.GetString("phone.defaultRegion")
+	pc.AllowedRegions = c.viper.GetStringSlice("phone.allowedRegions")
+	c.PhoneCfg = pc
+}
+
+func (c *Config) InitOidcConfig() {
+	oc := &oidc.Config{}
+	oc.StateExpire = c.viper.GetDuration("oidc.stateExpire")
+	oc.HttpTimeout = c.viper.GetDuration("oidc.httpTimeout")
+	oc.Providers = make(map[string]*oidc.ProviderConfig)
+	for name := range c.viper.GetStringMap("oidc.providers") {
+		key := "oidc.providers." + name
+		oc.Providers[name] = &oidc.ProviderConfig{
+			DisplayName:  c.viper.GetString(key + ".displayName"),
+			Issuer:       c.viper.GetString(key + ".issuer"),
+			ClientId:     c.viper.GetString(key + ".clientId"),
+			ClientSecret: c.viper.GetString(key + ".clientSecret"),
+			RedirectUrl:  c.viper.GetString(key + ".redirectUrl"),
+			Scopes:       c.viper.GetStringSlice(key + ".scopes"),
+			AutoRegister: c.viper.GetBool(key + ".autoRegister"),
+			TrustPhone:   c.viper.GetBool(key + ".trustPhone"),
+			TrustEmail:   c.viper.GetBool(key + ".trustEmail"),
+		}
+	}
+	c.OidcCfg = oc
+}
+
+func (c *Config) InitHumanCheckConfig() {
+	c.HumanCfg = &humancheck.Config{
+		Enabled:         c.viper.GetBool("humanCheck.enabled"),
+		Expire:          c.viper.GetDuration("humanCheck.expire"),
+		PassExpire:      c.viper.GetDuration("humanCheck.passExpire"),
+		SliderTolerance: c.viper.GetInt("humanCheck.sliderTolerance"),
+		MinSolveTime:    c.viper.GetDuration("humanCheck.minSolveTime"),
+		RiskMedium:      c.viper.GetInt("humanCheck.riskMedium"),
+		RiskHigh:        c.viper.GetInt("humanCheck.riskHigh"),
+		RiskBlock:       c.viper.GetInt("humanCheck.riskBlock"),
+		Risk: &humancheck.RiskConfig{
+			TrustedCidrs:      c.viper.GetStringSlice("humanCheck.risk.trustedCidrs"),
+			BlockedCidrs:      c.viper.GetStringSlice("humanCheck.risk.blockedCidrs"),
+			IpHourlyLimit:     c.viper.GetInt64("humanCheck.risk.ipHourlyLimit"),
+			DeviceHourlyLimit: c.viper.GetInt64("humanCheck.risk.deviceHourlyLimit"),
+		},
+	}
+}
+
+func (c *Config) InitOAuthConfig() {
+	c.OAuthCfg = &oauth.Config{
+		CodeExpire: c.viper.GetDuration("oauth.codeExpire"),
+		MaxClients: c.viper.GetInt("oauth.maxClients"),
+	}
+}
+
+func (c *Config) InitRoleConfig() {
+	rc := &role.Config{}
+	if err := c.viper.UnmarshalKey("rbac.roles", &rc.Roles); err != nil {
+		log.Fatalf("rbac.roles: %v", err)
+	}
+	for _, id := range c.viper.GetIntSlice("rbac.admins") {
+		rc.Admins = append(rc.Admins, int64(id))
+	}
+	c.RoleCfg = rc
+}
+
+func (c *Config) InitApiKeyConfig() {
+	c.ApiKeyCfg = &apikey.Config{
+		MaxKeys:            c.viper.GetInt("apiKey.maxKeys"),
+		MaxServiceAccounts: c.viper.GetInt("apiKey.maxServiceAccounts"),
+		DefaultExpire:      c.viper.GetDuration("apiKey.defaultExpire"),
+		MaxExpire:          c.viper.GetDuration("apiKey.maxExpire"),
+		TouchInterval:      c.viper.GetDuration("apiKey.touchInterval"),
+	}
+}
+
+func (c *Config) InitAccountConfig() {
+	c.AccountCfg = &account.Config{
+		GracePeriod:   c.viper.GetDuration("account.gracePeriod"),
+		PurgeInterval: c.viper.GetDuration("account.purgeInterval"),
+		PurgeBatch:    c.viper.GetInt("account.purgeBatch"),
+	}
+}
+
+func (c *Config) InitGatewayConfig() {
+	c.RouteCfg = &route.Config{
+		RouteTTL: c.viper.GetDuration("route.routeTTL"),
+		NodeTTL:  c.viper.GetDuration("route.nodeTTL"),
+	}
+	c.GatewayCfg = &gatewayPush.Config{
+		Timeout: c.viper.GetDuration("gateway.timeout"),
+	}
+}
+
+// EtcdConfig 服务发现配置
+type EtcdConfig struct {
+	Addrs []string
+}
+
+func (c *Config) InitEtcdConfig() {
+	ec := &EtcdConfig{}
+	ec.Addrs = c.viper.GetStringSlice("etcd.addrs")
+	c.EtcdCfg = ec
+}
+
+// PurgeConfig 账号清除时同时清除数据的服务，均为 etcd 中注册的服务名，为空时不清除该服务中的数据
+type PurgeConfig struct {
+	GroupService    string
+	MessageService  string
+	PresenceService string
+}
+
+func (c *Config) InitPurgeConfig() {
+	pc := &PurgeConfig{}
+	pc.GroupService = c.viper.GetString("purge.groupService")
+	pc.MessageService = c.viper.GetString("purge.messageService")
+	pc.PresenceService = c.viper.GetString("purge.presenceService")
+	c.PurgeCfg = pc
+}
@@ -0,0 +1,167 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strconv"
+	"time"
+)
+
+const (
+	purgeLockPrefix = "ACCOUNT_PURGE_" // ACCOUNT_PURGE_<userId> -> 清除中的占位，避免多个实例同时清除同一账号
+	purgeLockExpire = 10 * time.Minute
+)
+
+// Config 账号注销配置
+type Config struct {
+	GracePeriod   time.Duration // 注销冷静期，期间可登录并撤销注销，结束后清除数据
+	PurgeInterval time.Duration // 清除任务的执行间隔，0 表示不在本实例运行清除任务
+	PurgeBatch    int           // 每次最多清除的账号数
+}
+
+// UserData 保存账号数据的模块，清除账号时逐个调用，需可重复执行
+type UserData interface {
+	PurgeUser(ctx context.Context, userId int64) error
+}
+
+// Exporter 保存账号数据的其他服务，导出账号数据时逐个调用
+type Exporter interface {
+	// Service 导出数据中该服务的名称
+	Service() string
+	// ExportUser 返回账号在该服务中的全部数据，每条记录为一个 JSON 对象
+	ExportUser(ctx context.Context, userId int64) ([]json.RawMessage, error)
+}
+
+// Manager 账号注销管理：申请注销后进入冷静期，冷静期结束后由清除任务异步删除各模块中的账号数据，最后物理删除账号
+// 审计日志为只追加的安全记录，不随账号清除，按审计日志的保留策略过期
+type Manager struct {
+	users   repo.UserRepository
+	cache   LibCache.Cache
+	cfg     *Config
+	modules []UserData
+}
+
+func NewManager(userRepo repo.UserRepository, cache LibCache.Cache, cfg *Config) *Manager {
+	return &Manager{users: userRepo, cache: cache, cfg: cfg}
+}
+
+// Register 登记保存账号数据的模块，需在启动清除任务前调用
+func (m *Manager) Register(modules ...UserData) {
+	m.modules = append(m.modules, modules...)
+}
+
+// Export 导出已登记的模块中实现了 Exporter 的其他服务中的账号数据，按服务名称分组
+func (m *Manager) Export(ctx context.Context, userId int64) (map[string][]json.RawMessage, error) {
+	result := make(map[string][]json.RawMessage)
+	for _, module := range m.modules {
+		exporter, ok := module.(Exporter)
+		if !ok {
+			continue
+		}
+		records, err := exporter.ExportUser(ctx, userId)
+		if err != nil {
+			return nil, internalError(fmt.Sprintf("导出%s服务中的账号数据", exporter.Service()), err)
+		}
+		result[exporter.Service()] = records
+	}
+	return result, nil
+}
+
+// RequestDeletion 申请注销，返回计划清除数据的时间；已申请时返回错误
+func (m *Manager) RequestDeletion(ctx context.Context, user *data.User) (int64, error) {
+	if user.DeletionPending() {
+		return 0, libErrors.GrpcError(errs.ErrDeletionPending, "账号已申请注销")
+	}
+	user.PurgeTime = time.Now().Add(m.cfg.GracePeriod).UnixMilli()
+	if err := m.users.Save(ctx, user); err != nil {
+		return 0, internalError("保存注销申请", err)
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 申请注销，计划清除时间 %s", user.Id, time.UnixMilli(user.PurgeTime).Format(time.RFC3339)))
+	return user.PurgeTime, nil
+}
+
+// CancelDeletion 在冷静期内撤销注销
+func (m *Manager) CancelDeletion(ctx context.Context, user *data.User) error {
+	if !user.DeletionPending() {
+		return libErrors.GrpcError(errs.ErrDeletionNotPending, "账号未申请注销")
+	}
+	user.PurgeTime = 0
+	if err := m.users.Save(ctx, user); err != nil {
+		return internalError("撤销注销申请", err)
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 撤销注销", user.Id))
+	return nil
+}
+
+// Run 按 PurgeInterval 定期清除冷静期已结束的账号，ctx 取消后返回
+func (m *Manager) Run(ctx context.Context) {
+	if m.cfg.PurgeInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.PurgeDue(ctx); err != nil {
+				libLog.IMLog.Error(fmt.Sprintf("清除注销账号出错，原因: %v", err))
+			}
+		}
+	}
+}
+
+// PurgeDue 清除一批冷静期已结束的账号，返回清除成功的数量；单个账号失败或正由其他实例清除时跳过，下次继续清除
+func (m *Manager) PurgeDue(ctx context.Context) (int, error) {
+	ids, err := m.users.ListPurgeDue(ctx, time.Now().UnixMilli(), m.cfg.PurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, id := range ids {
+		ok, err := m.purge(ctx, id)
+		if err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("清除账号 %d 的数据出错，原因: %v", id, err))
+			continue
+		}
+		if ok {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purge 依次清除各模块中的账号数据，全部成功后物理删除账号；其他实例正在清除该账号时跳过并返回 false
+func (m *Manager) purge(ctx context.Context, userId int64) (bool, error) {
+	lock := purgeLockPrefix + strconv.FormatInt(userId, 10)
+	ok, err := m.cache.PutNX(ctx, lock, "1", purgeLockExpire)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer m.cache.Delete(context.Background(), lock)
+
+	for _, module := range m.modules {
+		if err = module.PurgeUser(ctx, userId); err != nil {
+			return false, fmt.Errorf("%T: %w", module, err)
+		}
+	}
+	if err = m.users.Purge(ctx, userId); err != nil && !errors.Is(err, repo.ErrUserNotFound) {
+		return false, err
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 的数据已清除", userId))
+	return true, nil
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,400 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/oauth"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Key 格式为 "imk_<prefix>_<secret>"，prefix 和 secret 均为十六进制随机串
+const (
+	keyScheme    = "imk_"
+	prefixBytes  = 6
+	secretBytes  = 32
+	maxNameRunes = 64
+)
+
+// Config API Key 与服务账号配置
+type Config struct {
+	MaxKeys            int           // 每个账号最多持有的有效 Key 数量，0 表示不限制
+	MaxServiceAccounts int           // 每个账号最多创建的服务账号数量，0 表示不限制
+	DefaultExpire      time.Duration // 未指定有效期时的默认有效期，0 表示不过期
+	MaxExpire          time.Duration // 允许的最长有效期，0 表示不限制
+	TouchInterval      time.Duration // 最近使用时间的最小更新间隔，避免每次请求都写存储
+}
+
+// Spec 创建 API Key 的参数
+type Spec struct {
+	AccountId int64 // 服务账号 ID，0 表示为调用者自己创建
+	Name      string
+	Scopes    []string
+	ExpireIn  time.Duration // 0 表示使用默认有效期
+}
+
+// Manager API Key 与服务账号管理
+// 服务账号是普通账号创建的、没有手机号和密码的账号，只能通过 API Key 调用接口；
+// 账号可以管理自己和自己创建的服务账号的 Key，Key 只保存 secret 的哈希，明文只在创建和轮换时返回一次
+type Manager struct {
+	repo  repo.ApiKeyRepository
+	users repo.UserRepository
+	cfg   *Config
+}
+
+func NewManager(keyRepo repo.ApiKeyRepository, userRepo repo.UserRepository, cfg *Config) *Manager {
+	return &Manager{repo: keyRepo, users: userRepo, cfg: cfg}
+}
+
+// CreateServiceAccount 为账号创建服务账号
+func (m *Manager) CreateServiceAccount(ctx context.Context, ownerId int64, name string) (*data.User, error) {
+	if name == "" || utf8.RuneCountInString(name) > maxNameRunes {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, "名称不能为空且不超过 64 个字符")
+	}
+	owner, err := m.users.FindById(ctx, ownerId)
+	if err != nil {
+		return nil, internalError("查询账号", err)
+	}
+	if owner == nil || owner.IsServiceAccount() {
+		// 服务账号不能再创建服务账号
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	if m.cfg.MaxServiceAccounts > 0 {
+		list, err := m.users.ListByOwner(ctx, ownerId)
+		if err != nil {
+			return nil, internalError("查询服务账号", err)
+		}
+		if len(list) >= m.cfg.MaxServiceAccounts {
+			return nil, libErrors.GrpcError(errs.ErrServiceAccountLimit, fmt.Sprintf("最多创建 %d 个服务账号", m.cfg.MaxServiceAccounts))
+		}
+	}
+	now := time.Now().UnixMilli()
+	account := &data.User{Name: name, OwnerId: ownerId, CreateTime: now, UpdateTime: now}
+	if err = m.users.Create(ctx, account); err != nil {
+		return nil, internalError("创建服务账号", err)
+	}
+	return account, nil
+}
+
+// ListServiceAccounts 账号创建的服务账号
+func (m *Manager) ListServiceAccounts(ctx context.Context, ownerId int64) ([]*data.User, error) {
+	list, err := m.users.ListByOwner(ctx, ownerId)
+	if err != nil {
+		return nil, internalError("查询服务账号", err)
+	}
+	return list, nil
+}
+
+// DeleteServiceAccount 注销服务账号，并吊销它的全部 Key
+func (m *Manager) DeleteServiceAccount(ctx context.Context, ownerId, accountId int64) error {
+	account, err := m.ownedAccount(ctx, ownerId, accountId)
+	if err != nil {
+		return err
+	}
+	keys, err := m.repo.ListByOwner(ctx, accountId)
+	if err != nil {
+		return internalError("查询 API Key", err)
+	}
+	now := time.Now().UnixMilli()
+	for _, k := range keys {
+		if k.RevokeTime == 0 {
+			k.RevokeTime = now
+			if err = m.repo.Save(ctx, k); err != nil {
+				return internalError("吊销 API Key", err)
+			}
+		}
+	}
+	// 服务账号没有冷静期，注销后由账号清除任务尽快清除数据
+	account.DeleteTime, account.PurgeTime = now, now
+	if err = m.users.Save(ctx, account); err != nil {
+		return internalError("注销服务账号", err)
+	}
+	return nil
+}
+
+// PurgeUser 清除已注销账号的 API Key，并将它创建的服务账号交给清除任务清除
+func (m *Manager) PurgeUser(ctx context.Context, userId int64) error {
+	if err := m.repo.DeleteByOwner(ctx, userId); err != nil {
+		return err
+	}
+	accounts, err := m.users.ListByOwner(ctx, userId)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	for _, a := range accounts {
+		a.DeleteTime, a.PurgeTime = now, now
+		if err = m.users.Save(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create 创建 API Key，返回 Key 和明文
+func (m *Manager) Create(ctx context.Context, userId int64, spec *Spec) (*data.ApiKey, string, error) {
+	ownerId, err := m.owner(ctx, userId, spec.AccountId)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := m.validateSpec(spec)
+	if err != nil {
+		return nil, "", err
+	}
+	if m.cfg.MaxKeys > 0 {
+		list, err := m.repo.ListByOwner(ctx, ownerId)
+		if err != nil {
+			return nil, "", internalError("查询 API Key", err)
+		}
+		active := 0
+		for _, k := range list {
+			if k.RevokeTime == 0 && !expired(k, time.Now()) {
+				active++
+			}
+		}
+		if active >= m.cfg.MaxKeys {
+			return nil, "", libErrors.GrpcError(errs.ErrApiKeyLimit, fmt.Sprintf("每个账号最多持有 %d 个有效的 API Key", m.cfg.MaxKeys))
+		}
+	}
+
+	random, err := utils.RandomToken(prefixBytes)
+	if err != nil {
+		return nil, "", internalError("生成 API Key 前缀", err)
+	}
+	secret, err := utils.RandomToken(secretBytes)
+	if err != nil {
+		return nil, "", internalError("生成 API Key", err)
+	}
+	key.Prefix, key.OwnerId, key.SecretHash = keyScheme+random, ownerId, hashSecret(secret)
+	if err = m.repo.Create(ctx, key); err != nil {
+		return nil, "", internalError("保存 API Key", err)
+	}
+	return key, key.Prefix + "_" + secret, nil
+}
+
+// List 账号或其服务账号的全部 Key（含已吊销和已过期）
+func (m *Manager) List(ctx context.Context, userId, accountId int64) ([]*data.ApiKey, error) {
+	ownerId, err := m.owner(ctx, userId, accountId)
+	if err != nil {
+		return nil, err
+	}
+	list, err := m.repo.ListByOwner(ctx, ownerId)
+	if err != nil {
+		return nil, internalError("查询 API Key", err)
+	}
+	return list, nil
+}
+
+// Rotate 轮换 Key 的 secret，前缀、授权范围和有效期不变，旧的明文立即失效
+func (m *Manager) Rotate(ctx context.Context, userId int64, prefix string) (*data.ApiKey, string, error) {
+	key, err := m.ownedKey(ctx, userId, prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.RevokeTime != 0 {
+		return nil, "", libErrors.GrpcError(errs.ErrApiKeyRevoked, "API Key 已吊销")
+	}
+	secret, err := utils.RandomToken(secretBytes)
+	if err != nil {
+		return nil, "", internalError("生成 API Key", err)
+	}
+	key.SecretHash, key.RotateTime = hashSecret(secret), time.Now().UnixMilli()
+	if err = m.repo.Save(ctx, key); err != nil {
+		return nil, "", internalError("保存 API Key", err)
+	}
+	return key, key.Prefix + "_" + secret, nil
+}
+
+// Revoke 吊销 Key，记录保留用于审计
+func (m *Manager) Revoke(ctx context.Context, userId int64, prefix string) error {
+	key, err := m.ownedKey(ctx, userId, prefix)
+	if err != nil {
+		return err
+	}
+	if key.RevokeTime != 0 {
+		return nil
+	}
+	key.RevokeTime = time.Now().UnixMilli()
+	if err = m.repo.Save(ctx, key); err != nil {
+		return internalError("吊销 API Key", err)
+	}
+	return nil
+}
+
+// Verify 校验 Key，返回 Key 和所属账号，通过后按 TouchInterval 记录最近使用时间和 IP
+// Key 所属账号已注销时同样视为无效
+func (m *Manager) Verify(ctx context.Context, plain, ip string) (*data.ApiKey, *data.User, error) {
+	prefix, secret, ok := parseKey(plain)
+	if !ok {
+		return nil, nil, libErrors.GrpcError(errs.ErrApiKeyInvalid, "API Key 无效")
+	}
+	key, err := m.repo.Find(ctx, prefix)
+	if err != nil {
+		return nil, nil, internalError("查询 API Key", err)
+	}
+	// Key 不存在时也比较一次，避免通过耗时区分
+	expected := hashSecret("")
+	if key != nil {
+		expected = key.SecretHash
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(expected)) != 1 || key == nil {
+		return nil, nil, libErrors.GrpcError(errs.ErrApiKeyInvalid, "API Key 无效")
+	}
+	now := time.Now()
+	if key.RevokeTime != 0 {
+		return nil, nil, libErrors.GrpcError(errs.ErrApiKeyRevoked, "API Key 已吊销")
+	}
+	if expired(key, now) {
+		return nil, nil, libErrors.GrpcError(errs.ErrApiKeyExpired, "API Key 已过期")
+	}
+	owner, err := m.activeOwner(ctx, key.OwnerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if now.Sub(time.UnixMilli(key.LastUsedTime)) >= m.cfg.TouchInterval || key.LastUsedIp != ip {
+		if err = m.repo.Touch(ctx, key.Prefix, now.UnixMilli(), ip); err != nil {
+			libLog.IMLog.Warn(fmt.Sprintf("记录 API Key 使用时间出错，原因: %v", err))
+		}
+	}
+	return key, owner, nil
+}
+
+// activeOwner 查询 Key 所属账号，账号或服务账号的创建者已注销、申请注销时 Key 不可用
+func (m *Manager) activeOwner(ctx context.Context, ownerId int64) (*data.User, error) {
+	owner, err := m.users.FindById(ctx, ownerId)
+	if err != nil {
+		return nil, internalError("查询账号", err)
+	}
+	if owner == nil || owner.PurgeTime != 0 {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalid, "API Key 无效")
+	}
+	if owner.IsServiceAccount() {
+		if _, err = m.activeOwner(ctx, owner.OwnerId); err != nil {
+			return nil, err
+		}
+	}
+	return owner, nil
+}
+
+// owner 确定 Key 所属账号：accountId 为 0 或调用者自己时为调用者，否则需为调用者创建的服务账号
+func (m *Manager) owner(ctx context.Context, userId, accountId int64) (int64, error) {
+	if accountId == 0 || accountId == userId {
+		return userId, nil
+	}
+	if _, err := m.ownedAccount(ctx, userId, accountId); err != nil {
+		return 0, err
+	}
+	return accountId, nil
+}
+
+// ownedAccount 查询调用者创建的服务账号，不存在或不属于调用者时返回同样的错误
+func (m *Manager) ownedAccount(ctx context.Context, userId, accountId int64) (*data.User, error) {
+	account, err := m.users.FindById(ctx, accountId)
+	if err != nil {
+		return nil, internalError("查询服务账号", err)
+	}
+	if account == nil || account.OwnerId != userId {
+		return nil, libErrors.GrpcError(errs.ErrServiceAccountNotExist, "服务账号不存在")
+	}
+	return account, nil
+}
+
+// ownedKey 查询调用者或其服务账号的 Key，不存在或无权管理时返回同样的错误
+func (m *Manager) ownedKey(ctx context.Context, userId int64, prefix string) (*data.ApiKey, error) {
+	key, err := m.repo.Find(ctx, prefix)
+	if err != nil {
+		return nil, internalError("查询 API Key", err)
+	}
+	if key == nil {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyNotExist, "API Key 不存在")
+	}
+	if key.OwnerId != userId {
+		if _, err = m.ownedAccount(ctx, userId, key.OwnerId); err != nil {
+			return nil, libErrors.GrpcError(errs.ErrApiKeyNotExist, "API Key 不存在")
+		}
+	}
+	return key, nil
+}
+
+func (m *Manager) validateSpec(spec *Spec) (*data.ApiKey, error) {
+	if spec.Name == "" || utf8.RuneCountInString(spec.Name) > maxNameRunes {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, "名称不能为空且不超过 64 个字符")
+	}
+	if len(spec.Scopes) == 0 {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, "至少选择一个授权范围")
+	}
+	var scopes []string
+	for _, s := range spec.Scopes {
+		if !oauth.SupportedScope(s) {
+			return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, "不支持的授权范围: "+s)
+		}
+		if !contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	expireIn := spec.ExpireIn
+	if expireIn < 0 {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, "有效期不合法")
+	}
+	if expireIn == 0 {
+		expireIn = m.cfg.DefaultExpire
+	}
+	if m.cfg.MaxExpire > 0 && (expireIn == 0 || expireIn > m.cfg.MaxExpire) {
+		return nil, libErrors.GrpcError(errs.ErrApiKeyInvalidRequest, fmt.Sprintf("有效期不能超过 %s", m.cfg.MaxExpire))
+	}
+
+	now := time.Now()
+	key := &data.ApiKey{Name: spec.Name, Scopes: scopes, CreateTime: now.UnixMilli()}
+	if expireIn > 0 {
+		key.ExpireTime = now.Add(expireIn).UnixMilli()
+	}
+	return key, nil
+}
+
+// parseKey 拆分 "imk_<prefix>_<secret>"，返回的 prefix 包含 "imk_"
+func parseKey(plain string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(plain, keyScheme)
+	if !ok {
+		return "", "", false
+	}
+	random, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(random) != prefixBytes*2 || len(secret) != secretBytes*2 {
+		return "", "", false
+	}
+	return keyScheme + random, secret, true
+}
+
+func expired(key *data.ApiKey, now time.Time) bool {
+	return key.ExpireTime != 0 && now.UnixMilli() >= key.ExpireTime
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
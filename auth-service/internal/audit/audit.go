@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 审计事件类型
+const (
+	TypeCaptchaRequest    = "captcha.request"        // 获取短信/邮件验证码
+	TypeHumanCheck        = "humancheck.verify"      // 提交人机验证
+	TypeLoginCaptcha      = "login.captcha"          // 手机号 + 验证码登录
+	TypeLoginPassword     = "login.password"         // 密码登录
+	TypeLoginTwoFactor    = "login.2fa"              // 登录两步验证
+	TypeLoginOidc         = "login.oidc"             // 第三方登录或绑定回调
+	TypeRegister          = "account.register"       // 注册账号
+	TypePasswordReset     = "password.reset"         // 重置密码
+	TypeEmailBind         = "email.bind"             // 绑定邮箱
+	TypeTokenRefresh      = "token.refresh"          // 刷新令牌
+	TypeTokenRevoke       = "token.revoke"           // 吊销令牌（退出登录）
+	TypeDeviceKick        = "session.kick"           // 下线指定设备
+	TypeLogoutAll         = "session.logout_all"     // 下线全部设备
+	TypeTotpEnroll        = "2fa.enroll"             // 绑定验证器
+	TypeTotpEnable        = "2fa.enable"             // 启用两步验证
+	TypeTotpDisable       = "2fa.disable"            // 关闭两步验证
+	TypeIdentityUnlink    = "oidc.unlink"            // 解除第三方身份关联
+	TypeOAuthClient       = "oauth.client"           // 注册开放平台应用
+	TypeOAuthSecretRotate = "oauth.secret_rotate"    // 轮换应用密钥
+	TypeOAuthClientDelete = "oauth.client_delete"    // 删除开放平台应用
+	TypeOAuthConsent      = "oauth.consent"          // 同意第三方应用授权
+	TypeOAuthRevoke       = "oauth.revoke"           // 撤销第三方应用授权
+	TypeOAuthToken        = "oauth.token"            // 第三方应用获取令牌
+	TypeRoleAssign        = "role.assign"            // 分配角色
+	TypeRoleRevoke        = "role.revoke"            // 收回角色
+	TypeMobileChange      = "account.mobile_change"  // 更换手机号
+	TypeDeletionRequest   = "account.delete"         // 申请注销账号
+	TypeDeletionCancel    = "account.delete_cancel"  // 撤销注销
+	TypeDataExport        = "account.export"         // 导出账号数据
+	TypeApiKeyCreate      = "apikey.create"          // 创建 API Key
+	TypeApiKeyRotate      = "apikey.rotate"          // 轮换 API Key
+	TypeApiKeyRevoke      = "apikey.revoke"          // 吊销 API Key
+	TypeServiceAccount    = "service_account.create" // 创建服务账号
+	TypeServiceAccountDel = "service_account.delete" // 注销服务账号
+)
+
+// 审计事件结果
+const (
+	OutcomeSuccess   = "success"   // 操作成功
+	OutcomeFailure   = "failure"   // 操作失败，ReasonCode 为错误码
+	OutcomeChallenge = "challenge" // 密码或验证码校验通过，等待两步验证
+)
+
+// Event 审计事件，写入审计日志的一行 JSON
+type Event struct {
+	Id         string `json:"id"`
+	Time       int64  `json:"time"` // 事件时间（毫秒时间戳）
+	Type       string `json:"type"`
+	Outcome    string `json:"outcome"`
+	ActorId    int64  `json:"actorId,omitempty"`    // 操作的账号，登录失败等无法确定账号时为 0
+	Account    string `json:"account,omitempty"`    // 请求中的账号标识：用户名、手机号或邮箱
+	ClientId   string `json:"clientId,omitempty"`   // 开放平台应用
+	DeviceId   string `json:"deviceId,omitempty"`   // 登录设备
+	Ip         string `json:"ip,omitempty"`         // 客户端 IP
+	UserAgent  string `json:"userAgent,omitempty"`  // 客户端 User-Agent
+	ReasonCode int32  `json:"reasonCode,omitempty"` // 失败时的错误码
+	Reason     string `json:"reason,omitempty"`     // 失败时的错误信息
+	TargetId   int64  `json:"targetId,omitempty"`   // 管理员操作的目标账号，此时 ActorId 为管理员
+}
+
+// Config 审计日志配置
+type Config struct {
+	Log           libLog.AuditConfig
+	QueryLimit    int // 查询未指定条数时的默认返回条数
+	QueryMaxLimit int // 单次查询的最大返回条数
+}
+
+// Logger 审计日志，事件只追加写入，不提供修改和删除
+type Logger struct {
+	cfg *Config
+	log *zap.Logger
+}
+
+func NewLogger(cfg *Config) *Logger {
+	return &Logger{cfg: cfg, log: libLog.NewAuditLogger(&cfg.Log)}
+}
+
+// Record 写入一条审计事件，未设置的 Id 和 Time 自动补全
+func (l *Logger) Record(e *Event) {
+	if e.Id == "" {
+		e.Id, _ = utils.RandomToken(12)
+	}
+	if e.Time == 0 {
+		e.Time = time.Now().UnixMilli()
+	}
+	fields := []zap.Field{
+		zap.String("id", e.Id),
+		zap.String("outcome", e.Outcome),
+	}
+	if e.ActorId != 0 {
+		fields = append(fields, zap.Int64("actorId", e.ActorId))
+	}
+	if e.TargetId != 0 {
+		fields = append(fields, zap.Int64("targetId", e.TargetId))
+	}
+	for _, f := range []struct{ key, value string }{
+		{"account", e.Account},
+		{"clientId", e.ClientId},
+		{"deviceId", e.DeviceId},
+		{"ip", e.Ip},
+		{"userAgent", e.UserAgent},
+	} {
+		if f.value != "" {
+			fields = append(fields, zap.String(f.key, f.value))
+		}
+	}
+	if e.ReasonCode != 0 {
+		fields = append(fields, zap.Int32("reasonCode", e.ReasonCode), zap.String("reason", e.Reason))
+	}
+	if ce := l.log.Check(zap.InfoLevel, e.Type); ce != nil {
+		ce.Time = time.UnixMilli(e.Time)
+		ce.Write(fields...)
+	}
+}
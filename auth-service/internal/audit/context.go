@@ -0,0 +1,58 @@
+package audit
+
+import "context"
+
+type eventKey struct{}
+
+// withEvent 将处理中的审计事件放入 ctx，供业务代码补充只有处理过程中才能确定的信息
+func withEvent(ctx context.Context, e *Event) context.Context {
+	return context.WithValue(ctx, eventKey{}, e)
+}
+
+func fromContext(ctx context.Context) *Event {
+	e, _ := ctx.Value(eventKey{}).(*Event)
+	return e
+}
+
+// SetActor 设置事件所属的账号，用于请求中不携带账号 ID 的操作，如登录、刷新令牌
+// ctx 中没有审计事件（该方法不需要审计）时不做任何操作，下同
+func SetActor(ctx context.Context, userId int64) {
+	if e := fromContext(ctx); e != nil {
+		e.ActorId = userId
+	}
+}
+
+// SetDevice 设置事件关联的登录设备，用于由服务端生成设备 ID 的登录
+func SetDevice(ctx context.Context, deviceId string) {
+	if e := fromContext(ctx); e != nil {
+		e.DeviceId = deviceId
+	}
+}
+
+// Challenge 标记操作已通过第一步校验，等待两步验证
+func Challenge(ctx context.Context) {
+	if e := fromContext(ctx); e != nil {
+		e.Outcome = OutcomeChallenge
+	}
+}
+
+// SetClient 设置事件关联的开放平台应用，用于应用 ID 嵌套在请求内部的操作
+func SetClient(ctx context.Context, clientId string) {
+	if e := fromContext(ctx); e != nil {
+		e.ClientId = clientId
+	}
+}
+
+// SetOperator 设置执行管理操作的管理员，请求中的账号 ID 作为目标账号
+func SetOperator(ctx context.Context, operatorId int64) {
+	if e := fromContext(ctx); e != nil {
+		e.TargetId, e.ActorId = e.ActorId, operatorId
+	}
+}
+
+// SetTarget 设置操作的目标账号，用于目标账号由服务端生成或嵌套在请求内部的操作，如创建服务账号
+func SetTarget(ctx context.Context, userId int64) {
+	if e := fromContext(ctx); e != nil {
+		e.TargetId = userId
+	}
+}
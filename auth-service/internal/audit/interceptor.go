@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"context"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"strings"
+)
+
+// 调用方（api-center）通过 gRPC metadata 透传的终端用户信息
+const (
+	MetadataClientIp        = "x-client-ip"
+	MetadataClientUserAgent = "x-client-user-agent"
+)
+
+// auditedMethods 需要审计的 RPC 方法名 -> 事件类型，查询类方法（导出账号数据除外）和高频的令牌校验不记录
+var auditedMethods = map[string]string{
+	"GetCaptcha":              TypeCaptchaRequest,
+	"VerifyHumanCheck":        TypeHumanCheck,
+	"Login":                   TypeLoginCaptcha,
+	"PasswordLogin":           TypeLoginPassword,
+	"VerifyTwoFactor":         TypeLoginTwoFactor,
+	"OidcCallback":            TypeLoginOidc,
+	"Register":                TypeRegister,
+	"ResetPassword":           TypePasswordReset,
+	"BindEmail":               TypeEmailBind,
+	"RefreshToken":            TypeTokenRefresh,
+	"RevokeToken":             TypeTokenRevoke,
+	"KickDevice":              TypeDeviceKick,
+	"LogoutAll":               TypeLogoutAll,
+	"EnrollTotp":              TypeTotpEnroll,
+	"ConfirmTotp":             TypeTotpEnable,
+	"DisableTotp":             TypeTotpDisable,
+	"UnlinkIdentity":          TypeIdentityUnlink,
+	"RegisterOAuthClient":     TypeOAuthClient,
+	"RotateOAuthClientSecret": TypeOAuthSecretRotate,
+	"DeleteOAuthClient":       TypeOAuthClientDelete,
+	"ApproveOAuthConsent":     TypeOAuthConsent,
+	"RevokeOAuthConsent":      TypeOAuthRevoke,
+	"OAuthToken":              TypeOAuthToken,
+	"AssignRole":              TypeRoleAssign,
+	"RevokeRole":              TypeRoleRevoke,
+	"ChangeMobile":            TypeMobileChange,
+	"RequestAccountDeletion":  TypeDeletionRequest,
+	"CancelAccountDeletion":   TypeDeletionCancel,
+	"ExportAccountData":       TypeDataExport,
+	"CreateApiKey":            TypeApiKeyCreate,
+	"RotateApiKey":            TypeApiKeyRotate,
+	"RevokeApiKey":            TypeApiKeyRevoke,
+	"CreateServiceAccount":    TypeServiceAccount,
+	"DeleteServiceAccount":    TypeServiceAccountDel,
+}
+
+// UnaryServerInterceptor 为需要审计的 RPC 记录审计事件
+// 账号、设备、IP 等优先从请求消息中提取，业务代码可通过 SetActor 等方法补充，结果和错误码由返回值确定
+func (l *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := info.FullMethod[strings.LastIndexByte(info.FullMethod, '/')+1:]
+		eventType, ok := auditedMethods[method]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		e := newEvent(ctx, eventType, req)
+		rsp, err := handler(withEvent(ctx, e), req)
+		switch {
+		case err != nil:
+			code, reason := libErrors.ParseGrpcError(err)
+			e.Outcome, e.ReasonCode, e.Reason = OutcomeFailure, int32(code), reason
+		case e.Outcome == "":
+			e.Outcome = OutcomeSuccess
+		}
+		l.Record(e)
+		return rsp, err
+	}
+}
+
+// newEvent 从 metadata 和请求消息中提取事件的基础信息
+func newEvent(ctx context.Context, eventType string, req any) *Event {
+	e := &Event{Type: eventType}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		e.Ip = first(md.Get(MetadataClientIp))
+		e.UserAgent = first(md.Get(MetadataClientUserAgent))
+	}
+	if r, ok := req.(interface{ GetIp() string }); ok && e.Ip == "" {
+		e.Ip = r.GetIp()
+	}
+	if r, ok := req.(interface{ GetUserId() int64 }); ok {
+		e.ActorId = r.GetUserId()
+	}
+	if r, ok := req.(interface{ GetDeviceId() string }); ok {
+		e.DeviceId = r.GetDeviceId()
+	}
+	if r, ok := req.(interface{ GetClientId() string }); ok {
+		e.ClientId = r.GetClientId()
+	}
+	e.Account = account(req)
+	return e
+}
+
+// account 请求中的账号标识，按账号、用户名、手机号、邮箱的顺序取第一个不为空的字段
+func account(req any) string {
+	if r, ok := req.(interface{ GetAccount() string }); ok && r.GetAccount() != "" {
+		return r.GetAccount()
+	}
+	if r, ok := req.(interface{ GetUsername() string }); ok && r.GetUsername() != "" {
+		return r.GetUsername()
+	}
+	if r, ok := req.(interface{ GetMobile() string }); ok && r.GetMobile() != "" {
+		return r.GetMobile()
+	}
+	if r, ok := req.(interface{ GetEmail() string }); ok && r.GetEmail() != "" {
+		return r.GetEmail()
+	}
+	return ""
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
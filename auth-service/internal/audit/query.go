@@ -0,0 +1,168 @@
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lumberjack 备份文件名中的时间格式：<name>-<time><ext>，压缩后追加 .gz
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Filter 审计事件查询条件，零值字段表示不限制
+type Filter struct {
+	From    int64 // 起始时间（毫秒时间戳，包含）
+	To      int64 // 截止时间（毫秒时间戳，不包含）
+	ActorId int64
+	Type    string
+	Outcome string
+	Offset  int // 跳过最新的 Offset 条，配合 Limit 分页；分页期间应固定 To，避免新写入的事件导致错位
+	Limit   int
+}
+
+// logFile 审计日志文件，rotated 为备份文件的轮转时间，当前文件为零值
+type logFile struct {
+	path    string
+	rotated time.Time
+}
+
+// Query 按时间倒序查询审计事件，跳过 Offset 条后最多返回 Limit 条
+// 依次读取当前文件和轮转后的备份文件，轮转时间早于 From 的备份文件中不会有符合条件的事件，直接跳过
+func (l *Logger) Query(ctx context.Context, f *Filter) ([]*Event, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = l.cfg.QueryLimit
+	}
+	if l.cfg.QueryMaxLimit > 0 && limit > l.cfg.QueryMaxLimit {
+		limit = l.cfg.QueryMaxLimit
+	}
+
+	files, err := l.files()
+	if err != nil {
+		return nil, err
+	}
+	var events []*Event
+	skip := f.Offset
+	for _, file := range files {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.From > 0 && !file.rotated.IsZero() && file.rotated.UnixMilli() < f.From {
+			break
+		}
+		matched, err := readFile(file.path, f)
+		if err != nil {
+			return nil, err
+		}
+		// 文件内按写入顺序（时间正序）排列，倒序追加
+		for i := len(matched) - 1; i >= 0 && len(events) < limit; i-- {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			events = append(events, matched[i])
+		}
+		if len(events) >= limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+// files 返回当前文件和全部备份文件，按时间从新到旧排列
+func (l *Logger) files() ([]*logFile, error) {
+	name := l.cfg.Log.FileName
+	dir, base := filepath.Split(name)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	var files []*logFile
+	if _, err := os.Stat(name); err == nil {
+		files = append(files, &logFile{path: name})
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if os.IsNotExist(err) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var backups []*logFile
+	for _, entry := range entries {
+		n := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(n, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimSuffix(n, ".gz"), ext)[len(prefix):]
+		rotated, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, &logFile{path: filepath.Join(dir, n), rotated: rotated})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.After(backups[j].rotated) })
+	return append(files, backups...), nil
+}
+
+// readFile 读取一个日志文件中符合条件的事件，无法解析的行（如正在写入的最后一行）忽略
+func readFile(path string, f *Filter) ([]*Event, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// 读取期间文件被轮转或清理
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var events []*Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e := &Event{}
+		if json.Unmarshal(scanner.Bytes(), e) != nil {
+			continue
+		}
+		if f.match(e) {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+func (f *Filter) match(e *Event) bool {
+	switch {
+	case f.From > 0 && e.Time < f.From:
+		return false
+	case f.To > 0 && e.Time >= f.To:
+		return false
+	case f.ActorId != 0 && e.ActorId != f.ActorId:
+		return false
+	case f.Type != "" && e.Type != f.Type:
+		return false
+	case f.Outcome != "" && e.Outcome != f.Outcome:
+		return false
+	}
+	return true
+}
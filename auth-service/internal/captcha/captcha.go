@@ -0,0 +1,163 @@
+package captcha
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"time"
+)
+
+const (
+	codeKeyPrefix        = "REGISTER_"       // REGISTER_<target> -> 验证码
+	failKeyPrefix        = "CAPTCHA_FAIL_"   // CAPTCHA_FAIL_<target> -> 校验失败次数
+	mobileIntervalPrefix = "CAPTCHA_MIN_M_"  // 手机号/邮箱发送间隔计数
+	mobileDailyPrefix    = "CAPTCHA_DAY_M_"  // 手机号/邮箱每日发送计数
+	ipIntervalPrefix     = "CAPTCHA_MIN_IP_" // IP 发送间隔计数
+	ipDailyPrefix        = "CAPTCHA_DAY_IP_" // IP 每日发送计数
+	day                  = 24 * time.Hour
+)
+
+// Config 验证码配置
+type Config struct {
+	Length           int           // 验证码位数（4 或 6）
+	Expire           time.Duration // 验证码有效期
+	DevMode          bool          // 开发模式下在响应中返回验证码
+	Interval         time.Duration // 同一手机号/邮箱/IP 两次获取的最小间隔
+	MobileDailyLimit int64         // 同一手机号/邮箱每日获取上限
+	IpDailyLimit     int64         // 同一 IP 每日获取上限
+	MaxAttempts      int64         // 单个验证码允许的最大错误次数
+}
+
+// Manager 负责验证码的生成、频控与校验，所有状态保存在 lib/cache 中
+// 验证码按接收方 target 保存，target 为 E.164 手机号或小写邮箱，两者格式不会冲突
+type Manager struct {
+	cache LibCache.Cache
+	cfg   *Config
+}
+
+func NewManager(cache LibCache.Cache, cfg *Config) *Manager {
+	return &Manager{cache: cache, cfg: cfg}
+}
+
+// DevMode 是否为开发模式
+func (m *Manager) DevMode() bool {
+	return m.cfg.DevMode
+}
+
+// Expire 验证码有效期
+func (m *Manager) Expire() time.Duration {
+	return m.cfg.Expire
+}
+
+// Issue 检查频控后生成新的验证码并保存，ip 为空时跳过 IP 维度的频控
+func (m *Manager) Issue(ctx context.Context, target, ip string) (string, error) {
+	if err := m.throttle(ctx, target, ip); err != nil {
+		return "", err
+	}
+
+	code, err := utils.RandomDigits(m.cfg.Length)
+	if err != nil {
+		return "", libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if err = m.cache.Put(ctx, codeKeyPrefix+target, code, m.cfg.Expire); err != nil {
+		return "", cacheError("保存验证码", err)
+	}
+	// 新验证码重新计算错误次数
+	if _, err = m.cache.Delete(ctx, failKeyPrefix+target); err != nil {
+		return "", cacheError("重置验证码错误次数", err)
+	}
+	return code, nil
+}
+
+// Verify 校验并消费验证码，同一个验证码只能校验成功一次
+// 错误次数达到上限后验证码立即失效，需要重新获取
+func (m *Manager) Verify(ctx context.Context, target, code string) error {
+	stored, err := m.cache.Get(ctx, codeKeyPrefix+target)
+	if err != nil {
+		return cacheError("读取验证码", err)
+	}
+	if stored == "" {
+		return libErrors.GrpcError(errors.ErrCaptchaNotExist, "验证码不存在或已过期")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(code)) != 1 {
+		fails, err := m.incr(ctx, failKeyPrefix+target, m.cfg.Expire)
+		if err != nil {
+			return cacheError("记录验证码错误次数", err)
+		}
+		if fails >= m.cfg.MaxAttempts {
+			m.invalidate(ctx, target)
+			return libErrors.GrpcError(errors.ErrCaptchaAttemptsExceeded, "验证码错误次数过多，请重新获取")
+		}
+		return libErrors.GrpcError(errors.ErrCaptchaError, fmt.Sprintf("验证码错误，还可尝试 %d 次", m.cfg.MaxAttempts-fails))
+	}
+
+	// 并发请求中只有成功删除的一方校验通过
+	consumed, err := m.cache.Delete(ctx, codeKeyPrefix+target)
+	if err != nil {
+		return cacheError("删除验证码", err)
+	}
+	if !consumed {
+		return libErrors.GrpcError(errors.ErrCaptchaNotExist, "验证码不存在或已过期")
+	}
+	if _, err = m.cache.Delete(ctx, failKeyPrefix+target); err != nil {
+		libLog.IMLog.Warn(fmt.Sprintf("删除验证码错误次数出错，原因: %v", err))
+	}
+	return nil
+}
+
+// rateLimit 固定窗口计数限制：window 时间内最多 limit 次
+type rateLimit struct {
+	key    string
+	window time.Duration
+	limit  int64
+}
+
+// throttle 按接收方和 IP 两个维度做发送间隔和每日上限控制
+func (m *Manager) throttle(ctx context.Context, target, ip string) error {
+	limits := []rateLimit{
+		{mobileIntervalPrefix + target, m.cfg.Interval, 1},
+		{mobileDailyPrefix + target, day, m.cfg.MobileDailyLimit},
+	}
+	if ip != "" {
+		limits = append(limits,
+			rateLimit{ipIntervalPrefix + ip, m.cfg.Interval, 1},
+			rateLimit{ipDailyPrefix + ip, day, m.cfg.IpDailyLimit},
+		)
+	}
+
+	for _, l := range limits {
+		n, err := m.incr(ctx, l.key, l.window)
+		if err != nil {
+			return cacheError("验证码频控计数", err)
+		}
+		if n > l.limit {
+			return libErrors.GrpcError(errors.ErrCaptchaTooFrequent, "验证码获取过于频繁，请稍后再试")
+		}
+	}
+	return nil
+}
+
+// incr 计数加一，首次计数时原子地设置窗口过期时间，避免计数器永不过期
+func (m *Manager) incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return m.cache.IncrExpire(ctx, key, window)
+}
+
+// invalidate 作废验证码及其错误计数
+func (m *Manager) invalidate(ctx context.Context, target string) {
+	for _, key := range []string{codeKeyPrefix + target, failKeyPrefix + target} {
+		if _, err := m.cache.Delete(ctx, key); err != nil {
+			libLog.IMLog.Warn(fmt.Sprintf("作废验证码出错，key: %s，原因: %v", key, err))
+		}
+	}
+}
+
+func cacheError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,241 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func init() {
+	libLog.IMLog = zap.NewNop()
+}
+
+// memCache 测试用的进程内缓存，过期时间按 now 计算，测试通过推进 now 模拟时间流逝
+type memCache struct {
+	mu      sync.Mutex
+	now     time.Time
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+func newMemCache() *memCache {
+	return &memCache{now: time.Unix(1700000000, 0), values: make(map[string]string), expires: make(map[string]time.Time)}
+}
+
+func (c *memCache) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// lookup 读取未过期的值，调用方需持有锁
+func (c *memCache) lookup(key string) (string, bool) {
+	if at, ok := c.expires[key]; ok && !c.now.Before(at) {
+		delete(c.values, key)
+		delete(c.expires, key)
+	}
+	v, ok := c.values[key]
+	return v, ok
+}
+
+func (c *memCache) set(key, value string, expire time.Duration) {
+	c.values[key] = value
+	if expire > 0 {
+		c.expires[key] = c.now.Add(expire)
+	} else {
+		delete(c.expires, key)
+	}
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.lookup(key)
+	return v, nil
+}
+
+func (c *memCache) Put(ctx context.Context, key string, value string, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value, expire)
+	return nil
+}
+
+func (c *memCache) PutNX(ctx context.Context, key string, value string, expire time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lookup(key); ok {
+		return false, nil
+	}
+	c.set(key, value, expire)
+	return true, nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.lookup(key)
+	delete(c.values, key)
+	delete(c.expires, key)
+	return ok, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrExpire(ctx, key, 0)
+}
+
+func (c *memCache) IncrExpire(ctx context.Context, key string, expire time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.lookup(key)
+	n, _ := strconv.ParseInt(v, 10, 64)
+	n++
+	c.values[key] = strconv.FormatInt(n, 10)
+	if _, ok := c.expires[key]; !ok && expire > 0 {
+		c.expires[key] = c.now.Add(expire)
+	}
+	return n, nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lookup(key); ok {
+		c.expires[key] = c.now.Add(expire)
+	}
+	return nil
+}
+
+// 验证码只使用字符串键，哈希操作不会被调用
+func (c *memCache) HSet(ctx context.Context, key, field, value string) error {
+	return errors.New("not implemented")
+}
+
+func (c *memCache) HGet(ctx context.Context, key, field string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (c *memCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *memCache) HDel(ctx context.Context, key, field string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (c *memCache) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (c *memCache) HIncrByKeys(ctx context.Context, keys []string, field string, incr int64) error {
+	return errors.New("not implemented")
+}
+
+func newTestManager() (*Manager, *memCache) {
+	cache := newMemCache()
+	return NewManager(cache, &Config{
+		Length:           6,
+		Expire:           15 * time.Minute,
+		Interval:         time.Minute,
+		MobileDailyLimit: 3,
+		IpDailyLimit:     4,
+		MaxAttempts:      3,
+	}), cache
+}
+
+func assertCode(t *testing.T, err error, want int) {
+	t.Helper()
+	if code, msg := libErrors.ParseGrpcError(err); code != want {
+		t.Fatalf("error code = %d (%s), want %d", code, msg, want)
+	}
+}
+
+func TestIssueInterval(t *testing.T) {
+	ctx := context.Background()
+	m, cache := newTestManager()
+	if _, err := m.Issue(ctx, "+8613800000000", "10.0.0.1"); err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	_, err := m.Issue(ctx, "+8613800000000", "10.0.0.2")
+	assertCode(t, err, errs.ErrCaptchaTooFrequent)
+	_, err = m.Issue(ctx, "+8613800000001", "10.0.0.1")
+	assertCode(t, err, errs.ErrCaptchaTooFrequent)
+
+	cache.advance(time.Minute)
+	if _, err = m.Issue(ctx, "+8613800000000", "10.0.0.1"); err != nil {
+		t.Fatalf("Issue after interval: %v", err)
+	}
+}
+
+func TestIssueDailyLimits(t *testing.T) {
+	ctx := context.Background()
+	m, cache := newTestManager()
+	const target = "user@example.com"
+	for i := int64(0); i < m.cfg.MobileDailyLimit; i++ {
+		if _, err := m.Issue(ctx, target, ""); err != nil {
+			t.Fatalf("Issue #%d: %v", i+1, err)
+		}
+		cache.advance(time.Minute)
+	}
+	_, err := m.Issue(ctx, target, "")
+	assertCode(t, err, errs.ErrCaptchaTooFrequent)
+
+	// 同一 IP 向不同接收方获取，受 IP 每日上限限制
+	for i := int64(0); i < m.cfg.IpDailyLimit; i++ {
+		if _, err = m.Issue(ctx, "+861390000000"+strconv.FormatInt(i, 10), "10.0.0.1"); err != nil {
+			t.Fatalf("Issue from ip #%d: %v", i+1, err)
+		}
+		cache.advance(time.Minute)
+	}
+	_, err = m.Issue(ctx, "+8613999999999", "10.0.0.1")
+	assertCode(t, err, errs.ErrCaptchaTooFrequent)
+
+	cache.advance(day)
+	if _, err = m.Issue(ctx, target, "10.0.0.1"); err != nil {
+		t.Fatalf("Issue on the next day: %v", err)
+	}
+}
+
+func TestVerifyMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager()
+	const target = "+8613800000000"
+	code, err := m.Issue(ctx, target, "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := int64(1); i < m.cfg.MaxAttempts; i++ {
+		assertCode(t, m.Verify(ctx, target, wrong), errs.ErrCaptchaError)
+	}
+	assertCode(t, m.Verify(ctx, target, wrong), errs.ErrCaptchaAttemptsExceeded)
+	// 达到上限后验证码作废，正确的验证码也不再可用
+	assertCode(t, m.Verify(ctx, target, code), errs.ErrCaptchaNotExist)
+}
+
+func TestVerifyConsumesCode(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager()
+	const target = "+8613800000000"
+	code, err := m.Issue(ctx, target, "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if len(code) != m.cfg.Length {
+		t.Fatalf("code length = %d, want %d", len(code), m.cfg.Length)
+	}
+	if err = m.Verify(ctx, target, code); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	assertCode(t, m.Verify(ctx, target, code), errs.ErrCaptchaNotExist)
+}
@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"sort"
+	"sync"
+)
+
+// ApiKeyMemoryDao 进程内存中的 API Key 存储，用于测试和本地调试
+type ApiKeyMemoryDao struct {
+	mu   sync.Mutex
+	keys map[string]*data.ApiKey // prefix -> API Key
+}
+
+func NewApiKeyMemoryDao() *ApiKeyMemoryDao {
+	return &ApiKeyMemoryDao{keys: make(map[string]*data.ApiKey)}
+}
+
+func (d *ApiKeyMemoryDao) Find(ctx context.Context, prefix string) (*data.ApiKey, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	k, ok := d.keys[prefix]
+	if !ok {
+		return nil, nil
+	}
+	return copyApiKey(k), nil
+}
+
+func (d *ApiKeyMemoryDao) ListByOwner(ctx context.Context, ownerId int64) ([]*data.ApiKey, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var list []*data.ApiKey
+	for _, k := range d.keys {
+		if k.OwnerId == ownerId {
+			list = append(list, copyApiKey(k))
+		}
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *ApiKeyMemoryDao) Create(ctx context.Context, key *data.ApiKey) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.keys[key.Prefix] = copyApiKey(key)
+	return nil
+}
+
+func (d *ApiKeyMemoryDao) Save(ctx context.Context, key *data.ApiKey) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.keys[key.Prefix]; !ok {
+		return repo.ErrApiKeyNotFound
+	}
+	d.keys[key.Prefix] = copyApiKey(key)
+	return nil
+}
+
+func (d *ApiKeyMemoryDao) Touch(ctx context.Context, prefix string, usedTime int64, ip string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if k, ok := d.keys[prefix]; ok {
+		k.LastUsedTime, k.LastUsedIp = usedTime, ip
+	}
+	return nil
+}
+
+func (d *ApiKeyMemoryDao) DeleteByOwner(ctx context.Context, ownerId int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for prefix, k := range d.keys {
+		if k.OwnerId == ownerId {
+			delete(d.keys, prefix)
+		}
+	}
+	return nil
+}
+
+func copyApiKey(k *data.ApiKey) *data.ApiKey {
+	c := *k
+	c.Scopes = append([]string(nil), k.Scopes...)
+	return &c
+}
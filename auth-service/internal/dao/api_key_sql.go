@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"strings"
+)
+
+const apiKeyColumns = `prefix, owner_id, name, secret_hash, scopes, expire_time, last_used_time, last_used_ip,
+	create_time, rotate_time, revoke_time`
+
+// ApiKeySqlDao 基于 database/sql 的 API Key 存储
+type ApiKeySqlDao struct {
+	db *sql.DB
+}
+
+func NewApiKeySqlDao(db *sql.DB) *ApiKeySqlDao {
+	return &ApiKeySqlDao{db: db}
+}
+
+func (d *ApiKeySqlDao) Find(ctx context.Context, prefix string) (*data.ApiKey, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = ?", prefix)
+	k, err := scanApiKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return k, err
+}
+
+func (d *ApiKeySqlDao) ListByOwner(ctx context.Context, ownerId int64) ([]*data.ApiKey, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE owner_id = ? ORDER BY create_time", ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.ApiKey
+	for rows.Next() {
+		k, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+func (d *ApiKeySqlDao) Create(ctx context.Context, key *data.ApiKey) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO api_keys (`+apiKeyColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.Prefix, key.OwnerId, key.Name, key.SecretHash, joinFields(key.Scopes), key.ExpireTime, key.LastUsedTime, key.LastUsedIp,
+		key.CreateTime, key.RotateTime, key.RevokeTime)
+	return err
+}
+
+func (d *ApiKeySqlDao) Save(ctx context.Context, key *data.ApiKey) error {
+	res, err := d.db.ExecContext(ctx, `UPDATE api_keys SET name = ?, secret_hash = ?, scopes = ?, expire_time = ?,
+		rotate_time = ?, revoke_time = ? WHERE prefix = ?`,
+		key.Name, key.SecretHash, joinFields(key.Scopes), key.ExpireTime, key.RotateTime, key.RevokeTime, key.Prefix)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return repo.ErrApiKeyNotFound
+	}
+	return err
+}
+
+func (d *ApiKeySqlDao) Touch(ctx context.Context, prefix string, usedTime int64, ip string) error {
+	_, err := d.db.ExecContext(ctx, `UPDATE api_keys SET last_used_time = ?, last_used_ip = ? WHERE prefix = ?`, usedTime, ip, prefix)
+	return err
+}
+
+func (d *ApiKeySqlDao) DeleteByOwner(ctx context.Context, ownerId int64) error {
+	_, err := d.db.ExecContext(ctx, "DELETE FROM api_keys WHERE owner_id = ?", ownerId)
+	return err
+}
+
+func scanApiKey(row interface{ Scan(dest ...any) error }) (*data.ApiKey, error) {
+	k := &data.ApiKey{}
+	var scopes string
+	err := row.Scan(&k.Prefix, &k.OwnerId, &k.Name, &k.SecretHash, &scopes, &k.ExpireTime, &k.LastUsedTime, &k.LastUsedIp,
+		&k.CreateTime, &k.RotateTime, &k.RevokeTime)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = strings.Fields(scopes)
+	return k, nil
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"sort"
+	"sync"
+)
+
+// IdentityMemoryDao 进程内存中的第三方身份关联存储，用于测试和本地调试
+type IdentityMemoryDao struct {
+	mu    sync.Mutex
+	items map[string]*data.Identity // provider + "\x00" + subject -> 关联
+}
+
+func NewIdentityMemoryDao() *IdentityMemoryDao {
+	return &IdentityMemoryDao{items: make(map[string]*data.Identity)}
+}
+
+func (d *IdentityMemoryDao) Find(ctx context.Context, provider, subject string) (*data.Identity, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i, ok := d.items[identityKey(provider, subject)]
+	if !ok {
+		return nil, nil
+	}
+	c := *i
+	return &c, nil
+}
+
+func (d *IdentityMemoryDao) ListByUser(ctx context.Context, userId int64) ([]*data.Identity, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var list []*data.Identity
+	for _, i := range d.items {
+		if i.UserId == userId {
+			c := *i
+			list = append(list, &c)
+		}
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *IdentityMemoryDao) Create(ctx context.Context, identity *data.Identity) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := identityKey(identity.Provider, identity.Subject)
+	if _, ok := d.items[key]; ok {
+		return repo.ErrIdentityExists
+	}
+	for _, i := range d.items {
+		if i.UserId == identity.UserId && i.Provider == identity.Provider {
+			return repo.ErrProviderLinked
+		}
+	}
+	c := *identity
+	d.items[key] = &c
+	return nil
+}
+
+func (d *IdentityMemoryDao) Delete(ctx context.Context, userId int64, provider string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, i := range d.items {
+		if i.UserId == userId && i.Provider == provider {
+			delete(d.items, key)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func identityKey(provider, subject string) string {
+	return provider + "\x00" + subject
+}
@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/mattn/go-sqlite3"
+	"strings"
+)
+
+// IdentitySqlDao 基于 database/sql 的第三方身份关联存储
+type IdentitySqlDao struct {
+	db *sql.DB
+}
+
+func NewIdentitySqlDao(db *sql.DB) *IdentitySqlDao {
+	return &IdentitySqlDao{db: db}
+}
+
+func (d *IdentitySqlDao) Find(ctx context.Context, provider, subject string) (*data.Identity, error) {
+	i := &data.Identity{}
+	err := d.db.QueryRowContext(ctx, `SELECT user_id, provider, subject, email, create_time
+		FROM user_identities WHERE provider = ? AND subject = ?`, provider, subject).
+		Scan(&i.UserId, &i.Provider, &i.Subject, &i.Email, &i.CreateTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (d *IdentitySqlDao) ListByUser(ctx context.Context, userId int64) ([]*data.Identity, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT user_id, provider, subject, email, create_time
+		FROM user_identities WHERE user_id = ? ORDER BY create_time`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.Identity
+	for rows.Next() {
+		i := &data.Identity{}
+		if err = rows.Scan(&i.UserId, &i.Provider, &i.Subject, &i.Email, &i.CreateTime); err != nil {
+			return nil, err
+		}
+		list = append(list, i)
+	}
+	return list, rows.Err()
+}
+
+func (d *IdentitySqlDao) Create(ctx context.Context, identity *data.Identity) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO user_identities (provider, subject, user_id, email, create_time)
+		VALUES (?, ?, ?, ?, ?)`, identity.Provider, identity.Subject, identity.UserId, identity.Email, identity.CreateTime)
+	var se sqlite3.Error
+	if errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return repo.ErrIdentityExists
+	}
+	if errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintUnique {
+		if strings.Contains(se.Error(), "user_identities.user_id") {
+			return repo.ErrProviderLinked
+		}
+		return repo.ErrIdentityExists
+	}
+	return err
+}
+
+func (d *IdentitySqlDao) Delete(ctx context.Context, userId int64, provider string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `DELETE FROM user_identities WHERE user_id = ? AND provider = ?`, userId, provider)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
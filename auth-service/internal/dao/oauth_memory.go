@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// OAuthMemoryDao 进程内存中的 OAuth2 客户端与授权存储，用于测试和本地调试
+type OAuthMemoryDao struct {
+	mu       sync.Mutex
+	clients  map[string]*data.OAuthClient
+	consents map[string]*data.OAuthConsent // userId + "\x00" + clientId -> 授权
+}
+
+func NewOAuthMemoryDao() *OAuthMemoryDao {
+	return &OAuthMemoryDao{
+		clients:  make(map[string]*data.OAuthClient),
+		consents: make(map[string]*data.OAuthConsent),
+	}
+}
+
+func (d *OAuthMemoryDao) FindClient(ctx context.Context, clientId string) (*data.OAuthClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c, ok := d.clients[clientId]
+	if !ok {
+		return nil, nil
+	}
+	return copyClient(c), nil
+}
+
+func (d *OAuthMemoryDao) ListClients(ctx context.Context, ownerId int64) ([]*data.OAuthClient, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var list []*data.OAuthClient
+	for _, c := range d.clients {
+		if c.OwnerId == ownerId {
+			list = append(list, copyClient(c))
+		}
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *OAuthMemoryDao) CreateClient(ctx context.Context, client *data.OAuthClient) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clients[client.ClientId] = copyClient(client)
+	return nil
+}
+
+func (d *OAuthMemoryDao) SaveClient(ctx context.Context, client *data.OAuthClient) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.clients[client.ClientId]; !ok {
+		return repo.ErrClientNotFound
+	}
+	d.clients[client.ClientId] = copyClient(client)
+	return nil
+}
+
+func (d *OAuthMemoryDao) DeleteClient(ctx context.Context, clientId string) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var grantIds []string
+	for key, c := range d.consents {
+		if c.ClientId == clientId {
+			grantIds = append(grantIds, c.GrantId)
+			delete(d.consents, key)
+		}
+	}
+	delete(d.clients, clientId)
+	return grantIds, nil
+}
+
+func (d *OAuthMemoryDao) FindConsent(ctx context.Context, userId int64, clientId string) (*data.OAuthConsent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c, ok := d.consents[consentKey(userId, clientId)]
+	if !ok {
+		return nil, nil
+	}
+	return copyConsent(c), nil
+}
+
+func (d *OAuthMemoryDao) ListConsents(ctx context.Context, userId int64) ([]*data.OAuthConsent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var list []*data.OAuthConsent
+	for _, c := range d.consents {
+		if c.UserId == userId {
+			list = append(list, copyConsent(c))
+		}
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *OAuthMemoryDao) SaveConsent(ctx context.Context, consent *data.OAuthConsent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := consentKey(consent.UserId, consent.ClientId)
+	c := copyConsent(consent)
+	if old, ok := d.consents[key]; ok {
+		c.CreateTime = old.CreateTime
+	}
+	d.consents[key] = c
+	return nil
+}
+
+func (d *OAuthMemoryDao) DeleteConsent(ctx context.Context, userId int64, clientId string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := consentKey(userId, clientId)
+	if _, ok := d.consents[key]; !ok {
+		return false, nil
+	}
+	delete(d.consents, key)
+	return true, nil
+}
+
+func copyClient(c *data.OAuthClient) *data.OAuthClient {
+	n := *c
+	n.RedirectUris = append([]string(nil), c.RedirectUris...)
+	n.Scopes = append([]string(nil), c.Scopes...)
+	n.GrantTypes = append([]string(nil), c.GrantTypes...)
+	return &n
+}
+
+func copyConsent(c *data.OAuthConsent) *data.OAuthConsent {
+	n := *c
+	n.Scopes = append([]string(nil), c.Scopes...)
+	return &n
+}
+
+func consentKey(userId int64, clientId string) string {
+	return strconv.FormatInt(userId, 10) + "\x00" + clientId
+}
@@ -0,0 +1,191 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"strings"
+)
+
+const oauthClientColumns = `client_id, secret_hash, name, owner_id, redirect_uris, scopes, grant_types, grant_id,
+	create_time, update_time`
+
+// OAuthSqlDao 基于 database/sql 的 OAuth2 客户端与授权存储，列表字段以空格分隔保存
+type OAuthSqlDao struct {
+	db *sql.DB
+}
+
+func NewOAuthSqlDao(db *sql.DB) *OAuthSqlDao {
+	return &OAuthSqlDao{db: db}
+}
+
+func (d *OAuthSqlDao) FindClient(ctx context.Context, clientId string) (*data.OAuthClient, error) {
+	row := d.db.QueryRowContext(ctx, `SELECT `+oauthClientColumns+` FROM oauth_clients WHERE client_id = ?`, clientId)
+	c, err := scanClient(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (d *OAuthSqlDao) ListClients(ctx context.Context, ownerId int64) ([]*data.OAuthClient, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT `+oauthClientColumns+` FROM oauth_clients
+		WHERE owner_id = ? ORDER BY create_time`, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.OAuthClient
+	for rows.Next() {
+		c, err := scanClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (d *OAuthSqlDao) CreateClient(ctx context.Context, c *data.OAuthClient) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO oauth_clients (`+oauthClientColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.ClientId, c.SecretHash, c.Name, c.OwnerId, joinFields(c.RedirectUris), joinFields(c.Scopes),
+		joinFields(c.GrantTypes), c.GrantId, c.CreateTime, c.UpdateTime)
+	return err
+}
+
+func (d *OAuthSqlDao) SaveClient(ctx context.Context, c *data.OAuthClient) error {
+	res, err := d.db.ExecContext(ctx, `UPDATE oauth_clients SET secret_hash = ?, name = ?, redirect_uris = ?,
+		scopes = ?, grant_types = ?, grant_id = ?, update_time = ? WHERE client_id = ?`,
+		c.SecretHash, c.Name, joinFields(c.RedirectUris), joinFields(c.Scopes), joinFields(c.GrantTypes),
+		c.GrantId, c.UpdateTime, c.ClientId)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrClientNotFound
+	}
+	return nil
+}
+
+func (d *OAuthSqlDao) DeleteClient(ctx context.Context, clientId string) ([]string, error) {
+	var grantIds []string
+	err := d.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `SELECT grant_id FROM oauth_consents WHERE client_id = ?`, clientId)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id string
+			if err = rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			grantIds = append(grantIds, id)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM oauth_consents WHERE client_id = ?`, clientId); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `DELETE FROM oauth_clients WHERE client_id = ?`, clientId)
+		return err
+	})
+	return grantIds, err
+}
+
+func (d *OAuthSqlDao) FindConsent(ctx context.Context, userId int64, clientId string) (*data.OAuthConsent, error) {
+	c := &data.OAuthConsent{}
+	var scopes string
+	err := d.db.QueryRowContext(ctx, `SELECT user_id, client_id, scopes, grant_id, create_time, update_time
+		FROM oauth_consents WHERE user_id = ? AND client_id = ?`, userId, clientId).
+		Scan(&c.UserId, &c.ClientId, &scopes, &c.GrantId, &c.CreateTime, &c.UpdateTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Scopes = strings.Fields(scopes)
+	return c, nil
+}
+
+func (d *OAuthSqlDao) ListConsents(ctx context.Context, userId int64) ([]*data.OAuthConsent, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT user_id, client_id, scopes, grant_id, create_time, update_time
+		FROM oauth_consents WHERE user_id = ? ORDER BY create_time`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.OAuthConsent
+	for rows.Next() {
+		c := &data.OAuthConsent{}
+		var scopes string
+		if err = rows.Scan(&c.UserId, &c.ClientId, &scopes, &c.GrantId, &c.CreateTime, &c.UpdateTime); err != nil {
+			return nil, err
+		}
+		c.Scopes = strings.Fields(scopes)
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (d *OAuthSqlDao) SaveConsent(ctx context.Context, c *data.OAuthConsent) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO oauth_consents (user_id, client_id, scopes, grant_id, create_time, update_time)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, client_id) DO UPDATE SET scopes = excluded.scopes, grant_id = excluded.grant_id,
+			update_time = excluded.update_time`,
+		c.UserId, c.ClientId, joinFields(c.Scopes), c.GrantId, c.CreateTime, c.UpdateTime)
+	return err
+}
+
+func (d *OAuthSqlDao) DeleteConsent(ctx context.Context, userId int64, clientId string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `DELETE FROM oauth_consents WHERE user_id = ? AND client_id = ?`, userId, clientId)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (d *OAuthSqlDao) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanClient(row rowScanner) (*data.OAuthClient, error) {
+	c := &data.OAuthClient{}
+	var redirectUris, scopes, grantTypes string
+	err := row.Scan(&c.ClientId, &c.SecretHash, &c.Name, &c.OwnerId, &redirectUris, &scopes, &grantTypes,
+		&c.GrantId, &c.CreateTime, &c.UpdateTime)
+	if err != nil {
+		return nil, err
+	}
+	c.RedirectUris, c.Scopes, c.GrantTypes = strings.Fields(redirectUris), strings.Fields(scopes), strings.Fields(grantTypes)
+	return c, nil
+}
+
+func joinFields(list []string) string {
+	return strings.Join(list, " ")
+}
@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"sort"
+	"sync"
+)
+
+// RoleMemoryDao 进程内存中的账号角色存储，用于测试和本地调试
+type RoleMemoryDao struct {
+	mu    sync.Mutex
+	items map[int64][]*data.UserRole // 账号 ID -> 角色
+}
+
+func NewRoleMemoryDao() *RoleMemoryDao {
+	return &RoleMemoryDao{items: make(map[int64][]*data.UserRole)}
+}
+
+func (d *RoleMemoryDao) ListByUser(ctx context.Context, userId int64) ([]*data.UserRole, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list := make([]*data.UserRole, 0, len(d.items[userId]))
+	for _, r := range d.items[userId] {
+		c := *r
+		list = append(list, &c)
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *RoleMemoryDao) Create(ctx context.Context, role *data.UserRole) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, r := range d.items[role.UserId] {
+		if r.Role == role.Role && r.Scope == role.Scope {
+			return repo.ErrRoleAssigned
+		}
+	}
+	c := *role
+	d.items[role.UserId] = append(d.items[role.UserId], &c)
+	return nil
+}
+
+func (d *RoleMemoryDao) Delete(ctx context.Context, userId int64, role, scope string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list := d.items[userId]
+	for i, r := range list {
+		if r.Role == role && r.Scope == scope {
+			d.items[userId] = append(list[:i:i], list[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/mattn/go-sqlite3"
+)
+
+// RoleSqlDao 基于 database/sql 的账号角色存储
+type RoleSqlDao struct {
+	db *sql.DB
+}
+
+func NewRoleSqlDao(db *sql.DB) *RoleSqlDao {
+	return &RoleSqlDao{db: db}
+}
+
+func (d *RoleSqlDao) ListByUser(ctx context.Context, userId int64) ([]*data.UserRole, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT user_id, role, scope, granted_by, create_time
+		FROM user_roles WHERE user_id = ? ORDER BY create_time`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.UserRole
+	for rows.Next() {
+		r := &data.UserRole{}
+		if err = rows.Scan(&r.UserId, &r.Role, &r.Scope, &r.GrantedBy, &r.CreateTime); err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+	return list, rows.Err()
+}
+
+func (d *RoleSqlDao) Create(ctx context.Context, role *data.UserRole) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO user_roles (user_id, role, scope, granted_by, create_time)
+		VALUES (?, ?, ?, ?, ?)`, role.UserId, role.Role, role.Scope, role.GrantedBy, role.CreateTime)
+	var se sqlite3.Error
+	if errors.As(err, &se) && se.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return repo.ErrRoleAssigned
+	}
+	return err
+}
+
+func (d *RoleSqlDao) Delete(ctx context.Context, userId int64, role, scope string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = ? AND role = ? AND scope = ?`, userId, role, scope)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"sync"
+)
+
+// TwoFactorMemoryDao 进程内存中的两步验证存储，用于测试和本地调试
+type TwoFactorMemoryDao struct {
+	mu    sync.Mutex
+	items map[int64]*data.TwoFactor
+	codes map[int64]map[string]bool // 账号 -> 恢复码哈希 -> 是否已使用
+}
+
+func NewTwoFactorMemoryDao() *TwoFactorMemoryDao {
+	return &TwoFactorMemoryDao{
+		items: make(map[int64]*data.TwoFactor),
+		codes: make(map[int64]map[string]bool),
+	}
+}
+
+func (d *TwoFactorMemoryDao) Find(ctx context.Context, userId int64) (*data.TwoFactor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tf, ok := d.items[userId]
+	if !ok {
+		return nil, nil
+	}
+	c := *tf
+	return &c, nil
+}
+
+func (d *TwoFactorMemoryDao) Save(ctx context.Context, tf *data.TwoFactor) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := *tf
+	d.items[tf.UserId] = &c
+	return nil
+}
+
+func (d *TwoFactorMemoryDao) Delete(ctx context.Context, userId int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.items, userId)
+	delete(d.codes, userId)
+	return nil
+}
+
+func (d *TwoFactorMemoryDao) AdvanceStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tf, ok := d.items[userId]
+	if !ok || tf.LastStep >= step {
+		return false, nil
+	}
+	tf.LastStep = step
+	return true, nil
+}
+
+func (d *TwoFactorMemoryDao) ReplaceRecoveryCodes(ctx context.Context, userId int64, hashes []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	codes := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		codes[h] = false
+	}
+	d.codes[userId] = codes
+	return nil
+}
+
+func (d *TwoFactorMemoryDao) UseRecoveryCode(ctx context.Context, userId int64, hash string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	used, ok := d.codes[userId][hash]
+	if !ok || used {
+		return false, nil
+	}
+	d.codes[userId][hash] = true
+	return true, nil
+}
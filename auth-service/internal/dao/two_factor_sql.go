@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"time"
+)
+
+// TwoFactorSqlDao 基于 database/sql 的两步验证存储
+type TwoFactorSqlDao struct {
+	db *sql.DB
+}
+
+func NewTwoFactorSqlDao(db *sql.DB) *TwoFactorSqlDao {
+	return &TwoFactorSqlDao{db: db}
+}
+
+func (d *TwoFactorSqlDao) Find(ctx context.Context, userId int64) (*data.TwoFactor, error) {
+	tf := &data.TwoFactor{}
+	err := d.db.QueryRowContext(ctx, `SELECT user_id, secret, enabled, last_step, create_time, enable_time
+		FROM user_two_factor WHERE user_id = ?`, userId).
+		Scan(&tf.UserId, &tf.Secret, &tf.Enabled, &tf.LastStep, &tf.CreateTime, &tf.EnableTime)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tf, nil
+}
+
+func (d *TwoFactorSqlDao) Save(ctx context.Context, tf *data.TwoFactor) error {
+	_, err := d.db.ExecContext(ctx, `INSERT INTO user_two_factor (user_id, secret, enabled, last_step, create_time, enable_time)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, enabled = excluded.enabled,
+			last_step = excluded.last_step, create_time = excluded.create_time, enable_time = excluded.enable_time`,
+		tf.UserId, tf.Secret, tf.Enabled, tf.LastStep, tf.CreateTime, tf.EnableTime)
+	return err
+}
+
+func (d *TwoFactorSqlDao) Delete(ctx context.Context, userId int64) error {
+	return d.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userId); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM user_two_factor WHERE user_id = ?`, userId)
+		return err
+	})
+}
+
+func (d *TwoFactorSqlDao) AdvanceStep(ctx context.Context, userId int64, step int64) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `UPDATE user_two_factor SET last_step = ? WHERE user_id = ? AND last_step < ?`,
+		step, userId, step)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (d *TwoFactorSqlDao) ReplaceRecoveryCodes(ctx context.Context, userId int64, hashes []string) error {
+	return d.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userId); err != nil {
+			return err
+		}
+		for _, h := range hashes {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES (?, ?)`, userId, h); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *TwoFactorSqlDao) UseRecoveryCode(ctx context.Context, userId int64, hash string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `UPDATE user_recovery_codes SET used_time = ?
+		WHERE user_id = ? AND code_hash = ? AND used_time = 0`, time.Now().UnixMilli(), userId, hash)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (d *TwoFactorSqlDao) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
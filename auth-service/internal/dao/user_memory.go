@@ -0,0 +1,166 @@
+package dao
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserMemoryDao 进程内存中的账号存储，语义与 UserSqlDao 一致，用于测试和本地调试
+type UserMemoryDao struct {
+	mu     sync.RWMutex
+	nextId int64
+	users  map[int64]*data.User
+}
+
+func NewUserMemoryDao() *UserMemoryDao {
+	return &UserMemoryDao{users: make(map[int64]*data.User)}
+}
+
+func (d *UserMemoryDao) FindById(ctx context.Context, id int64) (*data.User, error) {
+	return d.findOne(func(u *data.User) bool { return u.Id == id }), nil
+}
+
+func (d *UserMemoryDao) FindByMobile(ctx context.Context, mobile string) (*data.User, error) {
+	return d.findOne(func(u *data.User) bool { return mobile != "" && u.Mobile == mobile }), nil
+}
+
+func (d *UserMemoryDao) FindByUsername(ctx context.Context, username string) (*data.User, error) {
+	return d.findOne(func(u *data.User) bool { return username != "" && strings.EqualFold(u.Username, username) }), nil
+}
+
+func (d *UserMemoryDao) FindByEmail(ctx context.Context, email string) (*data.User, error) {
+	return d.findOne(func(u *data.User) bool { return email != "" && strings.EqualFold(u.Email, email) }), nil
+}
+
+func (d *UserMemoryDao) ListByOwner(ctx context.Context, ownerId int64) ([]*data.User, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var list []*data.User
+	for _, u := range d.users {
+		if u.DeleteTime == 0 && ownerId != 0 && u.OwnerId == ownerId {
+			c := *u
+			list = append(list, &c)
+		}
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].CreateTime < list[b].CreateTime })
+	return list, nil
+}
+
+func (d *UserMemoryDao) Create(ctx context.Context, user *data.User) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.checkUnique(user); err != nil {
+		return err
+	}
+	if user.UpdateTime == 0 {
+		user.UpdateTime = user.CreateTime
+	}
+	d.nextId++
+	user.Id = d.nextId
+	user.Email = strings.ToLower(user.Email)
+	u := *user
+	d.users[u.Id] = &u
+	return nil
+}
+
+func (d *UserMemoryDao) Save(ctx context.Context, user *data.User) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.users[user.Id]; !ok {
+		return repo.ErrUserNotFound
+	}
+	if err := d.checkUnique(user); err != nil {
+		return err
+	}
+	user.UpdateTime = time.Now().UnixMilli()
+	user.Email = strings.ToLower(user.Email)
+	u := *user
+	d.users[u.Id] = &u
+	return nil
+}
+
+func (d *UserMemoryDao) UpdateProfile(ctx context.Context, id int64, profile *data.Profile) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	u, ok := d.users[id]
+	if !ok || u.DeleteTime != 0 {
+		return repo.ErrUserNotFound
+	}
+	u.Name, u.Avatar, u.UpdateTime = profile.Name, profile.Avatar, time.Now().UnixMilli()
+	return nil
+}
+
+func (d *UserMemoryDao) SoftDelete(ctx context.Context, id int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	u, ok := d.users[id]
+	if !ok || u.DeleteTime != 0 {
+		return repo.ErrUserNotFound
+	}
+	u.DeleteTime = time.Now().UnixMilli()
+	u.UpdateTime = u.DeleteTime
+	return nil
+}
+
+func (d *UserMemoryDao) ListPurgeDue(ctx context.Context, before int64, limit int) ([]int64, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var due []*data.User
+	for _, u := range d.users {
+		if u.PurgeTime > 0 && u.PurgeTime <= before {
+			due = append(due, u)
+		}
+	}
+	sort.Slice(due, func(a, b int) bool { return due[a].PurgeTime < due[b].PurgeTime })
+	ids := make([]int64, 0, limit)
+	for _, u := range due {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, u.Id)
+	}
+	return ids, nil
+}
+
+func (d *UserMemoryDao) Purge(ctx context.Context, id int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.users, id)
+	return nil
+}
+
+// findOne 返回第一个满足条件且未注销的账号副本
+func (d *UserMemoryDao) findOne(match func(u *data.User) bool) *data.User {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, u := range d.users {
+		if u.DeleteTime == 0 && match(u) {
+			c := *u
+			return &c
+		}
+	}
+	return nil
+}
+
+// checkUnique 检查手机号、用户名、邮箱是否被其他账号（含已注销账号）占用，调用方需持有写锁
+func (d *UserMemoryDao) checkUnique(user *data.User) error {
+	for _, u := range d.users {
+		if u.Id == user.Id {
+			continue
+		}
+		switch {
+		case user.Mobile != "" && u.Mobile == user.Mobile:
+			return repo.ErrMobileExists
+		case user.Username != "" && strings.EqualFold(u.Username, user.Username):
+			return repo.ErrUsernameExists
+		case user.Email != "" && strings.EqualFold(u.Email, user.Email):
+			return repo.ErrEmailExists
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/mattn/go-sqlite3"
+	"strings"
+	"time"
+)
+
+const userColumns = `id, name, avatar, username, email, email_verified, mobile, password_hash,
+	create_time, update_time, last_login_time, delete_time, owner_id, purge_time`
+
+// UserSqlDao 基于 database/sql 的账号存储，表结构见 internal/database/migrations
+type UserSqlDao struct {
+	db *sql.DB
+}
+
+func NewUserSqlDao(db *sql.DB) *UserSqlDao {
+	return &UserSqlDao{db: db}
+}
+
+func (d *UserSqlDao) FindById(ctx context.Context, id int64) (*data.User, error) {
+	return d.findOne(ctx, "id = ?", id)
+}
+
+func (d *UserSqlDao) FindByMobile(ctx context.Context, mobile string) (*data.User, error) {
+	return d.findOne(ctx, "mobile = ?", mobile)
+}
+
+func (d *UserSqlDao) FindByUsername(ctx context.Context, username string) (*data.User, error) {
+	return d.findOne(ctx, "username = ?", username)
+}
+
+func (d *UserSqlDao) FindByEmail(ctx context.Context, email string) (*data.User, error) {
+	return d.findOne(ctx, "email = ?", email)
+}
+
+func (d *UserSqlDao) ListByOwner(ctx context.Context, ownerId int64) ([]*data.User, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE owner_id = ? AND delete_time = 0 ORDER BY create_time", ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*data.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, user)
+	}
+	return list, rows.Err()
+}
+
+func (d *UserSqlDao) Create(ctx context.Context, user *data.User) error {
+	if user.UpdateTime == 0 {
+		user.UpdateTime = user.CreateTime
+	}
+	res, err := d.db.ExecContext(ctx, `INSERT INTO users (name, avatar, username, email, email_verified, mobile,
+		password_hash, create_time, update_time, last_login_time, delete_time, owner_id, purge_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.Name, user.Avatar, nullString(user.Username), nullString(strings.ToLower(user.Email)), user.EmailVerified, nullString(user.Mobile),
+		user.PasswordHash, user.CreateTime, user.UpdateTime, user.LastLoginTime, user.DeleteTime, user.OwnerId, user.PurgeTime)
+	if err != nil {
+		return uniqueError(err)
+	}
+	user.Id, err = res.LastInsertId()
+	return err
+}
+
+func (d *UserSqlDao) Save(ctx context.Context, user *data.User) error {
+	user.UpdateTime = time.Now().UnixMilli()
+	res, err := d.db.ExecContext(ctx, `UPDATE users SET name = ?, avatar = ?, username = ?, email = ?, email_verified = ?,
+		mobile = ?, password_hash = ?, update_time = ?, last_login_time = ?, delete_time = ?, purge_time = ? WHERE id = ?`,
+		user.Name, user.Avatar, nullString(user.Username), nullString(strings.ToLower(user.Email)), user.EmailVerified, nullString(user.Mobile),
+		user.PasswordHash, user.UpdateTime, user.LastLoginTime, user.DeleteTime, user.PurgeTime, user.Id)
+	if err != nil {
+		return uniqueError(err)
+	}
+	return affectedOne(res)
+}
+
+func (d *UserSqlDao) UpdateProfile(ctx context.Context, id int64, profile *data.Profile) error {
+	res, err := d.db.ExecContext(ctx, `UPDATE users SET name = ?, avatar = ?, update_time = ? WHERE id = ? AND delete_time = 0`,
+		profile.Name, profile.Avatar, time.Now().UnixMilli(), id)
+	if err != nil {
+		return err
+	}
+	return affectedOne(res)
+}
+
+func (d *UserSqlDao) SoftDelete(ctx context.Context, id int64) error {
+	now := time.Now().UnixMilli()
+	res, err := d.db.ExecContext(ctx, `UPDATE users SET delete_time = ?, update_time = ? WHERE id = ? AND delete_time = 0`,
+		now, now, id)
+	if err != nil {
+		return err
+	}
+	return affectedOne(res)
+}
+
+func (d *UserSqlDao) ListPurgeDue(ctx context.Context, before int64, limit int) ([]int64, error) {
+	rows, err := d.db.QueryContext(ctx, `SELECT id FROM users WHERE purge_time > 0 AND purge_time <= ?
+		ORDER BY purge_time LIMIT ?`, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (d *UserSqlDao) Purge(ctx context.Context, id int64) error {
+	_, err := d.db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", id)
+	return err
+}
+
+// findOne 按条件查询一个未注销的账号，不存在时返回 nil, nil
+func (d *UserSqlDao) findOne(ctx context.Context, where string, arg any) (*data.User, error) {
+	row := d.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE "+where+" AND delete_time = 0", arg)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// scanUser 按 userColumns 的顺序读取一行账号
+func scanUser(row interface{ Scan(dest ...any) error }) (*data.User, error) {
+	user := &data.User{}
+	var username, email, mobile sql.NullString
+	err := row.Scan(&user.Id, &user.Name, &user.Avatar, &username, &email, &user.EmailVerified, &mobile, &user.PasswordHash,
+		&user.CreateTime, &user.UpdateTime, &user.LastLoginTime, &user.DeleteTime, &user.OwnerId, &user.PurgeTime)
+	if err != nil {
+		return nil, err
+	}
+	user.Username, user.Email, user.Mobile = username.String, email.String, mobile.String
+	return user, nil
+}
+
+// nullString 空字符串写入 NULL，使唯一索引不约束未设置的字段
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// uniqueError 将唯一索引冲突转换为对应的 repo.Err*Exists
+func uniqueError(err error) error {
+	var se sqlite3.Error
+	if !errors.As(err, &se) || se.ExtendedCode != sqlite3.ErrConstraintUnique {
+		return err
+	}
+	msg := se.Error()
+	switch {
+	case strings.Contains(msg, "users.mobile"):
+		return repo.ErrMobileExists
+	case strings.Contains(msg, "users.username"):
+		return repo.ErrUsernameExists
+	case strings.Contains(msg, "users.email"):
+		return repo.ErrEmailExists
+	}
+	return err
+}
+
+func affectedOne(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repo.ErrUserNotFound
+	}
+	return nil
+}
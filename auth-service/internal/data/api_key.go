@@ -0,0 +1,17 @@
+package data
+
+// ApiKey 供自动化脚本使用的长期凭证，属于普通账号或服务账号
+// 完整的 Key 为 "<prefix>.<secret>"，只保存 secret 的 SHA-256，prefix 可公开展示用于识别
+type ApiKey struct {
+	Prefix       string   `json:"prefix"`
+	OwnerId      int64    `json:"ownerId"` // 使用该 Key 时代表的账号
+	Name         string   `json:"name"`
+	SecretHash   string   `json:"-"`
+	Scopes       []string `json:"scopes"`       // 授权范围，与开放平台的授权范围一致
+	ExpireTime   int64    `json:"expireTime"`   // 过期时间（毫秒时间戳），0 表示不过期
+	LastUsedTime int64    `json:"lastUsedTime"` // 最近使用时间（毫秒时间戳），按配置的间隔更新
+	LastUsedIp   string   `json:"lastUsedIp"`
+	CreateTime   int64    `json:"createTime"`
+	RotateTime   int64    `json:"rotateTime"` // 最近一次轮换密钥的时间
+	RevokeTime   int64    `json:"revokeTime"` // 吊销时间，0 表示未吊销；吊销的 Key 保留记录用于审计
+}
@@ -0,0 +1,10 @@
+package data
+
+// Identity 账号关联的第三方身份（OIDC 身份提供方的 sub），同一提供方每个账号最多关联一个身份
+type Identity struct {
+	UserId     int64  `json:"userId"`
+	Provider   string `json:"provider"` // 配置中的身份提供方名称
+	Subject    string `json:"subject"`  // ID 令牌中的 sub，在同一提供方内唯一且不变
+	Email      string `json:"email"`    // 关联时 ID 令牌中的邮箱，仅用于展示
+	CreateTime int64  `json:"createTime"`
+}
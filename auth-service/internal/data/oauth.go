@@ -0,0 +1,25 @@
+package data
+
+// OAuthClient 第三方应用（机器人、集成）注册的 OAuth2 客户端
+type OAuthClient struct {
+	ClientId     string   `json:"clientId"`
+	SecretHash   string   `json:"-"` // 客户端密钥的 SHA-256，密钥只在注册和重置时返回一次
+	Name         string   `json:"name"`
+	OwnerId      int64    `json:"ownerId"`      // 注册该客户端的账号
+	RedirectUris []string `json:"redirectUris"` // 授权码模式允许的回调地址，需完全匹配
+	Scopes       []string `json:"scopes"`       // 允许申请的授权范围
+	GrantTypes   []string `json:"grantTypes"`   // 允许使用的授权方式
+	GrantId      string   `json:"-"`            // 客户端凭证模式令牌的授权记录，重置密钥或删除客户端时吊销
+	CreateTime   int64    `json:"createTime"`
+	UpdateTime   int64    `json:"updateTime"`
+}
+
+// OAuthConsent 账号对第三方应用的授权
+type OAuthConsent struct {
+	UserId     int64    `json:"userId"`
+	ClientId   string   `json:"clientId"`
+	Scopes     []string `json:"scopes"`  // 已同意的授权范围
+	GrantId    string   `json:"grantId"` // 写入令牌的授权记录，撤销授权时吊销
+	CreateTime int64    `json:"createTime"`
+	UpdateTime int64    `json:"updateTime"`
+}
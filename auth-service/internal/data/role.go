@@ -0,0 +1,10 @@
+package data
+
+// UserRole 授予账号的角色，Scope 为空表示全局生效，否则为 "tenant:<id>" 或 "group:<id>"，见 lib/rbac
+type UserRole struct {
+	UserId     int64  `json:"userId"`
+	Role       string `json:"role"`
+	Scope      string `json:"scope"`
+	GrantedBy  int64  `json:"grantedBy"` // 授予角色的管理员账号
+	CreateTime int64  `json:"createTime"`
+}
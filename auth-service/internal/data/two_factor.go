@@ -0,0 +1,11 @@
+package data
+
+// TwoFactor 账号的 TOTP 两步验证设置
+type TwoFactor struct {
+	UserId     int64  `json:"userId"`
+	Secret     string `json:"secret"`     // 加密后的 TOTP 密钥
+	Enabled    bool   `json:"enabled"`    // 绑定后需校验一次动态码才启用
+	LastStep   int64  `json:"lastStep"`   // 最近一次校验通过的时间步，防止动态码重放
+	CreateTime int64  `json:"createTime"` // 毫秒时间戳
+	EnableTime int64  `json:"enableTime"`
+}
@@ -0,0 +1,35 @@
+package data
+
+// User 账号信息
+type User struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	Avatar        string `json:"avatar"`
+	Username      string `json:"username"` // 登录用户名，未设置时为空
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"` // 邮箱是否已通过验证码验证，只有已验证的邮箱可用于找回密码
+	Mobile        string `json:"mobile"`
+	PasswordHash  string `json:"passwordHash"`  // 密码哈希（PHC 格式），未设置密码时为空
+	CreateTime    int64  `json:"createTime"`    // 创建时间（毫秒时间戳）
+	UpdateTime    int64  `json:"updateTime"`    // 更新时间（毫秒时间戳）
+	LastLoginTime int64  `json:"lastLoginTime"` // 最近登录时间（毫秒时间戳）
+	DeleteTime    int64  `json:"deleteTime"`    // 注销时间（毫秒时间戳），0 表示未注销
+	OwnerId       int64  `json:"ownerId"`       // 服务账号的创建者，普通账号为 0
+	PurgeTime     int64  `json:"purgeTime"`     // 申请注销后计划清除数据的时间（毫秒时间戳），0 表示未申请注销
+}
+
+// IsServiceAccount 是否为服务账号：供自动化脚本通过 API Key 调用接口，没有手机号和密码，不能登录
+func (u *User) IsServiceAccount() bool {
+	return u.OwnerId != 0
+}
+
+// DeletionPending 是否已申请注销、处于冷静期内，冷静期内仍可登录并撤销注销
+func (u *User) DeletionPending() bool {
+	return u.PurgeTime != 0 && u.DeleteTime == 0
+}
+
+// Profile 账号资料中允许用户自行修改的部分
+type Profile struct {
+	Name   string
+	Avatar string
+}
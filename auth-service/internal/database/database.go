@@ -0,0 +1,16 @@
+package database
+
+import (
+	"database/sql"
+	libDatabase "github.com/MortalSC/IM-System/lib/database"
+)
+
+const DriverMemory = "memory" // 账号保存在进程内存中，仅用于开发调试，重启后丢失
+
+// Open 按配置打开数据库连接池，memory 模式下返回 nil
+func Open(cfg *libDatabase.Config) (*sql.DB, error) {
+	if cfg.Driver == DriverMemory {
+		return nil, nil
+	}
+	return libDatabase.Open(cfg)
+}
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	libDatabase "github.com/MortalSC/IM-System/lib/database"
+)
+
+// 迁移脚本命名为 <版本号>_<说明>.sql，版本号递增且已发布的脚本不可修改
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate 执行本服务尚未执行的迁移脚本，返回本次执行的版本号
+func Migrate(ctx context.Context, db *sql.DB) ([]int, error) {
+	return libDatabase.Migrate(ctx, db, migrationFiles, "migrations")
+}
@@ -0,0 +1,18 @@
+-- 账号表：手机号、用户名、邮箱未设置时为 NULL，唯一索引允许多个 NULL
+CREATE TABLE users (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    name            TEXT    NOT NULL DEFAULT '',
+    avatar          TEXT    NOT NULL DEFAULT '',
+    username        TEXT    COLLATE NOCASE,
+    email           TEXT    COLLATE NOCASE,
+    mobile          TEXT,
+    password_hash   TEXT    NOT NULL DEFAULT '',
+    create_time     INTEGER NOT NULL,
+    update_time     INTEGER NOT NULL,
+    last_login_time INTEGER NOT NULL DEFAULT 0,
+    delete_time     INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX uk_users_mobile ON users (mobile);
+CREATE UNIQUE INDEX uk_users_username ON users (username);
+CREATE UNIQUE INDEX uk_users_email ON users (email);
@@ -0,0 +1,17 @@
+-- TOTP 两步验证设置，每个账号最多一条
+CREATE TABLE user_two_factor (
+    user_id     INTEGER PRIMARY KEY,
+    secret      TEXT    NOT NULL,
+    enabled     INTEGER NOT NULL DEFAULT 0,
+    last_step   INTEGER NOT NULL DEFAULT 0,
+    create_time INTEGER NOT NULL,
+    enable_time INTEGER NOT NULL DEFAULT 0
+);
+
+-- 两步验证恢复码，只保存哈希，每个恢复码只能使用一次
+CREATE TABLE user_recovery_codes (
+    user_id   INTEGER NOT NULL,
+    code_hash TEXT    NOT NULL,
+    used_time INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, code_hash)
+);
@@ -0,0 +1,2 @@
+-- 手机号统一保存为 E.164 格式，此前只接受中国大陆 11 位手机号，补全 +86 国际区号
+UPDATE users SET mobile = '+86' || mobile WHERE mobile IS NOT NULL AND mobile NOT LIKE '+%';
@@ -0,0 +1,11 @@
+-- 第三方身份关联：(provider, subject) 唯一对应一个账号，每个账号在同一提供方下最多关联一个身份
+CREATE TABLE user_identities (
+    provider    TEXT    NOT NULL,
+    subject     TEXT    NOT NULL,
+    user_id     INTEGER NOT NULL,
+    email       TEXT    NOT NULL DEFAULT '',
+    create_time INTEGER NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE UNIQUE INDEX uk_user_identities_user ON user_identities (user_id, provider);
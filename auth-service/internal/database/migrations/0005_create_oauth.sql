@@ -0,0 +1,28 @@
+-- OAuth2 客户端：redirect_uris、scopes、grant_types 以空格分隔
+CREATE TABLE oauth_clients (
+    client_id     TEXT    PRIMARY KEY,
+    secret_hash   TEXT    NOT NULL,
+    name          TEXT    NOT NULL,
+    owner_id      INTEGER NOT NULL,
+    redirect_uris TEXT    NOT NULL DEFAULT '',
+    scopes        TEXT    NOT NULL DEFAULT '',
+    grant_types   TEXT    NOT NULL DEFAULT '',
+    grant_id      TEXT    NOT NULL,
+    create_time   INTEGER NOT NULL,
+    update_time   INTEGER NOT NULL
+);
+
+CREATE INDEX idx_oauth_clients_owner ON oauth_clients (owner_id);
+
+-- 账号对客户端的授权，每个账号对同一客户端只保留一条
+CREATE TABLE oauth_consents (
+    user_id     INTEGER NOT NULL,
+    client_id   TEXT    NOT NULL,
+    scopes      TEXT    NOT NULL DEFAULT '',
+    grant_id    TEXT    NOT NULL,
+    create_time INTEGER NOT NULL,
+    update_time INTEGER NOT NULL,
+    PRIMARY KEY (user_id, client_id)
+);
+
+CREATE INDEX idx_oauth_consents_client ON oauth_consents (client_id);
@@ -0,0 +1,2 @@
+-- 邮箱验证标记：只有通过邮箱验证码验证过的邮箱才能用于找回密码，已有邮箱均视为未验证
+ALTER TABLE users ADD COLUMN email_verified INTEGER NOT NULL DEFAULT 0;
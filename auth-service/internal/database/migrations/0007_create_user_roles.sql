@@ -0,0 +1,9 @@
+-- 账号角色：scope 为空表示全局生效，否则为 tenant:<id> 或 group:<id>
+CREATE TABLE user_roles (
+    user_id     INTEGER NOT NULL,
+    role        TEXT    NOT NULL,
+    scope       TEXT    NOT NULL DEFAULT '',
+    granted_by  INTEGER NOT NULL DEFAULT 0,
+    create_time INTEGER NOT NULL,
+    PRIMARY KEY (user_id, role, scope)
+);
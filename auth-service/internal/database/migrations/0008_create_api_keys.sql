@@ -0,0 +1,21 @@
+-- 服务账号：由普通账号创建，owner_id 为创建者，普通账号为 0
+ALTER TABLE users ADD COLUMN owner_id INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_users_owner ON users (owner_id);
+
+-- API Key：明文只在创建和轮换时返回一次，只保存密钥部分的哈希；prefix 用于识别和查找
+CREATE TABLE api_keys (
+    prefix         TEXT    NOT NULL PRIMARY KEY,
+    owner_id       INTEGER NOT NULL,
+    name           TEXT    NOT NULL,
+    secret_hash    TEXT    NOT NULL,
+    scopes         TEXT    NOT NULL DEFAULT '',
+    expire_time    INTEGER NOT NULL DEFAULT 0,
+    last_used_time INTEGER NOT NULL DEFAULT 0,
+    last_used_ip   TEXT    NOT NULL DEFAULT '',
+    create_time    INTEGER NOT NULL,
+    rotate_time    INTEGER NOT NULL DEFAULT 0,
+    revoke_time    INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_api_keys_owner ON api_keys (owner_id);
@@ -0,0 +1,4 @@
+-- 账号注销：申请后进入冷静期，purge_time 为计划清除数据的时间，0 表示未申请注销
+ALTER TABLE users ADD COLUMN purge_time INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_users_purge ON users (purge_time);
@@ -1,5 +1,81 @@
 package errors
 
 const (
-	ErrNoLegalMobile = 2001 // 手机号不合法
+	ErrCacheFail = 1001 // 缓存服务异常
+
+	ErrNoLegalMobile           = 2001 // 手机号不合法
+	ErrCaptchaNotExist         = 2003 // 验证码不存在或已过期
+	ErrCaptchaError            = 2004 // 验证码错误
+	ErrCaptchaTooFrequent      = 2005 // 验证码获取过于频繁
+	ErrCaptchaAttemptsExceeded = 2006 // 验证码错误次数过多
+	ErrMobileCountryCode       = 2007 // 不支持的国家/地区代码
+	ErrMobileLength            = 2008 // 手机号长度不正确
+	ErrMobileRegionNotAllowed  = 2009 // 手机号所属地区不在允许范围内
+	ErrHumanCheckRequired      = 2010 // 发送短信前需完成人机验证
+	ErrHumanCheckNotExist      = 2011 // 人机验证不存在或已过期
+	ErrHumanCheckFailed        = 2012 // 人机验证未通过
+	ErrHumanCheckDenied        = 2013 // 风险过高，拒绝人机验证
+	ErrCaptchaChannel          = 2014 // 不支持的验证码发送渠道
+
+	ErrTokenInvalid = 2101 // 令牌无效
+	ErrTokenExpired = 2102 // 令牌已过期
+	ErrTokenRevoked = 2103 // 令牌已被吊销
+
+	ErrSessionNotExist = 2201 // 登录会话不存在
+
+	ErrNoLegalUsername    = 2301 // 用户名不合法
+	ErrNoLegalEmail       = 2302 // 邮箱不合法
+	ErrMobileExists       = 2303 // 手机号已注册
+	ErrUsernameExists     = 2304 // 用户名已被使用
+	ErrEmailExists        = 2305 // 邮箱已被使用
+	ErrPasswordWeak       = 2306 // 密码强度不足
+	ErrAccountOrPassword  = 2307 // 账号或密码错误
+	ErrAccountLocked      = 2308 // 密码错误次数过多，账号已锁定
+	ErrAccountNotExist    = 2309 // 账号不存在
+	ErrEmailNotVerified   = 2310 // 邮箱未验证
+	ErrMobileUnchanged    = 2311 // 新手机号与当前手机号相同
+	ErrDeletionPending    = 2312 // 账号已申请注销
+	ErrDeletionNotPending = 2313 // 账号未申请注销
+	ErrNoVerifiedContact  = 2314 // 账号未绑定手机号或已验证的邮箱，无法通过验证码确认身份
+
+	ErrTotpAlreadyEnabled        = 2401 // 已启用两步验证
+	ErrTotpNotEnrolled           = 2402 // 未绑定验证器
+	ErrTotpNotEnabled            = 2403 // 未启用两步验证
+	ErrTotpCodeError             = 2404 // 动态码或恢复码错误
+	ErrChallengeInvalid          = 2405 // 两步验证挑战令牌不存在或已过期
+	ErrChallengeAttemptsExceeded = 2406 // 两步验证错误次数过多
+
+	ErrOidcProviderNotExist = 2501 // 身份提供方不存在
+	ErrOidcStateInvalid     = 2502 // 第三方登录请求不存在或已过期
+	ErrOidcProviderFail     = 2503 // 身份提供方认证失败
+	ErrOidcAccountNotLinked = 2504 // 第三方身份未关联账号
+	ErrOidcIdentityExists   = 2505 // 第三方身份已关联其他账号
+	ErrOidcProviderLinked   = 2506 // 账号已关联该身份提供方
+	ErrOidcIdentityNotExist = 2507 // 账号未关联该身份提供方
+	ErrOidcLastLoginMethod  = 2508 // 不能解除唯一的登录方式
+
+	ErrOAuthInvalidClient      = 2601 // 客户端认证失败（invalid_client）
+	ErrOAuthInvalidGrant       = 2602 // 授权码、刷新令牌无效或已过期（invalid_grant）
+	ErrOAuthInvalidScope       = 2603 // 授权范围无效（invalid_scope）
+	ErrOAuthUnsupportedGrant   = 2604 // 不支持的授权方式（unsupported_grant_type）
+	ErrOAuthInvalidRequest     = 2605 // 请求参数缺失或不合法（invalid_request）
+	ErrOAuthUnauthorizedClient = 2606 // 客户端无权使用该授权方式（unauthorized_client）
+	ErrOAuthAccessDenied       = 2607 // 用户拒绝授权（access_denied）
+	ErrOAuthInvalidRedirect    = 2608 // 回调地址未登记
+	ErrOAuthClientNotExist     = 2609 // 客户端不存在
+
+	ErrPermissionDenied = 2701 // 权限不足
+	ErrRoleNotExist     = 2702 // 角色不存在
+	ErrRoleAssigned     = 2703 // 账号在该范围内已拥有该角色
+	ErrRoleNotAssigned  = 2704 // 账号在该范围内没有该角色
+	ErrRoleScopeInvalid = 2705 // 角色生效范围不合法
+
+	ErrApiKeyInvalid          = 2801 // API Key 无效
+	ErrApiKeyExpired          = 2802 // API Key 已过期
+	ErrApiKeyRevoked          = 2803 // API Key 已吊销
+	ErrApiKeyNotExist         = 2804 // API Key 不存在
+	ErrApiKeyInvalidRequest   = 2805 // 名称、授权范围或有效期不合法
+	ErrApiKeyLimit            = 2806 // API Key 数量已达上限
+	ErrServiceAccountNotExist = 2807 // 服务账号不存在
+	ErrServiceAccountLimit    = 2808 // 服务账号数量已达上限
 )
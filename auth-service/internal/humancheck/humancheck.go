@@ -0,0 +1,258 @@
+package humancheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"image"
+	"image/png"
+	mrand "math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	checkKeyPrefix = "HUMAN_CHECK_" // HUMAN_CHECK_<id> -> 待完成的人机验证（JSON）
+	passKeyPrefix  = "HUMAN_PASS_"  // HUMAN_PASS_<ticket> -> 通过人机验证的客户端（JSON），发送短信前消费
+
+	KindSlider = "slider" // 滑块拼图
+	KindImage  = "image"  // 扭曲字符图片
+)
+
+// Config 人机验证配置
+type Config struct {
+	Enabled         bool          // 关闭时发送短信验证码不要求人机验证，仅用于开发环境
+	Expire          time.Duration // 验证题有效期
+	PassExpire      time.Duration // 通过凭证有效期
+	SliderTolerance int           // 滑块允许的横向误差（像素）
+	MinSolveTime    time.Duration // 出题到提交的最短时间，过快视为脚本
+	RiskMedium      int           // 风险分达到该值时使用图片验证码
+	RiskHigh        int           // 风险分达到该值时使用高强度图片验证码
+	RiskBlock       int           // 风险分达到该值时拒绝请求
+	Risk            *RiskConfig
+}
+
+// Challenge 下发给客户端的验证题，图片为 PNG 的 data URI
+// 滑块题需将 Piece 水平拖动到背景缺口处，提交拼图块左边缘的横坐标；图片题提交图中字符
+type Challenge struct {
+	Id       string
+	Kind     string
+	Image    string
+	Piece    string // 滑块拼图块，仅滑块题
+	PieceY   int    // 拼图块在背景中的纵坐标，仅滑块题
+	Width    int
+	Height   int
+	ExpireAt int64 // 毫秒时间戳
+}
+
+// record 缓存中的验证题
+type record struct {
+	Kind      string    `json:"kind"`
+	Answer    string    `json:"answer"`
+	Client    RiskInput `json:"client"`
+	CreatedAt int64     `json:"createdAt"` // 毫秒时间戳
+}
+
+// Manager 人机验证：按风险分出题、校验答案并签发一次性通过凭证，状态保存在 lib/cache 中
+type Manager struct {
+	cache  LibCache.Cache
+	cfg    *Config
+	scorer RiskScorer
+}
+
+func NewManager(cache LibCache.Cache, cfg *Config, scorer RiskScorer) *Manager {
+	return &Manager{cache: cache, cfg: cfg, scorer: scorer}
+}
+
+// Create 评估客户端风险并出题：低风险为滑块，中高风险为强度递增的图片验证码，风险过高直接拒绝
+func (m *Manager) Create(ctx context.Context, client *RiskInput) (*Challenge, error) {
+	score, err := m.scorer.Score(ctx, client)
+	if err != nil {
+		return nil, internalError("评估人机验证风险", err)
+	}
+	if score >= m.cfg.RiskBlock {
+		libLog.IMLog.Warn(fmt.Sprintf("人机验证风险过高已拒绝，ip: %s，分数: %d", client.Ip, score))
+		return nil, libErrors.GrpcError(errs.ErrHumanCheckDenied, "请求存在风险，请稍后再试")
+	}
+
+	rng, err := newRand()
+	if err != nil {
+		return nil, internalError("初始化随机数", err)
+	}
+	c := &Challenge{}
+	rec := &record{Client: *client, CreatedAt: time.Now().UnixMilli()}
+	switch {
+	case score < m.cfg.RiskMedium:
+		p := renderSlider(rng)
+		c.Kind, c.PieceY, c.Width, c.Height = KindSlider, p.Y-pieceKnob, sliderWidth, sliderHeight
+		if c.Image, err = dataUri(p.Background); err == nil {
+			c.Piece, err = dataUri(p.Piece)
+		}
+		rec.Answer = strconv.Itoa(p.X)
+	default:
+		style := imageNormal
+		if score >= m.cfg.RiskHigh {
+			style = imageStrict
+		}
+		var img image.Image
+		rec.Answer, img = renderText(rng, style)
+		c.Kind, c.Width, c.Height = KindImage, imageWidth, imageHeight
+		c.Image, err = dataUri(img)
+	}
+	if err != nil {
+		return nil, internalError("编码人机验证图片", err)
+	}
+	rec.Kind = c.Kind
+
+	if c.Id, err = utils.RandomToken(16); err != nil {
+		return nil, internalError("生成人机验证 ID", err)
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, internalError("序列化人机验证", err)
+	}
+	if err = m.cache.Put(ctx, checkKeyPrefix+c.Id, string(b), m.cfg.Expire); err != nil {
+		return nil, internalError("保存人机验证", err)
+	}
+	c.ExpireAt = time.Now().Add(m.cfg.Expire).UnixMilli()
+	return c, nil
+}
+
+// Verify 校验答案，每道题只能提交一次；通过后返回一次性凭证，发送短信验证码时携带
+// 提交答案的客户端（IP、设备指纹）须与出题时一致
+func (m *Manager) Verify(ctx context.Context, id, answer string, client *RiskInput) (string, int64, error) {
+	rec, err := m.consumeCheck(ctx, id)
+	if err != nil {
+		return "", 0, err
+	}
+	if rec == nil || rec.Client.Ip != client.Ip || rec.Client.Fingerprint != client.Fingerprint {
+		return "", 0, libErrors.GrpcError(errs.ErrHumanCheckNotExist, "人机验证不存在或已过期，请刷新")
+	}
+
+	passed := time.Since(time.UnixMilli(rec.CreatedAt)) >= m.cfg.MinSolveTime && m.check(rec, answer)
+	if err = m.scorer.Report(ctx, client, passed); err != nil {
+		libLog.IMLog.Warn(fmt.Sprintf("上报人机验证结果出错，原因: %v", err))
+	}
+	if !passed {
+		return "", 0, libErrors.GrpcError(errs.ErrHumanCheckFailed, "人机验证未通过，请重试")
+	}
+
+	ticket, err := utils.RandomToken(32)
+	if err != nil {
+		return "", 0, internalError("生成人机验证凭证", err)
+	}
+	b, err := json.Marshal(client)
+	if err != nil {
+		return "", 0, internalError("序列化人机验证凭证", err)
+	}
+	if err = m.cache.Put(ctx, passKeyPrefix+ticket, string(b), m.cfg.PassExpire); err != nil {
+		return "", 0, internalError("保存人机验证凭证", err)
+	}
+	return ticket, time.Now().Add(m.cfg.PassExpire).UnixMilli(), nil
+}
+
+// Consume 消费通过凭证，每个凭证只能用于发送一次短信；未开启人机验证时直接通过
+func (m *Manager) Consume(ctx context.Context, ticket string, client *RiskInput) error {
+	if !m.cfg.Enabled {
+		return nil
+	}
+	required := libErrors.GrpcError(errs.ErrHumanCheckRequired, "请先完成人机验证")
+	if ticket == "" {
+		return required
+	}
+	key := passKeyPrefix + ticket
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return internalError("读取人机验证凭证", err)
+	}
+	if val == "" {
+		return required
+	}
+	// 并发请求中只有成功删除的一方可以使用凭证
+	consumed, err := m.cache.Delete(ctx, key)
+	if err != nil {
+		return internalError("删除人机验证凭证", err)
+	}
+	if !consumed {
+		return required
+	}
+	passed := &RiskInput{}
+	if err = json.Unmarshal([]byte(val), passed); err != nil {
+		return internalError("解析人机验证凭证", err)
+	}
+	if passed.Ip != client.Ip || passed.Fingerprint != client.Fingerprint {
+		return required
+	}
+	return nil
+}
+
+// consumeCheck 取出并删除验证题，不存在时返回 nil
+func (m *Manager) consumeCheck(ctx context.Context, id string) (*record, error) {
+	if id == "" {
+		return nil, nil
+	}
+	key := checkKeyPrefix + id
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return nil, internalError("读取人机验证", err)
+	}
+	if val == "" {
+		return nil, nil
+	}
+	consumed, err := m.cache.Delete(ctx, key)
+	if err != nil {
+		return nil, internalError("删除人机验证", err)
+	}
+	if !consumed {
+		return nil, nil
+	}
+	rec := &record{}
+	if err = json.Unmarshal([]byte(val), rec); err != nil {
+		return nil, internalError("解析人机验证", err)
+	}
+	return rec, nil
+}
+
+func (m *Manager) check(rec *record, answer string) bool {
+	answer = strings.TrimSpace(answer)
+	if rec.Kind == KindImage {
+		return strings.EqualFold(answer, rec.Answer)
+	}
+	x, err := strconv.Atoi(answer)
+	if err != nil {
+		return false
+	}
+	want, _ := strconv.Atoi(rec.Answer)
+	return x >= want-m.cfg.SliderTolerance && x <= want+m.cfg.SliderTolerance
+}
+
+// newRand 以加密安全的随机种子创建随机数生成器，保证答案不可预测
+func newRand() (*mrand.Rand, error) {
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+	return mrand.New(mrand.NewChaCha8(seed)), nil
+}
+
+func dataUri(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,133 @@
+package humancheck
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+)
+
+const (
+	imageWidth  = 200
+	imageHeight = 70
+	glyphScale  = 3 // basicfont 7x13 放大倍数
+)
+
+// imageAlphabet 去掉了容易混淆的 0/O、1/I/L 等字符，校验时不区分大小写
+const imageAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// imageStyle 图片验证码强度
+type imageStyle struct {
+	length    int     // 字符数
+	maxRotate float64 // 单个字符最大旋转角度（弧度）
+	waveAmp   float64 // 整体正弦扭曲幅度（像素）
+	lines     int     // 干扰线数量
+	dots      int     // 噪点数量
+}
+
+var (
+	imageNormal = imageStyle{length: 4, maxRotate: 0.35, waveAmp: 3, lines: 3, dots: 300}
+	imageStrict = imageStyle{length: 6, maxRotate: 0.55, waveAmp: 6, lines: 6, dots: 700}
+)
+
+// renderText 生成字符扭曲、带干扰线和噪点的图片验证码，返回答案和图片
+func renderText(rng *rand.Rand, style imageStyle) (string, image.Image) {
+	answer := make([]byte, style.length)
+	for i := range answer {
+		answer[i] = imageAlphabet[rng.IntN(len(imageAlphabet))]
+	}
+
+	// 1. 字符逐个旋转、缩放后绘制到透明图层上
+	layer := image.NewRGBA(image.Rect(0, 0, imageWidth, imageHeight))
+	step := float64(imageWidth-20) / float64(style.length)
+	for i, ch := range answer {
+		cx := 10 + step*(float64(i)+0.5) + float64(rng.IntN(7)-3)
+		cy := float64(imageHeight)/2 + float64(rng.IntN(11)-5)
+		angle := (rng.Float64()*2 - 1) * style.maxRotate
+		drawGlyph(layer, rune(ch), cx, cy, angle, randomDark(rng))
+	}
+
+	// 2. 背景 + 正弦扭曲后的字符图层
+	img := image.NewRGBA(layer.Rect)
+	bg := randomLight(rng)
+	phase, freq := rng.Float64()*2*math.Pi, 0.03+rng.Float64()*0.04
+	for y := 0; y < imageHeight; y++ {
+		for x := 0; x < imageWidth; x++ {
+			sy := y + int(style.waveAmp*math.Sin(float64(x)*freq+phase))
+			sx := x + int(style.waveAmp/2*math.Sin(float64(y)*freq*2+phase))
+			c := bg
+			if image.Pt(sx, sy).In(layer.Rect) {
+				if fg := layer.RGBAAt(sx, sy); fg.A != 0 {
+					c = fg
+				}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	// 3. 干扰线与噪点
+	for i := 0; i < style.lines; i++ {
+		drawCurve(img, rng, randomDark(rng))
+	}
+	for i := 0; i < style.dots; i++ {
+		img.SetRGBA(rng.IntN(imageWidth), rng.IntN(imageHeight), randomColor(rng))
+	}
+	return string(answer), img
+}
+
+// drawGlyph 以 (cx, cy) 为中心绘制旋转 angle、放大 glyphScale 倍的字符
+func drawGlyph(dst *image.RGBA, ch rune, cx, cy, angle float64, c color.RGBA) {
+	face := basicfont.Face7x13
+	mask := image.NewAlpha(image.Rect(0, 0, face.Advance, face.Height))
+	d := &font.Drawer{Dst: mask, Src: image.Opaque, Face: face, Dot: fixed.P(0, face.Ascent)}
+	d.DrawString(string(ch))
+
+	w, h := float64(face.Advance*glyphScale), float64(face.Height*glyphScale)
+	r := int(math.Hypot(w, h)/2) + 1
+	sin, cos := math.Sin(angle), math.Cos(angle)
+	// 逆向映射：目标像素旋转回原坐标后在字模中采样
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			ox := (float64(dx)*cos + float64(dy)*sin + w/2) / glyphScale
+			oy := (-float64(dx)*sin + float64(dy)*cos + h/2) / glyphScale
+			if ox < 0 || oy < 0 || mask.AlphaAt(int(ox), int(oy)).A == 0 {
+				continue
+			}
+			x, y := int(cx)+dx, int(cy)+dy
+			if image.Pt(x, y).In(dst.Rect) {
+				dst.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// drawCurve 横穿图片的随机正弦干扰线
+func drawCurve(img *image.RGBA, rng *rand.Rand, c color.RGBA) {
+	amp := 5 + rng.Float64()*float64(imageHeight)/4
+	base := float64(imageHeight)/4 + rng.Float64()*float64(imageHeight)/2
+	freq, phase := 0.01+rng.Float64()*0.05, rng.Float64()*2*math.Pi
+	thick := 1 + rng.IntN(2)
+	for x := 0; x < imageWidth; x++ {
+		y := int(base + amp*math.Sin(float64(x)*freq+phase))
+		for t := 0; t < thick; t++ {
+			if image.Pt(x, y+t).In(img.Rect) {
+				img.SetRGBA(x, y+t, c)
+			}
+		}
+	}
+}
+
+func randomDark(rng *rand.Rand) color.RGBA {
+	return color.RGBA{R: uint8(rng.IntN(120)), G: uint8(rng.IntN(120)), B: uint8(rng.IntN(120)), A: 255}
+}
+
+func randomLight(rng *rand.Rand) color.RGBA {
+	return color.RGBA{R: uint8(200 + rng.IntN(56)), G: uint8(200 + rng.IntN(56)), B: uint8(200 + rng.IntN(56)), A: 255}
+}
+
+func randomColor(rng *rand.Rand) color.RGBA {
+	return color.RGBA{R: uint8(rng.IntN(256)), G: uint8(rng.IntN(256)), B: uint8(rng.IntN(256)), A: 255}
+}
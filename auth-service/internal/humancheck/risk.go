@@ -0,0 +1,168 @@
+package humancheck
+
+import (
+	"context"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	riskIpPrefix          = "RISK_IP_"      // RISK_IP_<ip> -> 一小时内发起人机验证的次数
+	riskFingerprintPrefix = "RISK_FP_"      // RISK_FP_<fingerprint> -> 一小时内发起人机验证的次数
+	riskFailIpPrefix      = "RISK_FAIL_IP_" // RISK_FAIL_IP_<ip> -> 一小时内人机验证失败次数
+	riskFailFpPrefix      = "RISK_FAIL_FP_" // RISK_FAIL_FP_<fingerprint> -> 一小时内人机验证失败次数
+	riskWindow            = time.Hour
+
+	MaxRiskScore = 100
+)
+
+// RiskInput 评估风险使用的客户端信息
+type RiskInput struct {
+	Ip          string
+	Fingerprint string // 客户端上报的设备指纹，可能为空或被伪造，只作为风险信号
+	UserAgent   string
+}
+
+// RiskScorer 风险评分，返回 0~MaxRiskScore，分数越高人机验证越严格
+// 可替换为接入设备指纹服务、IP 信誉库等外部系统的实现
+type RiskScorer interface {
+	// Score 发起人机验证时评分
+	Score(ctx context.Context, in *RiskInput) (int, error)
+	// Report 上报人机验证结果，供评分参考
+	Report(ctx context.Context, in *RiskInput, passed bool) error
+}
+
+// RiskConfig 内置规则评分配置
+type RiskConfig struct {
+	TrustedCidrs      []string // 可信网段（如内网、办公网），直接评为 0 分
+	BlockedCidrs      []string // 封禁网段，直接评为满分
+	IpHourlyLimit     int64    // 同一 IP 每小时发起次数超过该值后加分
+	DeviceHourlyLimit int64    // 同一设备指纹每小时发起次数超过该值后加分
+}
+
+// RuleScorer 基于规则的风险评分：网段黑白名单、缺失的设备信息、请求频率以及近期失败次数
+// 计数保存在 lib/cache 中，窗口为一小时
+type RuleScorer struct {
+	cache   LibCache.Cache
+	cfg     *RiskConfig
+	trusted []*net.IPNet
+	blocked []*net.IPNet
+}
+
+func NewRuleScorer(cache LibCache.Cache, cfg *RiskConfig) (*RuleScorer, error) {
+	s := &RuleScorer{cache: cache, cfg: cfg}
+	var err error
+	if s.trusted, err = parseCidrs(cfg.TrustedCidrs); err != nil {
+		return nil, err
+	}
+	if s.blocked, err = parseCidrs(cfg.BlockedCidrs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *RuleScorer) Score(ctx context.Context, in *RiskInput) (int, error) {
+	ip := net.ParseIP(in.Ip)
+	if ip != nil && contains(s.blocked, ip) {
+		return MaxRiskScore, nil
+	}
+	if ip != nil && contains(s.trusted, ip) {
+		return 0, nil
+	}
+
+	score := 0
+	if in.Fingerprint == "" {
+		score += 25
+	}
+	if in.UserAgent == "" {
+		score += 15
+	}
+
+	// 请求频率：超出部分每次加 10 分
+	n, err := s.incr(ctx, riskIpPrefix+in.Ip)
+	if err != nil {
+		return 0, err
+	}
+	score += over(n, s.cfg.IpHourlyLimit) * 10
+	if in.Fingerprint != "" {
+		if n, err = s.incr(ctx, riskFingerprintPrefix+in.Fingerprint); err != nil {
+			return 0, err
+		}
+		score += over(n, s.cfg.DeviceHourlyLimit) * 10
+	}
+
+	// 近期失败：每次加 15 分，IP 与设备取较大值
+	fails, err := s.count(ctx, riskFailIpPrefix+in.Ip)
+	if err != nil {
+		return 0, err
+	}
+	if in.Fingerprint != "" {
+		fpFails, err := s.count(ctx, riskFailFpPrefix+in.Fingerprint)
+		if err != nil {
+			return 0, err
+		}
+		fails = max(fails, fpFails)
+	}
+	score += int(fails) * 15
+	return min(score, MaxRiskScore), nil
+}
+
+func (s *RuleScorer) Report(ctx context.Context, in *RiskInput, passed bool) error {
+	if passed {
+		return nil
+	}
+	if _, err := s.incr(ctx, riskFailIpPrefix+in.Ip); err != nil {
+		return err
+	}
+	if in.Fingerprint != "" {
+		if _, err := s.incr(ctx, riskFailFpPrefix+in.Fingerprint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// incr 计数加一，首次计数时原子地设置窗口过期时间，避免计数器永不过期
+func (s *RuleScorer) incr(ctx context.Context, key string) (int64, error) {
+	return s.cache.IncrExpire(ctx, key, riskWindow)
+}
+
+func (s *RuleScorer) count(ctx context.Context, key string) (int64, error) {
+	v, err := s.cache.Get(ctx, key)
+	if err != nil || v == "" {
+		return 0, err
+	}
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n, nil
+}
+
+func parseCidrs(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, c := range list {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// over n 超出 limit 的部分，limit 为 0 表示不限制
+func over(n, limit int64) int {
+	if limit <= 0 || n <= limit {
+		return 0
+	}
+	return int(n - limit)
+}
@@ -0,0 +1,119 @@
+package humancheck
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand/v2"
+)
+
+const (
+	sliderWidth  = 300
+	sliderHeight = 160
+	pieceSize    = 44                    // 拼图块主体边长
+	pieceKnob    = 8                     // 拼图块凸起半径
+	pieceFull    = pieceSize + pieceKnob // 拼图块图片的宽和高（含凸起）
+)
+
+// puzzle 生成的滑块拼图，X 为答案，客户端只拿到背景、拼图块和 Y
+// 拼图块图片左上角对应背景中的 (X, Y-pieceKnob)
+type puzzle struct {
+	Background image.Image
+	Piece      image.Image
+	X, Y       int
+}
+
+// renderSlider 生成带缺口的背景图和对应的拼图块
+func renderSlider(rng *rand.Rand) *puzzle {
+	bg := sliderBackground(rng)
+	minX := pieceFull + 10
+	p := &puzzle{
+		X: minX + rng.IntN(sliderWidth-pieceFull-10-minX),
+		Y: pieceKnob + rng.IntN(sliderHeight-pieceFull-pieceKnob),
+	}
+
+	// 拼图块从原图中截取，需在挖出缺口前完成
+	piece := image.NewRGBA(image.Rect(0, 0, pieceFull+1, pieceFull))
+	for y := 0; y < piece.Rect.Dy(); y++ {
+		for x := 0; x < piece.Rect.Dx(); x++ {
+			switch {
+			case pieceEdge(x, y-pieceKnob):
+				piece.SetRGBA(x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+			case inPiece(x, y-pieceKnob):
+				piece.SetRGBA(x, y, bg.RGBAAt(p.X+x, p.Y+y-pieceKnob))
+			}
+		}
+	}
+	p.Piece = piece
+
+	carve(bg, p.X, p.Y)
+	p.Background = bg
+	return p
+}
+
+// sliderBackground 渐变底色叠加随机色块，避免缺口位置能通过纯色差直接识别
+func sliderBackground(rng *rand.Rand) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, sliderWidth, sliderHeight))
+	from, to := randomColor(rng), randomColor(rng)
+	for y := 0; y < sliderHeight; y++ {
+		for x := 0; x < sliderWidth; x++ {
+			t := float64(x+y) / float64(sliderWidth+sliderHeight)
+			img.SetRGBA(x, y, lerp(from, to, t))
+		}
+	}
+	for i := 0; i < 12; i++ {
+		c := randomColor(rng)
+		cx, cy, r := rng.IntN(sliderWidth), rng.IntN(sliderHeight), 10+rng.IntN(40)
+		for y := cy - r; y <= cy+r; y++ {
+			for x := cx - r; x <= cx+r; x++ {
+				if image.Pt(x, y).In(img.Rect) && (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+					img.SetRGBA(x, y, lerp(img.RGBAAt(x, y), c, 0.5))
+				}
+			}
+		}
+	}
+	return img
+}
+
+// carve 在 (ox, oy) 处挖出拼图形状的缺口：压暗并描边
+func carve(img *image.RGBA, ox, oy int) {
+	black := color.RGBA{A: 255}
+	for y := -pieceKnob; y < pieceFull; y++ {
+		for x := 0; x <= pieceFull; x++ {
+			px, py := ox+x, oy+y
+			if !image.Pt(px, py).In(img.Rect) {
+				continue
+			}
+			switch {
+			case pieceEdge(x, y):
+				img.SetRGBA(px, py, color.RGBA{R: 240, G: 240, B: 240, A: 255})
+			case inPiece(x, y):
+				img.SetRGBA(px, py, lerp(img.RGBAAt(px, py), black, 0.45))
+			}
+		}
+	}
+}
+
+// inPiece 拼图形状：pieceSize 正方形，上边和右边各有一个半圆凸起，坐标以正方形左上角为原点
+func inPiece(x, y int) bool {
+	if x >= 0 && x < pieceSize && y >= 0 && y < pieceSize {
+		return true
+	}
+	mid := float64(pieceSize) / 2
+	top := math.Hypot(float64(x)-mid, float64(y)) <= pieceKnob
+	right := math.Hypot(float64(x-pieceSize), float64(y)-mid) <= pieceKnob
+	return top || right
+}
+
+// pieceEdge 拼图形状的 1 像素描边
+func pieceEdge(x, y int) bool {
+	if !inPiece(x, y) {
+		return false
+	}
+	return !inPiece(x-1, y) || !inPiece(x+1, y) || !inPiece(x, y-1) || !inPiece(x, y+1)
+}
+
+func lerp(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 { return uint8(float64(x)*(1-t) + float64(y)*t) }
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
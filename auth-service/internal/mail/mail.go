@@ -0,0 +1,284 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	TemplateCaptcha = "captcha" // 验证码邮件模板
+
+	StatusSent   = "SENT"   // 邮件服务器已受理
+	StatusFailed = "FAILED" // 重试后仍发送失败
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// Message 待发送的邮件，Text 与 Html 至少有一个不为空
+type Message struct {
+	From      string
+	To        string
+	Subject   string
+	Text      string
+	Html      string
+	MessageId string // 为空时由 Bytes 生成
+}
+
+// MailSender 邮件服务抽象，返回邮件的 Message-ID
+type MailSender interface {
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// Report 邮件投递状态报告
+type Report struct {
+	MsgId    string
+	To       string
+	Template string
+	Locale   string
+	Status   string
+	Attempts int
+	Err      error
+}
+
+// StatusCallback 邮件投递状态回调
+type StatusCallback func(report *Report)
+
+// SmtpConfig SMTP 服务器配置
+type SmtpConfig struct {
+	Host     string
+	Port     int
+	Username string // 为空时不做认证
+	Password string
+	Tls      string // starttls | tls（465 端口隐式 TLS）| none（仅限本地调试）
+}
+
+// Config 邮件配置
+type Config struct {
+	Provider      string        // 邮件服务：smtp | maildir（写入本地 maildir 目录）
+	Maildir       string        // maildir 服务的目录，按 tmp/new/cur 结构存放
+	From          string        // 发件人，如 "IM-System <no-reply@example.com>"
+	DefaultLocale string        // 请求语言没有对应模板时使用的语言
+	Timeout       time.Duration // 单次发送超时时间
+	Retry         int           // 失败后的最大重试次数
+	Backoff       time.Duration // 首次重试等待时间，之后按指数递增
+	TemplateDir   string        // 自定义模板目录，为空时使用内置模板
+	Smtp          SmtpConfig
+}
+
+// localeTemplates 某一语言下的一组邮件模板
+// text 模板中通过 {{define "subject"}} 定义邮件标题，其余内容为纯文本正文
+type localeTemplates struct {
+	text map[string]*template.Template
+	html map[string]*htmlTemplate.Template
+}
+
+// Dispatcher 负责按语言渲染模板、失败重试和投递状态回调
+type Dispatcher struct {
+	sender   MailSender
+	cfg      *Config
+	locales  map[string]*localeTemplates
+	callback StatusCallback
+}
+
+// New 按配置创建邮件服务及对应的 Dispatcher
+func New(cfg *Config) (*Dispatcher, error) {
+	var sender MailSender
+	switch cfg.Provider {
+	case "smtp":
+		sender = NewSmtpSender(&cfg.Smtp)
+	case "maildir":
+		sender = NewMaildirSender(cfg.Maildir)
+	default:
+		return nil, fmt.Errorf("unknown mail provider: %q", cfg.Provider)
+	}
+	return NewDispatcher(sender, cfg)
+}
+
+func NewDispatcher(sender MailSender, cfg *Config) (*Dispatcher, error) {
+	var fsys fs.FS
+	if cfg.TemplateDir != "" {
+		fsys = os.DirFS(cfg.TemplateDir)
+	} else {
+		sub, err := fs.Sub(builtinTemplates, "templates")
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	locales, err := loadTemplates(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := locales[cfg.DefaultLocale]; !ok {
+		return nil, fmt.Errorf("mail templates for default locale %q not found", cfg.DefaultLocale)
+	}
+	return &Dispatcher{sender: sender, cfg: cfg, locales: locales}, nil
+}
+
+// loadTemplates 读取模板目录，目录结构为 <locale>/<name>.txt 和 <locale>/<name>.html
+func loadTemplates(fsys fs.FS) (map[string]*localeTemplates, error) {
+	dirs, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read mail templates: %w", err)
+	}
+	locales := make(map[string]*localeTemplates, len(dirs))
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		lt := &localeTemplates{
+			text: make(map[string]*template.Template),
+			html: make(map[string]*htmlTemplate.Template),
+		}
+		files, err := fs.ReadDir(fsys, dir.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read mail templates %s: %w", dir.Name(), err)
+		}
+		for _, f := range files {
+			file := path.Join(dir.Name(), f.Name())
+			content, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("read mail template %s: %w", file, err)
+			}
+			name := strings.TrimSuffix(f.Name(), path.Ext(f.Name()))
+			switch path.Ext(f.Name()) {
+			case ".txt":
+				t, err := template.New(name).Option("missingkey=error").Parse(string(content))
+				if err != nil {
+					return nil, fmt.Errorf("parse mail template %s: %w", file, err)
+				}
+				if t.Lookup("subject") == nil {
+					return nil, fmt.Errorf("mail template %s: subject not defined", file)
+				}
+				lt.text[name] = t
+			case ".html":
+				t, err := htmlTemplate.New(name).Option("missingkey=error").Parse(string(content))
+				if err != nil {
+					return nil, fmt.Errorf("parse mail template %s: %w", file, err)
+				}
+				lt.html[name] = t
+			}
+		}
+		locales[dir.Name()] = lt
+	}
+	return locales, nil
+}
+
+// OnStatus 设置投递状态回调
+func (d *Dispatcher) OnStatus(cb StatusCallback) {
+	d.callback = cb
+}
+
+// ResolveLocale 选择与请求语言最匹配的模板语言：完全匹配优先，其次语言相同（如 en 匹配 en-US），否则使用默认语言
+func (d *Dispatcher) ResolveLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "" {
+		return d.cfg.DefaultLocale
+	}
+	for name := range d.locales {
+		if strings.EqualFold(name, locale) {
+			return name
+		}
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	// 同一语言有多个地区模板时默认语言优先，其余按名称排序保证结果稳定
+	if defLang, _, _ := strings.Cut(d.cfg.DefaultLocale, "-"); strings.EqualFold(defLang, lang) {
+		return d.cfg.DefaultLocale
+	}
+	match := ""
+	for name := range d.locales {
+		if l, _, _ := strings.Cut(name, "-"); strings.EqualFold(l, lang) && (match == "" || name < match) {
+			match = name
+		}
+	}
+	if match != "" {
+		return match
+	}
+	return d.cfg.DefaultLocale
+}
+
+// render 渲染指定语言的邮件标题、纯文本正文和 HTML 正文
+func (d *Dispatcher) render(name, locale string, params map[string]string) (*Message, error) {
+	lt := d.locales[locale]
+	t, ok := lt.text[name]
+	if !ok {
+		return nil, fmt.Errorf("mail template %s/%s not found", locale, name)
+	}
+	msg := &Message{From: d.cfg.From}
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "subject", params); err != nil {
+		return nil, fmt.Errorf("render mail template %s/%s: %w", locale, name, err)
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := t.Execute(&buf, params); err != nil {
+		return nil, fmt.Errorf("render mail template %s/%s: %w", locale, name, err)
+	}
+	msg.Text = strings.TrimSpace(buf.String()) + "\n"
+
+	if h, ok := lt.html[name]; ok {
+		buf.Reset()
+		if err := h.Execute(&buf, params); err != nil {
+			return nil, fmt.Errorf("render mail template %s/%s.html: %w", locale, name, err)
+		}
+		msg.Html = buf.String()
+	}
+	return msg, nil
+}
+
+// SendTemplate 按语言渲染模板并发送邮件，失败时按指数退避重试，最终结果通过状态回调通知
+func (d *Dispatcher) SendTemplate(ctx context.Context, to, name, locale string, params map[string]string) error {
+	locale = d.ResolveLocale(locale)
+	msg, err := d.render(name, locale, params)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+
+	report := &Report{To: to, Template: name, Locale: locale}
+	backoff := d.cfg.Backoff
+	for report.Attempts = 1; ; report.Attempts++ {
+		report.MsgId, report.Err = d.send(ctx, msg)
+		if report.Err == nil || report.Attempts > d.cfg.Retry {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			report.Err = ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+			continue
+		}
+		break
+	}
+
+	report.Status = StatusSent
+	if report.Err != nil {
+		report.Status = StatusFailed
+	}
+	if d.callback != nil {
+		d.callback(report)
+	}
+	return report.Err
+}
+
+// send 单次发送，带超时控制
+func (d *Dispatcher) send(ctx context.Context, msg *Message) (string, error) {
+	if d.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.cfg.Timeout)
+		defer cancel()
+	}
+	return d.sender.Send(ctx, msg)
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// MaildirSender 将邮件写入本地 maildir 目录的 new 子目录，供本地开发和集成测试读取
+// 按 maildir 约定先写入 tmp 再原子重命名到 new，读取方不会看到写了一半的文件
+type MaildirSender struct {
+	dir string
+	seq atomic.Uint64
+}
+
+func NewMaildirSender(dir string) *MaildirSender {
+	return &MaildirSender{dir: dir}
+}
+
+func (s *MaildirSender) Send(ctx context.Context, msg *Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	content, err := msg.Bytes()
+	if err != nil {
+		return "", err
+	}
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err = os.MkdirAll(filepath.Join(s.dir, sub), 0o755); err != nil {
+			return "", err
+		}
+	}
+
+	host, _ := os.Hostname()
+	name := fmt.Sprintf("%d.P%dQ%d.%s", time.Now().UnixNano(), os.Getpid(), s.seq.Add(1), host)
+	tmp := filepath.Join(s.dir, "tmp", name)
+	if err = os.WriteFile(tmp, content, 0o644); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp, filepath.Join(s.dir, "new", name)); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return msg.MessageId, nil
+}
@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netMail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Bytes 按 RFC 5322 组装邮件，同时有纯文本和 HTML 正文时使用 multipart/alternative
+// MessageId 为空时生成一个新的 Message-ID 并回填
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := netMail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", m.From, err)
+	}
+	to, err := netMail.ParseAddress(m.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address %q: %w", m.To, err)
+	}
+	if m.MessageId == "" {
+		if m.MessageId, err = newMessageId(from.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.BEncoding.Encode("UTF-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", m.MessageId)
+	header("MIME-Version", "1.0")
+
+	if m.Text == "" || m.Html == "" {
+		contentType, body := "text/plain; charset=UTF-8", m.Text
+		if m.Text == "" {
+			contentType, body = "text/html; charset=UTF-8", m.Html
+		}
+		header("Content-Type", contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err = writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	header("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+	// 按 RFC 2046，越靠后的部分越被客户端优先展示
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.Html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err = mw.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable 按 quoted-printable 编码写入，换行统一输出为 CRLF
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// newMessageId 生成 <随机串@发件人域名> 形式的 Message-ID
+func newMessageId(from string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if i := strings.LastIndexByte(from, '@'); i >= 0 {
+		domain = from[i+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
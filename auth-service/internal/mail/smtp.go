@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	netMail "net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+// SmtpSender 通过 SMTP 服务器投递邮件，每封邮件使用一个独立连接
+type SmtpSender struct {
+	cfg *SmtpConfig
+}
+
+func NewSmtpSender(cfg *SmtpConfig) *SmtpSender {
+	return &SmtpSender{cfg: cfg}
+}
+
+func (s *SmtpSender) Send(ctx context.Context, msg *Message) (string, error) {
+	content, err := msg.Bytes()
+	if err != nil {
+		return "", err
+	}
+	from, _ := netMail.ParseAddress(msg.From)
+	to, _ := netMail.ParseAddress(msg.To)
+
+	c, err := s.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	if s.cfg.Tls == "starttls" {
+		if err = c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return "", fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err = c.Auth(auth); err != nil {
+			return "", fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err = c.Mail(from.Address); err != nil {
+		return "", fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err = c.Rcpt(to.Address); err != nil {
+		return "", fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return "", fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err = w.Write(content); err != nil {
+		return "", fmt.Errorf("smtp data: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return "", fmt.Errorf("smtp data: %w", err)
+	}
+	if err = c.Quit(); err != nil {
+		return "", fmt.Errorf("smtp quit: %w", err)
+	}
+	return msg.MessageId, nil
+}
+
+// dial 建立到 SMTP 服务器的连接，tls 模式下直接建立 TLS 连接，连接的读写截止时间与 ctx 一致
+func (s *SmtpSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var conn net.Conn
+	var err error
+	if s.cfg.Tls == "tls" {
+		d := &tls.Dialer{Config: &tls.Config{ServerName: s.cfg.Host}}
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("smtp dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp handshake: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,400 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const codeKeyPrefix = "OAUTH_CODE_" // OAUTH_CODE_<code> -> 授权码绑定的授权（JSON）
+
+// AuthorizeRequest 授权端点参数
+type AuthorizeRequest struct {
+	ClientId            string
+	RedirectUri         string // 为空时使用客户端唯一登记的回调地址
+	Scope               string // 空格分隔，为空时申请客户端允许的全部范围
+	State               string
+	CodeChallenge       string // PKCE，只支持 S256
+	CodeChallengeMethod string
+}
+
+// Authorization 校验通过的授权请求，用于展示授权页
+type Authorization struct {
+	Client      *data.OAuthClient
+	RedirectUri string
+	Scopes      []string // 本次申请的范围
+	Granted     []string // 此前已同意的范围
+	NeedConsent bool     // 申请的范围超出已同意的范围时需要用户确认
+}
+
+// codeGrant 授权码绑定的授权
+type codeGrant struct {
+	ClientId         string `json:"clientId"`
+	UserId           int64  `json:"userId"`
+	RedirectUri      string `json:"redirectUri"`
+	RedirectExplicit bool   `json:"redirectExplicit"` // 授权请求是否携带了 redirect_uri，携带时换取令牌必须一致
+	Scope            string `json:"scope"`
+	GrantId          string `json:"grantId"`
+	CodeChallenge    string `json:"codeChallenge"`
+}
+
+// Authorize 校验授权请求，返回客户端信息、申请的范围以及是否需要用户确认
+// 客户端或回调地址不合法时不能重定向回客户端，直接返回错误
+func (m *Manager) Authorize(ctx context.Context, userId int64, req *AuthorizeRequest) (*Authorization, error) {
+	client, err := m.repo.FindClient(ctx, req.ClientId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 客户端", err)
+	}
+	if client == nil {
+		return nil, libErrors.GrpcError(errs.ErrOAuthClientNotExist, "应用不存在")
+	}
+	redirectUri := req.RedirectUri
+	if redirectUri == "" && len(client.RedirectUris) == 1 {
+		redirectUri = client.RedirectUris[0]
+	}
+	if !contains(client.RedirectUris, redirectUri) {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRedirect, "回调地址未登记")
+	}
+	if !contains(client.GrantTypes, GrantAuthorizationCode) {
+		return nil, libErrors.GrpcError(errs.ErrOAuthUnauthorizedClient, "应用未开通授权码模式")
+	}
+	scopes, err := requestedScopes(client, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "code_challenge_method 只支持 S256")
+	}
+	if req.CodeChallenge == "" && req.CodeChallengeMethod != "" {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "缺少 code_challenge")
+	}
+
+	consent, err := m.repo.FindConsent(ctx, userId, client.ClientId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 授权", err)
+	}
+	auth := &Authorization{Client: client, RedirectUri: redirectUri, Scopes: scopes, NeedConsent: true}
+	if consent != nil {
+		auth.Granted, auth.NeedConsent = consent.Scopes, false
+		for _, s := range scopes {
+			if !contains(consent.Scopes, s) {
+				auth.NeedConsent = true
+				break
+			}
+		}
+	}
+	return auth, nil
+}
+
+// Approve 用户确认或拒绝授权，返回重定向回客户端的地址
+// 同意时合并保存授权范围并签发授权码，拒绝时回调地址携带 error=access_denied
+func (m *Manager) Approve(ctx context.Context, userId int64, req *AuthorizeRequest, approved bool) (string, error) {
+	auth, err := m.Authorize(ctx, userId, req)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	if !approved {
+		q.Set("error", "access_denied")
+		return appendQuery(auth.RedirectUri, q), nil
+	}
+
+	consent, err := m.repo.FindConsent(ctx, userId, auth.Client.ClientId)
+	if err != nil {
+		return "", internalError("查询 OAuth2 授权", err)
+	}
+	now := time.Now().UnixMilli()
+	if consent == nil {
+		consent = &data.OAuthConsent{UserId: userId, ClientId: auth.Client.ClientId, CreateTime: now}
+		if consent.GrantId, err = utils.RandomToken(16); err != nil {
+			return "", internalError("生成授权记录 ID", err)
+		}
+	}
+	consent.Scopes, consent.UpdateTime = dedupe(append(consent.Scopes, auth.Scopes...)), now
+	if err = m.repo.SaveConsent(ctx, consent); err != nil {
+		return "", internalError("保存 OAuth2 授权", err)
+	}
+
+	code, err := utils.RandomToken(32)
+	if err != nil {
+		return "", internalError("生成授权码", err)
+	}
+	b, err := json.Marshal(&codeGrant{
+		ClientId:         auth.Client.ClientId,
+		UserId:           userId,
+		RedirectUri:      auth.RedirectUri,
+		RedirectExplicit: req.RedirectUri != "",
+		Scope:            strings.Join(auth.Scopes, " "),
+		GrantId:          consent.GrantId,
+		CodeChallenge:    req.CodeChallenge,
+	})
+	if err != nil {
+		return "", internalError("序列化授权码", err)
+	}
+	if err = m.cache.Put(ctx, codeKeyPrefix+code, string(b), m.cfg.CodeExpire); err != nil {
+		return "", internalError("保存授权码", err)
+	}
+	q.Set("code", code)
+	return appendQuery(auth.RedirectUri, q), nil
+}
+
+// TokenRequest 令牌端点参数
+type TokenRequest struct {
+	GrantType    string
+	ClientId     string
+	ClientSecret string
+	Code         string
+	RedirectUri  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string // 客户端凭证模式申请的范围，为空时为客户端允许的全部范围
+}
+
+// Token 令牌端点签发的令牌
+type Token struct {
+	*token.Pair
+	Scope string
+}
+
+// Token 令牌端点：按授权方式校验并签发令牌
+func (m *Manager) Token(ctx context.Context, req *TokenRequest) (*Token, error) {
+	client, err := m.authenticate(ctx, req.ClientId, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	switch req.GrantType {
+	case GrantAuthorizationCode, GrantClientCredentials, GrantRefreshToken:
+	default:
+		return nil, libErrors.GrpcError(errs.ErrOAuthUnsupportedGrant, "不支持的授权方式")
+	}
+	if !contains(client.GrantTypes, req.GrantType) {
+		return nil, libErrors.GrpcError(errs.ErrOAuthUnauthorizedClient, "应用未开通该授权方式")
+	}
+
+	switch req.GrantType {
+	case GrantAuthorizationCode:
+		return m.exchangeCode(ctx, client, req)
+	case GrantClientCredentials:
+		scopes, err := requestedScopes(client, req.Scope)
+		if err != nil {
+			return nil, err
+		}
+		// 客户端以自身身份访问，不代表任何账号
+		g := &token.Grant{ClientId: client.ClientId, Scope: strings.Join(scopes, " "), GrantId: client.GrantId}
+		pair, err := m.tokens.IssueGrant(ctx, g, false)
+		if err != nil {
+			return nil, internalError("签发令牌", err)
+		}
+		return &Token{Pair: pair, Scope: g.Scope}, nil
+	default:
+		if req.RefreshToken == "" {
+			return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "缺少 refresh_token")
+		}
+		claims, pair, err := m.tokens.Refresh(ctx, req.RefreshToken, client.ClientId)
+		if err != nil {
+			if errors.Is(err, jwts.ErrTokenInvalid) || errors.Is(err, jwts.ErrTokenExpired) ||
+				errors.Is(err, token.ErrTokenRevoked) || errors.Is(err, token.ErrRefreshTokenReuse) {
+				return nil, libErrors.GrpcError(errs.ErrOAuthInvalidGrant, "刷新令牌无效或已过期")
+			}
+			return nil, internalError("刷新令牌", err)
+		}
+		return &Token{Pair: pair, Scope: claims.Scope}, nil
+	}
+}
+
+// exchangeCode 用授权码换取令牌，授权码只能使用一次
+func (m *Manager) exchangeCode(ctx context.Context, client *data.OAuthClient, req *TokenRequest) (*Token, error) {
+	invalid := libErrors.GrpcError(errs.ErrOAuthInvalidGrant, "授权码无效或已过期")
+	if req.Code == "" {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "缺少 code")
+	}
+	key := codeKeyPrefix + req.Code
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return nil, internalError("读取授权码", err)
+	}
+	if val == "" {
+		return nil, invalid
+	}
+	// 并发换取时只有成功删除的一方可以继续
+	consumed, err := m.cache.Delete(ctx, key)
+	if err != nil {
+		return nil, internalError("删除授权码", err)
+	}
+	if !consumed {
+		return nil, invalid
+	}
+	cg := &codeGrant{}
+	if err = json.Unmarshal([]byte(val), cg); err != nil {
+		return nil, internalError("解析授权码", err)
+	}
+
+	if cg.ClientId != client.ClientId {
+		return nil, invalid
+	}
+	if (cg.RedirectExplicit || req.RedirectUri != "") && req.RedirectUri != cg.RedirectUri {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidGrant, "redirect_uri 与授权请求不一致")
+	}
+	if cg.CodeChallenge != "" {
+		sum := sha256.Sum256([]byte(req.CodeVerifier))
+		challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+		if req.CodeVerifier == "" || subtle.ConstantTimeCompare([]byte(challenge), []byte(cg.CodeChallenge)) != 1 {
+			return nil, libErrors.GrpcError(errs.ErrOAuthInvalidGrant, "code_verifier 校验失败")
+		}
+	}
+	// 换取前用户已撤销授权
+	consent, err := m.repo.FindConsent(ctx, cg.UserId, client.ClientId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 授权", err)
+	}
+	if consent == nil || consent.GrantId != cg.GrantId {
+		return nil, invalid
+	}
+
+	g := &token.Grant{UserId: cg.UserId, ClientId: client.ClientId, Scope: cg.Scope, GrantId: cg.GrantId}
+	pair, err := m.tokens.IssueGrant(ctx, g, contains(client.GrantTypes, GrantRefreshToken))
+	if err != nil {
+		return nil, internalError("签发令牌", err)
+	}
+	return &Token{Pair: pair, Scope: cg.Scope}, nil
+}
+
+// Introspection 令牌内省结果（RFC 7662），Active 为 false 时其余字段为空
+type Introspection struct {
+	Active    bool
+	ClientId  string
+	UserId    int64
+	Scope     string
+	TokenType string
+	TokenId   string
+	IssuedAt  int64 // 毫秒时间戳
+	ExpireAt  int64
+}
+
+// Introspect 令牌内省，客户端只能查询自己获得的令牌，其他令牌一律视为无效
+func (m *Manager) Introspect(ctx context.Context, clientId, clientSecret, raw string) (*Introspection, error) {
+	client, err := m.authenticate(ctx, clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := m.tokens.Inspect(ctx, raw)
+	if err != nil {
+		if errors.Is(err, jwts.ErrTokenInvalid) || errors.Is(err, jwts.ErrTokenExpired) || errors.Is(err, token.ErrTokenRevoked) {
+			return &Introspection{}, nil
+		}
+		return nil, internalError("校验令牌", err)
+	}
+	if claims.ClientId != client.ClientId {
+		return &Introspection{}, nil
+	}
+	return &Introspection{
+		Active:    true,
+		ClientId:  claims.ClientId,
+		UserId:    claims.UserId,
+		Scope:     claims.Scope,
+		TokenType: claims.TokenType,
+		TokenId:   claims.ID,
+		IssuedAt:  claims.IssuedAt.UnixMilli(),
+		ExpireAt:  claims.ExpiresAt.UnixMilli(),
+	}, nil
+}
+
+// Consent 账号对应用的授权以及应用名称
+type Consent struct {
+	*data.OAuthConsent
+	ClientName string
+}
+
+// ListConsents 账号授权过的应用
+func (m *Manager) ListConsents(ctx context.Context, userId int64) ([]*Consent, error) {
+	list, err := m.repo.ListConsents(ctx, userId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 授权", err)
+	}
+	consents := make([]*Consent, 0, len(list))
+	for _, c := range list {
+		client, err := m.repo.FindClient(ctx, c.ClientId)
+		if err != nil {
+			return nil, internalError("查询 OAuth2 客户端", err)
+		}
+		if client == nil {
+			continue
+		}
+		consents = append(consents, &Consent{OAuthConsent: c, ClientName: client.Name})
+	}
+	return consents, nil
+}
+
+// RevokeConsent 撤销账号对应用的授权，该授权下签发的令牌全部失效
+func (m *Manager) RevokeConsent(ctx context.Context, userId int64, clientId string) error {
+	consent, err := m.repo.FindConsent(ctx, userId, clientId)
+	if err != nil {
+		return internalError("查询 OAuth2 授权", err)
+	}
+	if consent == nil {
+		return libErrors.GrpcError(errs.ErrOAuthClientNotExist, "未授权该应用")
+	}
+	if _, err = m.repo.DeleteConsent(ctx, userId, clientId); err != nil {
+		return internalError("删除 OAuth2 授权", err)
+	}
+	if err = m.tokens.RevokeGrant(ctx, consent.GrantId); err != nil {
+		return internalError("吊销授权令牌", err)
+	}
+	return nil
+}
+
+// RevokeAllConsents 撤销账号对全部应用的授权，用于申请注销
+func (m *Manager) RevokeAllConsents(ctx context.Context, userId int64) error {
+	list, err := m.repo.ListConsents(ctx, userId)
+	if err != nil {
+		return internalError("查询 OAuth2 授权", err)
+	}
+	for _, c := range list {
+		if err = m.RevokeConsent(ctx, userId, c.ClientId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsGranted 授权范围此前是否已同意
+func (a *Authorization) IsGranted(scope string) bool {
+	return contains(a.Granted, scope)
+}
+
+// requestedScopes 解析申请的范围，须在客户端允许的范围内，为空时为客户端允许的全部范围
+func requestedScopes(client *data.OAuthClient, scope string) ([]string, error) {
+	fields := strings.Fields(scope)
+	if len(fields) == 0 {
+		return client.Scopes, nil
+	}
+	for _, s := range fields {
+		if !contains(client.Scopes, s) {
+			return nil, libErrors.GrpcError(errs.ErrOAuthInvalidScope, "应用无权申请该授权范围: "+s)
+		}
+	}
+	return dedupe(fields), nil
+}
+
+func appendQuery(rawUrl string, q url.Values) string {
+	sep := "?"
+	if strings.Contains(rawUrl, "?") {
+		sep = "&"
+	}
+	return rawUrl + sep + q.Encode()
+}
@@ -0,0 +1,305 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"net"
+	"net/url"
+	"time"
+	"unicode/utf8"
+)
+
+// 授权范围，令牌的 scope 声明以空格分隔
+const (
+	ScopeMessagesRead = "messages:read" // 读取消息
+	ScopeMessagesSend = "messages:send" // 发送消息
+	ScopeGroupsManage = "groups:manage" // 管理群组
+)
+
+// 授权方式
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// scopeDescriptions 授权范围 -> 授权页展示的说明
+var scopeDescriptions = map[string]string{
+	ScopeMessagesRead: "读取你的消息",
+	ScopeMessagesSend: "以你的身份发送消息",
+	ScopeGroupsManage: "管理你创建或管理的群组",
+}
+
+// SupportedScope 是否为支持的授权范围
+func SupportedScope(scope string) bool {
+	_, ok := scopeDescriptions[scope]
+	return ok
+}
+
+// DescribeScope 授权范围的说明
+func DescribeScope(scope string) string {
+	return scopeDescriptions[scope]
+}
+
+// Config OAuth2 授权服务配置
+type Config struct {
+	CodeExpire time.Duration // 授权码有效期
+	MaxClients int           // 每个账号最多注册的客户端数量，0 表示不限制
+}
+
+// Manager OAuth2 授权服务：客户端注册、用户授权、授权码与客户端凭证模式签发令牌、令牌内省
+// 客户端与授权保存在 OAuthRepository 中，授权码保存在 lib/cache 中，令牌由 token.Manager 签发
+type Manager struct {
+	repo   repo.OAuthRepository
+	cache  LibCache.Cache
+	tokens *token.Manager
+	cfg    *Config
+}
+
+func NewManager(oauthRepo repo.OAuthRepository, cache LibCache.Cache, tokens *token.Manager, cfg *Config) *Manager {
+	return &Manager{repo: oauthRepo, cache: cache, tokens: tokens, cfg: cfg}
+}
+
+// ClientSpec 注册客户端的参数
+type ClientSpec struct {
+	Name         string
+	RedirectUris []string
+	Scopes       []string
+	GrantTypes   []string // 为空时默认授权码 + 刷新令牌
+}
+
+// RegisterClient 为账号注册客户端，返回客户端和明文密钥，密钥只返回这一次
+func (m *Manager) RegisterClient(ctx context.Context, ownerId int64, spec *ClientSpec) (*data.OAuthClient, string, error) {
+	client, err := validateSpec(spec)
+	if err != nil {
+		return nil, "", err
+	}
+	if m.cfg.MaxClients > 0 {
+		list, err := m.repo.ListClients(ctx, ownerId)
+		if err != nil {
+			return nil, "", internalError("查询 OAuth2 客户端", err)
+		}
+		if len(list) >= m.cfg.MaxClients {
+			return nil, "", libErrors.GrpcError(errs.ErrOAuthInvalidRequest, fmt.Sprintf("最多注册 %d 个应用", m.cfg.MaxClients))
+		}
+	}
+
+	if client.ClientId, err = utils.RandomToken(12); err != nil {
+		return nil, "", internalError("生成客户端 ID", err)
+	}
+	if client.GrantId, err = utils.RandomToken(16); err != nil {
+		return nil, "", internalError("生成授权记录 ID", err)
+	}
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, "", internalError("生成客户端密钥", err)
+	}
+	now := time.Now().UnixMilli()
+	client.SecretHash, client.OwnerId, client.CreateTime, client.UpdateTime = hashSecret(secret), ownerId, now, now
+	if err = m.repo.CreateClient(ctx, client); err != nil {
+		return nil, "", internalError("保存 OAuth2 客户端", err)
+	}
+	return client, secret, nil
+}
+
+// ListClients 账号注册的客户端
+func (m *Manager) ListClients(ctx context.Context, ownerId int64) ([]*data.OAuthClient, error) {
+	list, err := m.repo.ListClients(ctx, ownerId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 客户端", err)
+	}
+	return list, nil
+}
+
+// RotateSecret 重置客户端密钥，旧密钥立即失效，客户端凭证模式签发的令牌一并吊销
+func (m *Manager) RotateSecret(ctx context.Context, ownerId int64, clientId string) (string, error) {
+	client, err := m.ownedClient(ctx, ownerId, clientId)
+	if err != nil {
+		return "", err
+	}
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		return "", internalError("生成客户端密钥", err)
+	}
+	oldGrantId := client.GrantId
+	if client.GrantId, err = utils.RandomToken(16); err != nil {
+		return "", internalError("生成授权记录 ID", err)
+	}
+	client.SecretHash, client.UpdateTime = hashSecret(secret), time.Now().UnixMilli()
+	if err = m.repo.SaveClient(ctx, client); err != nil {
+		return "", internalError("保存 OAuth2 客户端", err)
+	}
+	if err = m.tokens.RevokeGrant(ctx, oldGrantId); err != nil {
+		return "", internalError("吊销客户端令牌", err)
+	}
+	return secret, nil
+}
+
+// DeleteClient 删除客户端，所有用户对它的授权以及已签发的令牌一并失效
+func (m *Manager) DeleteClient(ctx context.Context, ownerId int64, clientId string) error {
+	client, err := m.ownedClient(ctx, ownerId, clientId)
+	if err != nil {
+		return err
+	}
+	grantIds, err := m.repo.DeleteClient(ctx, clientId)
+	if err != nil {
+		return internalError("删除 OAuth2 客户端", err)
+	}
+	for _, id := range append(grantIds, client.GrantId) {
+		if err = m.tokens.RevokeGrant(ctx, id); err != nil {
+			return internalError("吊销客户端令牌", err)
+		}
+	}
+	return nil
+}
+
+// PurgeUser 清除已注销账号的授权和注册的应用，应用的令牌一并吊销
+func (m *Manager) PurgeUser(ctx context.Context, userId int64) error {
+	if err := m.RevokeAllConsents(ctx, userId); err != nil {
+		return err
+	}
+	clients, err := m.repo.ListClients(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, c := range clients {
+		if err = m.DeleteClient(ctx, userId, c.ClientId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownedClient 查询账号注册的客户端，不存在或不属于该账号时返回同样的错误
+func (m *Manager) ownedClient(ctx context.Context, ownerId int64, clientId string) (*data.OAuthClient, error) {
+	client, err := m.repo.FindClient(ctx, clientId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 客户端", err)
+	}
+	if client == nil || client.OwnerId != ownerId {
+		return nil, libErrors.GrpcError(errs.ErrOAuthClientNotExist, "应用不存在")
+	}
+	return client, nil
+}
+
+// authenticate 校验客户端 ID 和密钥
+func (m *Manager) authenticate(ctx context.Context, clientId, secret string) (*data.OAuthClient, error) {
+	if clientId == "" || secret == "" {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidClient, "客户端认证失败")
+	}
+	client, err := m.repo.FindClient(ctx, clientId)
+	if err != nil {
+		return nil, internalError("查询 OAuth2 客户端", err)
+	}
+	// 客户端不存在时也比较一次，避免通过耗时区分
+	expected := hashSecret("")
+	if client != nil {
+		expected = client.SecretHash
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(expected)) != 1 || client == nil {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidClient, "客户端认证失败")
+	}
+	return client, nil
+}
+
+func validateSpec(spec *ClientSpec) (*data.OAuthClient, error) {
+	if spec.Name == "" || utf8.RuneCountInString(spec.Name) > 64 {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "应用名称不能为空且不超过 64 个字符")
+	}
+	client := &data.OAuthClient{Name: spec.Name}
+
+	grantTypes := spec.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{GrantAuthorizationCode, GrantRefreshToken}
+	}
+	for _, g := range grantTypes {
+		if g != GrantAuthorizationCode && g != GrantClientCredentials && g != GrantRefreshToken {
+			return nil, libErrors.GrpcError(errs.ErrOAuthUnsupportedGrant, "不支持的授权方式: "+g)
+		}
+	}
+	client.GrantTypes = dedupe(grantTypes)
+	if contains(client.GrantTypes, GrantRefreshToken) && !contains(client.GrantTypes, GrantAuthorizationCode) {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRequest, "refresh_token 只能与 authorization_code 一起使用")
+	}
+
+	if len(spec.Scopes) == 0 {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidScope, "至少选择一个授权范围")
+	}
+	for _, s := range spec.Scopes {
+		if !SupportedScope(s) {
+			return nil, libErrors.GrpcError(errs.ErrOAuthInvalidScope, "不支持的授权范围: "+s)
+		}
+	}
+	client.Scopes = dedupe(spec.Scopes)
+
+	for _, u := range spec.RedirectUris {
+		if !validRedirect(u) {
+			return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRedirect, "回调地址须为 https 或本机 http 地址且不带片段: "+u)
+		}
+	}
+	client.RedirectUris = dedupe(spec.RedirectUris)
+	if contains(client.GrantTypes, GrantAuthorizationCode) && len(client.RedirectUris) == 0 {
+		return nil, libErrors.GrpcError(errs.ErrOAuthInvalidRedirect, "授权码模式至少需要一个回调地址")
+	}
+	return client, nil
+}
+
+// validRedirect 回调地址须为绝对地址、不带片段，https 或回环地址上的 http
+func validRedirect(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	}
+	return false
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func dedupe(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if !contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,276 @@
+// Package mock 本地模拟 OIDC 身份提供方，仅用于开发调试和联调第三方登录流程
+// 支持发现文档、授权码 + PKCE（S256）、RS256 签名的 ID 令牌和 JWKS，不做任何持久化
+package mock
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	keyId      = "mock-1"
+	codeExpire = time.Minute
+	idExpire   = 10 * time.Minute
+)
+
+// Config 模拟身份提供方配置
+type Config struct {
+	Issuer       string // 签发方，需与依赖方配置的 issuer 完全一致
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string // 允许的回调地址，为空时不限制
+}
+
+// User 登录的模拟用户
+type User struct {
+	Subject       string
+	Name          string
+	Email         string
+	EmailVerified bool
+	Phone         string
+	PhoneVerified bool
+}
+
+type grant struct {
+	user        *User
+	redirectUri string
+	challenge   string
+	nonce       string
+	expireAt    time.Time
+}
+
+// Server 模拟身份提供方
+type Server struct {
+	cfg *Config
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]*grant
+}
+
+func New(cfg *Config) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{cfg: cfg, key: key, codes: make(map[string]*grant)}, nil
+}
+
+// Handler 返回身份提供方的 HTTP 路由
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	return mux
+}
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]any{
+		"issuer":                                s.cfg.Issuer,
+		"authorization_endpoint":                s.cfg.Issuer + "/authorize",
+		"token_endpoint":                        s.cfg.Issuer + "/token",
+		"jwks_uri":                              s.cfg.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "profile", "email", "phone"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJson(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Mock OIDC 登录</title></head>
+<body>
+<h3>Mock OIDC 登录</h3>
+<form method="post" action="/authorize?{{.Query}}">
+  <p>sub <input name="sub" value="mock-user-1" required></p>
+  <p>name <input name="name" value="Mock User"></p>
+  <p>email <input name="email" value="mock@example.com"> <label><input type="checkbox" name="email_verified" checked> 已验证</label></p>
+  <p>phone <input name="phone" value=""> <label><input type="checkbox" name="phone_verified" checked> 已验证</label></p>
+  <button name="action" value="approve">同意</button>
+  <button name="action" value="deny">拒绝</button>
+</form>
+</body></html>`))
+
+// authorize 授权端点：GET 展示登录表单，携带 login_hint 时直接以该 sub 登录便于脚本联调；POST 提交表单
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectUri := q.Get("redirect_uri")
+	switch {
+	case q.Get("client_id") != s.cfg.ClientId:
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	case redirectUri == "" || (s.cfg.RedirectUrl != "" && redirectUri != s.cfg.RedirectUrl):
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	back, _ := url.Parse(redirectUri)
+	params := back.Query()
+	params.Set("state", q.Get("state"))
+
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		params.Set("error", "invalid_request")
+		back.RawQuery = params.Encode()
+		http.Redirect(w, r, back.String(), http.StatusFound)
+		return
+	}
+
+	var user *User
+	switch {
+	case r.Method == http.MethodPost:
+		if r.PostFormValue("action") == "deny" {
+			params.Set("error", "access_denied")
+			back.RawQuery = params.Encode()
+			http.Redirect(w, r, back.String(), http.StatusFound)
+			return
+		}
+		user = &User{
+			Subject:       r.PostFormValue("sub"),
+			Name:          r.PostFormValue("name"),
+			Email:         r.PostFormValue("email"),
+			EmailVerified: r.PostFormValue("email_verified") != "",
+			Phone:         r.PostFormValue("phone"),
+			PhoneVerified: r.PostFormValue("phone_verified") != "",
+		}
+	case q.Get("login_hint") != "":
+		user = &User{Subject: q.Get("login_hint"), Name: q.Get("login_hint")}
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = loginPage.Execute(w, map[string]string{"Query": r.URL.RawQuery})
+		return
+	}
+	if user.Subject == "" {
+		http.Error(w, "sub required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = &grant{
+		user:        user,
+		redirectUri: redirectUri,
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		expireAt:    time.Now().Add(codeExpire),
+	}
+	s.mu.Unlock()
+
+	params.Set("code", code)
+	back.RawQuery = params.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+// token 令牌端点：校验客户端、授权码、回调地址与 PKCE 后签发 ID 令牌
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	clientId, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientId, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if clientId != s.cfg.ClientId || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.cfg.ClientSecret)) != 1 {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.codes[r.PostFormValue("code")]
+	delete(s.codes, r.PostFormValue("code"))
+	s.mu.Unlock()
+	if !ok || time.Now().After(g.expireAt) || g.redirectUri != r.PostFormValue("redirect_uri") {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.cfg.Issuer,
+		"sub":   g.user.Subject,
+		"aud":   s.cfg.ClientId,
+		"iat":   now.Unix(),
+		"exp":   now.Add(idExpire).Unix(),
+		"nonce": g.nonce,
+		"name":  g.user.Name,
+	}
+	if g.user.Email != "" {
+		claims["email"] = g.user.Email
+		claims["email_verified"] = g.user.EmailVerified
+	}
+	if g.user.Phone != "" {
+		claims["phone_number"] = g.user.Phone
+		claims["phone_number_verified"] = g.user.PhoneVerified
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = keyId
+	idToken, err := t.SignedString(s.key)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(idExpire.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJson(w, status, map[string]string{"error": code})
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,285 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const stateKeyPrefix = "OIDC_STATE_" // OIDC_STATE_<state> -> 登录流程（JSON）
+
+// 接受的 ID 令牌签名算法，不接受 none 和 HS*（客户端密钥不应用于验签）
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384"}
+
+// Config OIDC 登录配置
+type Config struct {
+	StateExpire time.Duration              // 发起登录到回调的最长时间
+	HttpTimeout time.Duration              // 请求身份提供方的超时
+	Providers   map[string]*ProviderConfig // 名称 -> 身份提供方
+}
+
+// ProviderConfig 身份提供方配置
+type ProviderConfig struct {
+	Name         string   // 配置中的名称，用于路由和账号关联
+	DisplayName  string   // 登录页展示的名称
+	Issuer       string   // 签发方，发现文档地址为 <Issuer>/.well-known/openid-configuration
+	ClientId     string   // 在身份提供方注册的客户端
+	ClientSecret string   // 公开客户端可为空，仅依赖 PKCE
+	RedirectUrl  string   // 回调地址，需与身份提供方登记的一致
+	Scopes       []string // 额外申请的 scope，openid 始终包含
+	AutoRegister bool     // 身份未关联且无法自动关联时是否创建新账号
+	TrustPhone   bool     // 是否按 phone_number_verified 的手机号自动关联已有账号
+	TrustEmail   bool     // 是否按 email_verified 的邮箱自动关联已有账号，仅对能保证邮箱归属的企业身份提供方开启
+}
+
+// Flow 一次登录或绑定流程，发起时保存在缓存中，回调时按 state 取回
+type Flow struct {
+	Provider   string `json:"provider"`
+	Verifier   string `json:"verifier"` // PKCE code_verifier
+	Nonce      string `json:"nonce"`
+	Binding    string `json:"binding"`    // 浏览器绑定值的 SHA-256，回调时须带回同一个值，防止登录 CSRF
+	BindUserId int64  `json:"bindUserId"` // 已登录账号发起的绑定流程，0 表示登录流程
+	DeviceId   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	Platform   string `json:"platform"`
+}
+
+// Claims ID 令牌载荷
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce               string   `json:"nonce"`
+	AuthorizedParty     string   `json:"azp,omitempty"`
+	Name                string   `json:"name,omitempty"`
+	PreferredUsername   string   `json:"preferred_username,omitempty"`
+	Picture             string   `json:"picture,omitempty"`
+	Email               string   `json:"email,omitempty"`
+	EmailVerified       flexBool `json:"email_verified,omitempty"`
+	PhoneNumber         string   `json:"phone_number,omitempty"`
+	PhoneNumberVerified flexBool `json:"phone_number_verified,omitempty"`
+}
+
+// flexBool 兼容部分身份提供方以字符串 "true"/"false" 返回的布尔声明
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	*b = flexBool(s == "true")
+	return nil
+}
+
+// Manager OIDC 依赖方（Relying Party）：发起授权码 + PKCE 登录，回调时换取并校验 ID 令牌
+type Manager struct {
+	cache     LibCache.Cache
+	cfg       *Config
+	providers map[string]*provider
+}
+
+func NewManager(cache LibCache.Cache, cfg *Config) *Manager {
+	client := &http.Client{Timeout: cfg.HttpTimeout}
+	m := &Manager{cache: cache, cfg: cfg, providers: make(map[string]*provider, len(cfg.Providers))}
+	for name, pc := range cfg.Providers {
+		pc.Name = name
+		m.providers[name] = &provider{cfg: pc, client: client}
+	}
+	return m
+}
+
+// Providers 已配置的身份提供方，按名称排序
+func (m *Manager) Providers() []*ProviderConfig {
+	list := make([]*ProviderConfig, 0, len(m.providers))
+	for _, p := range m.providers {
+		list = append(list, p.cfg)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Provider 按名称查询身份提供方配置，不存在时返回 nil
+func (m *Manager) Provider(name string) *ProviderConfig {
+	if p, ok := m.providers[name]; ok {
+		return p.cfg
+	}
+	return nil
+}
+
+// Begin 发起登录流程，返回需要重定向到的授权地址、state 和浏览器绑定值
+// 绑定值须由调用方写入发起请求的浏览器的 HttpOnly cookie，流程中只保存其哈希
+func (m *Manager) Begin(ctx context.Context, flow *Flow) (authorizeUrl, state, binding string, err error) {
+	p, ok := m.providers[flow.Provider]
+	if !ok {
+		return "", "", "", libErrors.GrpcError(errs.ErrOidcProviderNotExist, "身份提供方不存在")
+	}
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", "", "", providerError(flow.Provider, err)
+	}
+
+	if state, err = utils.RandomToken(32); err != nil {
+		return "", "", "", internalError("生成 OIDC state", err)
+	}
+	if binding, err = utils.RandomToken(32); err != nil {
+		return "", "", "", internalError("生成 OIDC 浏览器绑定值", err)
+	}
+	flow.Binding = hashBinding(binding)
+	if flow.Nonce, err = utils.RandomToken(16); err != nil {
+		return "", "", "", internalError("生成 OIDC nonce", err)
+	}
+	if flow.Verifier, err = utils.RandomToken(32); err != nil {
+		return "", "", "", internalError("生成 PKCE code_verifier", err)
+	}
+	b, err := json.Marshal(flow)
+	if err != nil {
+		return "", "", "", internalError("序列化 OIDC 登录流程", err)
+	}
+	if err = m.cache.Put(ctx, stateKeyPrefix+state, string(b), m.cfg.StateExpire); err != nil {
+		return "", "", "", internalError("保存 OIDC 登录流程", err)
+	}
+
+	challenge := sha256.Sum256([]byte(flow.Verifier))
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientId)
+	q.Set("redirect_uri", p.cfg.RedirectUrl)
+	q.Set("scope", scope(p.cfg.Scopes))
+	q.Set("state", state)
+	q.Set("nonce", flow.Nonce)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), state, binding, nil
+}
+
+// Finish 处理回调：消费 state，用授权码换取令牌并校验 ID 令牌，返回发起时的流程和身份声明
+// providerName 为回调地址中的身份提供方，与发起时不一致时拒绝，防止混淆攻击（mix-up）
+// binding 为回调请求携带的浏览器绑定值，与发起时不一致说明回调被诱导到了其他浏览器，拒绝以防登录 CSRF
+func (m *Manager) Finish(ctx context.Context, providerName, state, code, binding string) (*Flow, *Claims, error) {
+	flow, err := m.consume(ctx, state)
+	if err != nil {
+		return nil, nil, err
+	}
+	if flow.Provider != providerName {
+		return nil, nil, libErrors.GrpcError(errs.ErrOidcStateInvalid, "登录请求与身份提供方不匹配，请重新发起")
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(hashBinding(binding)), []byte(flow.Binding)) != 1 {
+		return nil, nil, libErrors.GrpcError(errs.ErrOidcStateInvalid, "登录请求不是由当前浏览器发起的，请重新发起")
+	}
+	p, ok := m.providers[flow.Provider]
+	if !ok {
+		return nil, nil, libErrors.GrpcError(errs.ErrOidcProviderNotExist, "身份提供方不存在")
+	}
+
+	tr, err := p.exchange(ctx, code, flow.Verifier)
+	if err != nil {
+		return nil, nil, providerError(flow.Provider, err)
+	}
+	claims, err := m.verify(ctx, p, tr.IdToken, flow.Nonce)
+	if err != nil {
+		return nil, nil, providerError(flow.Provider, err)
+	}
+	return flow, claims, nil
+}
+
+// Cancel 身份提供方回调携带 error 时调用，作废 state
+func (m *Manager) Cancel(ctx context.Context, state string) {
+	_, _ = m.cache.Delete(ctx, stateKeyPrefix+state)
+}
+
+// consume 取出并删除 state 对应的流程，state 只能使用一次
+func (m *Manager) consume(ctx context.Context, state string) (*Flow, error) {
+	key := stateKeyPrefix + state
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return nil, internalError("读取 OIDC 登录流程", err)
+	}
+	if state == "" || val == "" {
+		return nil, libErrors.GrpcError(errs.ErrOidcStateInvalid, "登录请求已过期，请重新发起")
+	}
+	consumed, err := m.cache.Delete(ctx, key)
+	if err != nil {
+		return nil, internalError("删除 OIDC 登录流程", err)
+	}
+	if !consumed {
+		return nil, libErrors.GrpcError(errs.ErrOidcStateInvalid, "登录请求已过期，请重新发起")
+	}
+	flow := &Flow{}
+	if err = json.Unmarshal([]byte(val), flow); err != nil {
+		return nil, internalError("解析 OIDC 登录流程", err)
+	}
+	return flow, nil
+}
+
+// verify 校验 ID 令牌的签名、签发方、受众、有效期与 nonce
+func (m *Manager) verify(ctx context.Context, p *provider, raw, nonce string) (*Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockLeeway),
+	)
+	claims := &Claims{}
+	_, err = parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("id_token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("id_token: missing sub")
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("id_token: nonce mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientId {
+		return nil, fmt.Errorf("id_token: azp mismatch")
+	}
+	return claims, nil
+}
+
+func hashBinding(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func scope(extra []string) string {
+	scopes := []string{"openid"}
+	for _, s := range extra {
+		if s != "openid" {
+			scopes = append(scopes, s)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+func providerError(name string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("身份提供方 %s 认证失败，原因: %v", name, err))
+	return libErrors.GrpcError(errs.ErrOidcProviderFail, "第三方登录失败，请稍后重试")
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,214 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath   = "/.well-known/openid-configuration"
+	metadataTTL     = time.Hour        // 发现文档缓存时长
+	jwksMinRefresh  = time.Minute      // 遇到未知 kid 时重新拉取 JWKS 的最小间隔，避免被伪造的 kid 放大请求
+	maxResponseSize = 1 << 20          // 身份提供方响应体大小上限
+	clockLeeway     = 30 * time.Second // 校验 ID 令牌时间的允许偏差
+)
+
+// metadata 发现文档（OpenID Connect Discovery 1.0）中用到的字段
+type metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JwksUri               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+	SigningAlgs           []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// provider 单个身份提供方，缓存发现文档和验签公钥
+type provider struct {
+	cfg    *ProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	meta      *metadata
+	metaTime  time.Time
+	keys      map[string]crypto.PublicKey
+	keysTime  time.Time
+	keysFetch bool // 是否拉取过 JWKS
+}
+
+// tokenResponse 令牌端点响应
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IdToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// discover 获取发现文档，缓存 metadataTTL，签发方必须与配置一致
+func (p *provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil && time.Since(p.metaTime) < metadataTTL {
+		return p.meta, nil
+	}
+
+	meta := &metadata{}
+	if err := p.getJson(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, meta); err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery: issuer mismatch, expected %q got %q", p.cfg.Issuer, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JwksUri == "" {
+		return nil, errors.New("discovery: missing required endpoints")
+	}
+	p.meta, p.metaTime = meta, time.Now()
+	return meta, nil
+}
+
+// key 按 kid 查找验签公钥，未命中时重新拉取 JWKS 以支持提供方轮换密钥
+func (p *provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	if p.keysFetch && time.Since(p.keysTime) < jwksMinRefresh {
+		return nil, fmt.Errorf("jwks: unknown kid %q", kid)
+	}
+
+	set := &jwkSet{}
+	p.keysFetch, p.keysTime = true, time.Now()
+	if err = p.getJson(ctx, meta.JwksUri, set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for i := range set.Keys {
+		k := &set.Keys[i]
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	p.keys = keys
+
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("jwks: unknown kid %q", kid)
+}
+
+// lookup 令牌未携带 kid 且提供方只有一个密钥时使用该密钥
+func (p *provider) lookup(kid string) (crypto.PublicKey, bool) {
+	if k, ok := p.keys[kid]; ok {
+		return k, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// exchange 使用授权码和 PKCE code_verifier 换取令牌
+func (p *provider) exchange(ctx context.Context, code, verifier string) (*tokenResponse, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectUrl)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.cfg.ClientId)
+	basic := p.useBasicAuth(meta)
+	if !basic && p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basic {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientId), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token: %w", err)
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("token: %w", err)
+	}
+	tr := &tokenResponse{}
+	if err = json.Unmarshal(body, tr); err != nil {
+		return nil, fmt.Errorf("token: status %d, %w", rsp.StatusCode, err)
+	}
+	if rsp.StatusCode != http.StatusOK || tr.Error != "" {
+		return nil, fmt.Errorf("token: status %d, %s %s", rsp.StatusCode, tr.Error, tr.ErrorDescription)
+	}
+	if tr.IdToken == "" {
+		return nil, errors.New("token: response missing id_token")
+	}
+	return tr, nil
+}
+
+// useBasicAuth 提供方声明支持 client_secret_basic 或未声明（规范默认值）时使用 HTTP Basic 认证
+func (p *provider) useBasicAuth(meta *metadata) bool {
+	if p.cfg.ClientSecret == "" {
+		return false
+	}
+	if len(meta.TokenAuthMethods) == 0 {
+		return true
+	}
+	for _, m := range meta.TokenAuthMethods {
+		if m == "client_secret_basic" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *provider) getJson(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", u, rsp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(rsp.Body, maxResponseSize)).Decode(v)
+}
@@ -0,0 +1,55 @@
+package password
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const (
+	failKeyPrefix = "PASSWORD_FAIL_" // PASSWORD_FAIL_<userId> -> 窗口期内密码错误次数
+	lockKeyPrefix = "PASSWORD_LOCK_" // PASSWORD_LOCK_<userId> -> 锁定截止时间（毫秒时间戳）
+)
+
+// LockedUntil 账号的锁定截止时间，未锁定时返回零值
+func (m *Manager) LockedUntil(ctx context.Context, userId int64) (time.Time, error) {
+	v, err := m.cache.Get(ctx, lockKeyPrefix+strconv.FormatInt(userId, 10))
+	if err != nil || v == "" {
+		return time.Time{}, err
+	}
+	until, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(until), nil
+}
+
+// Fail 记录一次密码错误，返回剩余可尝试次数；达到上限时锁定账号并返回 0
+func (m *Manager) Fail(ctx context.Context, userId int64) (int64, error) {
+	id := strconv.FormatInt(userId, 10)
+	n, err := m.cache.IncrExpire(ctx, failKeyPrefix+id, m.cfg.FailWindow)
+	if err != nil {
+		return 0, err
+	}
+	if n < m.cfg.MaxAttempts {
+		return m.cfg.MaxAttempts - n, nil
+	}
+
+	until := time.Now().Add(m.cfg.LockDuration).UnixMilli()
+	if err = m.cache.Put(ctx, lockKeyPrefix+id, strconv.FormatInt(until, 10), m.cfg.LockDuration); err != nil {
+		return 0, err
+	}
+	_, err = m.cache.Delete(ctx, failKeyPrefix+id)
+	return 0, err
+}
+
+// Unlock 清除错误次数和锁定状态，用于登录成功或重置密码后
+func (m *Manager) Unlock(ctx context.Context, userId int64) error {
+	id := strconv.FormatInt(userId, 10)
+	for _, key := range []string{failKeyPrefix + id, lockKeyPrefix + id} {
+		if _, err := m.cache.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+var ErrUnknownHash = errors.New("unknown password hash format") // 无法识别的密码哈希格式
+
+// Config 密码配置
+type Config struct {
+	Algorithm     string // argon2id | bcrypt，新密码使用的哈希算法，旧格式的哈希在登录成功后自动升级
+	Argon2Memory  uint32 // argon2id 内存开销（KiB）
+	Argon2Time    uint32 // argon2id 迭代次数
+	Argon2Threads uint8  // argon2id 并行度
+	BcryptCost    int    // bcrypt 计算开销
+
+	MinLength  int      // 最小长度
+	MaxLength  int      // 最大长度，限制哈希计算开销，bcrypt 最多只使用前 72 字节
+	MinClasses int      // 至少包含的字符类别数（小写字母、大写字母、数字、符号）
+	Blacklist  []string // 禁止使用的常见弱密码，不区分大小写
+
+	MaxAttempts  int64         // 窗口期内允许的最大错误次数，达到后锁定账号
+	FailWindow   time.Duration // 错误次数统计窗口
+	LockDuration time.Duration // 锁定时长
+}
+
+// Manager 负责密码哈希、强度校验以及连续错误后的账号锁定，锁定状态保存在 lib/cache 中
+type Manager struct {
+	cache LibCache.Cache
+	cfg   *Config
+	dummy string // 账号不存在时用于校验的哈希，使响应耗时与账号存在时一致
+}
+
+func NewManager(cache LibCache.Cache, cfg *Config) (*Manager, error) {
+	m := &Manager{cache: cache, cfg: cfg}
+	dummy, err := m.Hash("dummy-password")
+	if err != nil {
+		return nil, err
+	}
+	m.dummy = dummy
+	return m, nil
+}
+
+// Hash 使用配置的算法计算密码哈希，返回 PHC 格式字符串
+func (m *Manager) Hash(password string) (string, error) {
+	if m.cfg.Algorithm == AlgBcrypt {
+		b, err := bcrypt.GenerateFromPassword([]byte(password), m.cfg.BcryptCost)
+		return string(b), err
+	}
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, m.cfg.Argon2Time, m.cfg.Argon2Memory, m.cfg.Argon2Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		m.cfg.Argon2Memory, m.cfg.Argon2Time, m.cfg.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify 校验密码与哈希是否匹配，encoded 为空（账号未设置密码）时仍执行一次校验以避免时间侧信道
+func (m *Manager) Verify(password, encoded string) (bool, error) {
+	if encoded == "" {
+		_, _ = m.Verify(password, m.dummy)
+		return false, nil
+	}
+	if strings.HasPrefix(encoded, "$2") {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	p, err := parseArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1, nil
+}
+
+// NeedsRehash 哈希的算法或参数与当前配置不一致时返回 true
+func (m *Manager) NeedsRehash(encoded string) bool {
+	if m.cfg.Algorithm == AlgBcrypt {
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != m.cfg.BcryptCost
+	}
+	p, err := parseArgon2(encoded)
+	return err != nil || p.memory != m.cfg.Argon2Memory || p.time != m.cfg.Argon2Time || p.threads != m.cfg.Argon2Threads
+}
+
+type argon2Hash struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+// parseArgon2 解析 $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+func parseArgon2(encoded string) (*argon2Hash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgArgon2id {
+		return nil, ErrUnknownHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, ErrUnknownHash
+	}
+	p := &argon2Hash{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, ErrUnknownHash
+	}
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, ErrUnknownHash
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return nil, ErrUnknownHash
+	}
+	return p, nil
+}
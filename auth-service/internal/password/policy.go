@@ -0,0 +1,66 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WeakError 密码不满足强度策略，Reason 可直接返回给用户
+type WeakError struct {
+	Reason string
+}
+
+func (e *WeakError) Error() string {
+	return "weak password: " + e.Reason
+}
+
+// Check 按强度策略校验密码，identities 为账号的用户名、手机号等，密码中不允许包含
+func (m *Manager) Check(password string, identities ...string) error {
+	n := utf8.RuneCountInString(password)
+	if n < m.cfg.MinLength {
+		return &WeakError{Reason: fmt.Sprintf("密码长度不能少于 %d 位", m.cfg.MinLength)}
+	}
+	if m.cfg.MaxLength > 0 && n > m.cfg.MaxLength {
+		return &WeakError{Reason: fmt.Sprintf("密码长度不能超过 %d 位", m.cfg.MaxLength)}
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsSpace(r) || !unicode.IsPrint(r):
+			return &WeakError{Reason: "密码不能包含空白或不可见字符"}
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < m.cfg.MinClasses {
+		return &WeakError{Reason: fmt.Sprintf("密码需包含小写字母、大写字母、数字、符号中的至少 %d 种", m.cfg.MinClasses)}
+	}
+
+	lowered := strings.ToLower(password)
+	for _, weak := range m.cfg.Blacklist {
+		if lowered == strings.ToLower(weak) {
+			return &WeakError{Reason: "密码过于常见，请更换"}
+		}
+	}
+	for _, id := range identities {
+		if id != "" && strings.Contains(lowered, strings.ToLower(id)) {
+			return &WeakError{Reason: "密码不能包含用户名或手机号"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package phone
+
+import (
+	"regexp"
+	"strings"
+)
+
+// region 国家/地区的手机号元数据，pattern 匹配去掉国家代码和长途前缀后的国内有效号码
+type region struct {
+	code        string // ISO 3166-1 alpha-2
+	callingCode string // 国际电话区号
+	trunk       string // 国内长途前缀，国内格式输入时去掉
+	minLen      int    // 国内有效号码最小长度
+	maxLen      int    // 国内有效号码最大长度
+	pattern     *regexp.Regexp
+}
+
+// nanpCanada 北美编号计划中加拿大的区号，其余区号视为美国
+var nanpCanada = "204|226|236|249|250|263|289|306|343|354|365|367|368|382|403|416|418|428|431|437|438|450|468|474|" +
+	"506|514|519|548|579|581|584|587|604|613|639|647|672|683|705|709|742|753|778|780|782|807|819|825|867|873|879|902|905"
+
+// regions 支持的国家/地区，同一区号下按顺序匹配，正则只在包初始化时编译一次
+var regions = []*region{
+	newRegion("CN", "86", "0", 11, 11, `1[3-9]\d{9}`),
+	newRegion("HK", "852", "", 8, 8, `[4-79]\d{7}`),
+	newRegion("MO", "853", "", 8, 8, `6\d{7}`),
+	newRegion("TW", "886", "0", 9, 9, `9\d{8}`),
+	newRegion("CA", "1", "1", 10, 10, `(?:`+nanpCanada+`)[2-9]\d{6}`),
+	newRegion("US", "1", "1", 10, 10, `[2-9]\d{2}[2-9]\d{6}`),
+	newRegion("GB", "44", "0", 10, 10, `7\d{9}`),
+	newRegion("DE", "49", "0", 10, 11, `1[5-7]\d{8,9}`),
+	newRegion("FR", "33", "0", 9, 9, `[67]\d{8}`),
+	newRegion("JP", "81", "0", 10, 10, `[789]0\d{8}`),
+	newRegion("KR", "82", "0", 9, 10, `1\d{8,9}`),
+	newRegion("SG", "65", "", 8, 8, `[89]\d{7}`),
+	newRegion("MY", "60", "0", 9, 10, `1\d{8,9}`),
+	newRegion("AU", "61", "0", 9, 9, `4\d{8}`),
+	newRegion("IN", "91", "0", 10, 10, `[6-9]\d{9}`),
+}
+
+var (
+	regionByCode  = map[string]*region{}   // ISO 代码 -> 元数据
+	regionsByCall = map[string][]*region{} // 国际区号 -> 元数据，北美等多个地区共用一个区号
+)
+
+func init() {
+	for _, r := range regions {
+		regionByCode[r.code] = r
+		regionsByCall[r.callingCode] = append(regionsByCall[r.callingCode], r)
+	}
+}
+
+func newRegion(code, callingCode, trunk string, minLen, maxLen int, pattern string) *region {
+	return &region{
+		code:        code,
+		callingCode: callingCode,
+		trunk:       trunk,
+		minLen:      minLen,
+		maxLen:      maxLen,
+		pattern:     regexp.MustCompile(`^(?:` + pattern + `)$`),
+	}
+}
+
+// Supported 是否支持该国家/地区
+func Supported(code string) bool {
+	_, ok := regionByCode[strings.ToUpper(code)]
+	return ok
+}
@@ -0,0 +1,165 @@
+package phone
+
+import (
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"strings"
+)
+
+// maxCallingCodeLen 国际区号最长 3 位
+const maxCallingCodeLen = 3
+
+// Config 手机号配置
+type Config struct {
+	DefaultRegion  string   // 不带国际区号的号码按该地区解析
+	AllowedRegions []string // 允许注册/登录的国家/地区（ISO 3166-1 alpha-2），为空时允许所有支持的地区
+}
+
+// Number 解析后的手机号
+type Number struct {
+	E164        string // +<国际区号><国内有效号码>，作为缓存和账号的唯一键
+	Region      string // 国家/地区（ISO 3166-1 alpha-2）
+	CallingCode string // 国际区号
+	National    string // 国内有效号码，不含长途前缀
+}
+
+// Error 手机号校验错误，Code 取值见 internal/errors 中的 ErrNoLegalMobile 与 ErrMobile*
+type Error struct {
+	Code   int
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("phone: %s (%d)", e.Reason, e.Code)
+}
+
+// Parser 将用户输入的手机号解析并规范化为 E.164 格式
+type Parser struct {
+	defaultRegion *region
+	allowed       map[string]bool // 为空时不限制
+}
+
+func NewParser(cfg *Config) (*Parser, error) {
+	p := &Parser{defaultRegion: regionByCode[strings.ToUpper(cfg.DefaultRegion)]}
+	if p.defaultRegion == nil {
+		return nil, fmt.Errorf("phone: unsupported default region %q", cfg.DefaultRegion)
+	}
+	for _, code := range cfg.AllowedRegions {
+		code = strings.ToUpper(code)
+		if !Supported(code) {
+			return nil, fmt.Errorf("phone: unsupported region %q", code)
+		}
+		if p.allowed == nil {
+			p.allowed = map[string]bool{}
+		}
+		p.allowed[code] = true
+	}
+	return p, nil
+}
+
+// Parse 解析手机号，支持 +86 138...、0086 138...、以及默认地区的国内格式，允许空格、横线、括号和点作为分隔符
+// 解析失败时返回 *Error
+func (p *Parser) Parse(raw string) (*Number, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*region
+	var nsn string
+	if international {
+		for i := 1; i <= maxCallingCodeLen && i < len(digits); i++ {
+			if rs, ok := regionsByCall[digits[:i]]; ok {
+				candidates, nsn = rs, digits[i:]
+				break
+			}
+		}
+		if candidates == nil {
+			return nil, &Error{Code: errors.ErrMobileCountryCode, Reason: "不支持的国家/地区代码"}
+		}
+	} else {
+		candidates, nsn = regionsByCall[p.defaultRegion.callingCode], digits
+	}
+
+	r, nsn, err := match(candidates, nsn)
+	if err != nil {
+		return nil, err
+	}
+	if p.allowed != nil && !p.allowed[r.code] {
+		return nil, &Error{Code: errors.ErrMobileRegionNotAllowed, Reason: "暂不支持该国家/地区的手机号"}
+	}
+	return &Number{
+		E164:        "+" + r.callingCode + nsn,
+		Region:      r.code,
+		CallingCode: r.callingCode,
+		National:    nsn,
+	}, nil
+}
+
+// Normalize 解析手机号并返回 E.164 格式
+func (p *Parser) Normalize(raw string) (string, error) {
+	n, err := p.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return n.E164, nil
+}
+
+// Valid 手机号是否合法且属于允许的地区
+func (p *Parser) Valid(raw string) bool {
+	_, err := p.Parse(raw)
+	return err == nil
+}
+
+// clean 去掉分隔符，返回纯数字和是否为国际格式（+ 或 00 开头）
+func clean(raw string) (string, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false, &Error{Code: errors.ErrNoLegalMobile, Reason: "手机号不能为空"}
+	}
+
+	var b strings.Builder
+	international := false
+	for i, c := range raw {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == '+' && i == 0:
+			international = true
+		case c == ' ' || c == '-' || c == '(' || c == ')' || c == '.':
+		default:
+			return "", false, &Error{Code: errors.ErrNoLegalMobile, Reason: "手机号只能包含数字"}
+		}
+	}
+
+	digits := b.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		digits, international = digits[2:], true
+	}
+	if digits == "" {
+		return "", false, &Error{Code: errors.ErrNoLegalMobile, Reason: "手机号不能为空"}
+	}
+	return digits, international, nil
+}
+
+// match 按顺序匹配候选地区，号码带有长途前缀（如 +44 07...、国内格式 0 开头）时去掉后再匹配
+func match(candidates []*region, nsn string) (*region, string, error) {
+	lengthOk := false
+	for _, r := range candidates {
+		n := nsn
+		if r.trunk != "" && len(n) > r.maxLen && strings.HasPrefix(n, r.trunk) {
+			n = n[len(r.trunk):]
+		}
+		if len(n) < r.minLen || len(n) > r.maxLen {
+			continue
+		}
+		lengthOk = true
+		if r.pattern.MatchString(n) {
+			return r, n, nil
+		}
+	}
+	if !lengthOk {
+		return nil, "", &Error{Code: errors.ErrMobileLength, Reason: "手机号长度不正确"}
+	}
+	return nil, "", &Error{Code: errors.ErrNoLegalMobile, Reason: "手机号不合法"}
+}
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+var ErrApiKeyNotFound = errors.New("api key not found") // API Key 不存在
+
+// ApiKeyRepository API Key 存储抽象
+type ApiKeyRepository interface {
+	// Find 按前缀查询，不存在时返回 nil, nil
+	Find(ctx context.Context, prefix string) (*data.ApiKey, error)
+	// ListByOwner 查询账号的全部 API Key（含已吊销），按创建时间排序
+	ListByOwner(ctx context.Context, ownerId int64) ([]*data.ApiKey, error)
+	// Create 新增 API Key
+	Create(ctx context.Context, key *data.ApiKey) error
+	// Save 更新 API Key，不存在时返回 ErrApiKeyNotFound
+	Save(ctx context.Context, key *data.ApiKey) error
+	// Touch 记录最近使用时间和 IP
+	Touch(ctx context.Context, prefix string, usedTime int64, ip string) error
+	// DeleteByOwner 删除账号的全部 API Key，用于清除已注销账号的数据
+	DeleteByOwner(ctx context.Context, ownerId int64) error
+}
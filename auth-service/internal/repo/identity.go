@@ -0,0 +1,24 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+var (
+	ErrIdentityExists = errors.New("identity already linked")         // 第三方身份已关联到某个账号
+	ErrProviderLinked = errors.New("provider already linked to user") // 账号已关联该提供方的其他身份
+)
+
+// IdentityRepository 第三方身份关联存储抽象
+type IdentityRepository interface {
+	// Find 按提供方和 sub 查询关联，不存在时返回 nil, nil
+	Find(ctx context.Context, provider, subject string) (*data.Identity, error)
+	// ListByUser 查询账号关联的全部第三方身份
+	ListByUser(ctx context.Context, userId int64) ([]*data.Identity, error)
+	// Create 新增关联，身份已被关联时返回 ErrIdentityExists，账号已关联该提供方时返回 ErrProviderLinked
+	Create(ctx context.Context, identity *data.Identity) error
+	// Delete 解除账号与提供方的关联，返回是否存在该关联
+	Delete(ctx context.Context, userId int64, provider string) (bool, error)
+}
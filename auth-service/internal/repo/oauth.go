@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+var ErrClientNotFound = errors.New("oauth client not found") // OAuth2 客户端不存在
+
+// OAuthRepository OAuth2 客户端与用户授权存储抽象
+type OAuthRepository interface {
+	// FindClient 查询客户端，不存在时返回 nil, nil
+	FindClient(ctx context.Context, clientId string) (*data.OAuthClient, error)
+	// ListClients 查询账号注册的客户端
+	ListClients(ctx context.Context, ownerId int64) ([]*data.OAuthClient, error)
+	// CreateClient 注册客户端
+	CreateClient(ctx context.Context, client *data.OAuthClient) error
+	// SaveClient 更新客户端，不存在时返回 ErrClientNotFound
+	SaveClient(ctx context.Context, client *data.OAuthClient) error
+	// DeleteClient 删除客户端及其全部用户授权，返回被删除授权的 GrantId，用于吊销令牌
+	DeleteClient(ctx context.Context, clientId string) ([]string, error)
+
+	// FindConsent 查询账号对客户端的授权，不存在时返回 nil, nil
+	FindConsent(ctx context.Context, userId int64, clientId string) (*data.OAuthConsent, error)
+	// ListConsents 查询账号的全部授权
+	ListConsents(ctx context.Context, userId int64) ([]*data.OAuthConsent, error)
+	// SaveConsent 新增或覆盖账号对客户端的授权
+	SaveConsent(ctx context.Context, consent *data.OAuthConsent) error
+	// DeleteConsent 删除账号对客户端的授权，返回是否存在该授权
+	DeleteConsent(ctx context.Context, userId int64, clientId string) (bool, error)
+}
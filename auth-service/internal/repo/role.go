@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+var ErrRoleAssigned = errors.New("role already assigned") // 账号在该范围内已拥有该角色
+
+// RoleRepository 账号角色存储抽象
+type RoleRepository interface {
+	// ListByUser 查询账号拥有的全部角色
+	ListByUser(ctx context.Context, userId int64) ([]*data.UserRole, error)
+	// Create 授予角色，账号在该范围内已拥有该角色时返回 ErrRoleAssigned
+	Create(ctx context.Context, role *data.UserRole) error
+	// Delete 收回账号在某个范围内的角色，返回是否存在该角色
+	Delete(ctx context.Context, userId int64, role, scope string) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+// TwoFactorRepository 两步验证设置与恢复码存储抽象，恢复码只保存哈希
+type TwoFactorRepository interface {
+	// Find 查询账号的两步验证设置，未绑定时返回 nil, nil
+	Find(ctx context.Context, userId int64) (*data.TwoFactor, error)
+	// Save 新增或覆盖账号的两步验证设置
+	Save(ctx context.Context, tf *data.TwoFactor) error
+	// Delete 删除账号的两步验证设置及全部恢复码
+	Delete(ctx context.Context, userId int64) error
+	// AdvanceStep 仅当 step 大于已记录的时间步时更新，返回是否更新成功，用于原子地防止动态码重放
+	AdvanceStep(ctx context.Context, userId int64, step int64) (bool, error)
+	// ReplaceRecoveryCodes 用新的一组恢复码替换账号的全部恢复码
+	ReplaceRecoveryCodes(ctx context.Context, userId int64, hashes []string) error
+	// UseRecoveryCode 使用一个未使用过的恢复码，返回是否使用成功
+	UseRecoveryCode(ctx context.Context, userId int64, hash string) (bool, error)
+}
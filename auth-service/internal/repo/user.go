@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+)
+
+var (
+	ErrUserNotFound   = errors.New("user not found")          // 账号不存在或已注销
+	ErrMobileExists   = errors.New("mobile already exists")   // 手机号已被其他账号使用
+	ErrUsernameExists = errors.New("username already exists") // 用户名已被其他账号使用
+	ErrEmailExists    = errors.New("email already exists")    // 邮箱已被其他账号使用
+)
+
+// UserRepository 账号存储抽象
+// 用户名和邮箱不区分大小写；查找方法不返回已注销的账号，已注销账号的手机号、用户名、邮箱在清除前仍被占用
+type UserRepository interface {
+	// FindById 按账号 ID 查找，不存在时返回 nil, nil
+	FindById(ctx context.Context, id int64) (*data.User, error)
+	// FindByMobile 按手机号查找账号，不存在时返回 nil, nil
+	FindByMobile(ctx context.Context, mobile string) (*data.User, error)
+	// FindByUsername 按用户名查找账号，不存在时返回 nil, nil
+	FindByUsername(ctx context.Context, username string) (*data.User, error)
+	// FindByEmail 按邮箱查找账号，不存在时返回 nil, nil
+	FindByEmail(ctx context.Context, email string) (*data.User, error)
+	// ListByOwner 查询账号创建的全部服务账号，按创建时间排序
+	ListByOwner(ctx context.Context, ownerId int64) ([]*data.User, error)
+	// Create 创建账号，成功后回填 user.Id；手机号、用户名或邮箱已被使用时返回对应的 Err*Exists
+	Create(ctx context.Context, user *data.User) error
+	// Save 更新账号的全部字段，账号不存在时返回 ErrUserNotFound
+	Save(ctx context.Context, user *data.User) error
+	// UpdateProfile 更新账号资料，账号不存在时返回 ErrUserNotFound
+	UpdateProfile(ctx context.Context, id int64, profile *data.Profile) error
+	// SoftDelete 将账号标记为已注销，账号不存在时返回 ErrUserNotFound
+	SoftDelete(ctx context.Context, id int64) error
+	// ListPurgeDue 查询计划清除时间不晚于 before 的账号 ID（含已注销账号），最多 limit 个
+	ListPurgeDue(ctx context.Context, before int64, limit int) ([]int64, error)
+	// Purge 物理删除账号，释放手机号、用户名和邮箱，账号不存在时不报错
+	Purge(ctx context.Context, id int64) error
+}
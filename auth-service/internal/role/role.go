@@ -0,0 +1,164 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/rbac"
+	"time"
+)
+
+// Admin 内置的超级管理员角色名，配置中的 admins 账号自动拥有该角色的全局授权
+const Admin = "admin"
+
+// 权限，角色配置中引用
+const (
+	PermAuditRead   = "audit:read"   // 查询审计日志
+	PermRolesManage = "roles:manage" // 分配和收回角色
+)
+
+// Config 角色配置
+type Config struct {
+	Roles  []rbac.Role // 角色定义
+	Admins []int64     // 初始管理员账号，不依赖存储即拥有 admin 角色，用于首次部署时分配其他角色
+}
+
+// Manager 账号角色管理：角色定义来自配置，账号与角色的关系保存在 RoleRepository 中
+type Manager struct {
+	repo   repo.RoleRepository
+	policy *rbac.Policy
+	admins map[int64]bool
+}
+
+// NewManager 校验角色配置并创建角色管理，配置了 admins 时必须定义 admin 角色
+func NewManager(roleRepo repo.RoleRepository, cfg *Config) (*Manager, error) {
+	policy, err := rbac.NewPolicy(cfg.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Admins) > 0 && !policy.HasRole(Admin) {
+		return nil, fmt.Errorf("rbac: admins configured but role %q is not defined", Admin)
+	}
+	admins := make(map[int64]bool, len(cfg.Admins))
+	for _, id := range cfg.Admins {
+		admins[id] = true
+	}
+	return &Manager{repo: roleRepo, policy: policy, admins: admins}, nil
+}
+
+// Policy 角色策略
+func (m *Manager) Policy() *rbac.Policy {
+	return m.policy
+}
+
+// Grants 账号当前拥有的角色，用于写入令牌；已从配置中删除的角色忽略
+func (m *Manager) Grants(ctx context.Context, userId int64) ([]string, error) {
+	list, err := m.repo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	var grants []string
+	if m.admins[userId] {
+		grants = append(grants, Admin)
+	}
+	for _, r := range list {
+		if !m.policy.HasRole(r.Role) || (m.admins[userId] && r.Role == Admin && r.Scope == "") {
+			continue
+		}
+		// 无法解析的范围直接忽略，不能按全局授权处理
+		sc, err := rbac.ParseScope(r.Scope)
+		if err != nil {
+			continue
+		}
+		grants = append(grants, rbac.Grant{Role: r.Role, Scope: sc}.String())
+	}
+	return grants, nil
+}
+
+// List 账号的全部角色
+func (m *Manager) List(ctx context.Context, userId int64) ([]*data.UserRole, error) {
+	list, err := m.repo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, internalError("查询账号角色", err)
+	}
+	return list, nil
+}
+
+// Assign 由操作者为账号授予角色，操作者需在该范围内拥有角色的全部权限，避免越权分配
+func (m *Manager) Assign(ctx context.Context, operator []rbac.Grant, operatorId, userId int64, roleName, scope string) (*data.UserRole, error) {
+	sc, err := m.check(operator, roleName, scope)
+	if err != nil {
+		return nil, err
+	}
+	r := &data.UserRole{
+		UserId:     userId,
+		Role:       roleName,
+		Scope:      sc.String(),
+		GrantedBy:  operatorId,
+		CreateTime: time.Now().UnixMilli(),
+	}
+	err = m.repo.Create(ctx, r)
+	if errors.Is(err, repo.ErrRoleAssigned) {
+		return nil, libErrors.GrpcError(errs.ErrRoleAssigned, "账号已拥有该角色")
+	}
+	if err != nil {
+		return nil, internalError("保存账号角色", err)
+	}
+	return r, nil
+}
+
+// Revoke 由操作者收回账号的角色，权限要求与 Assign 相同
+// 已签发的令牌中仍带有该角色，直到访问令牌过期或刷新
+func (m *Manager) Revoke(ctx context.Context, operator []rbac.Grant, userId int64, roleName, scope string) error {
+	sc, err := m.check(operator, roleName, scope)
+	if err != nil {
+		return err
+	}
+	ok, err := m.repo.Delete(ctx, userId, roleName, sc.String())
+	if err != nil {
+		return internalError("删除账号角色", err)
+	}
+	if !ok {
+		return libErrors.GrpcError(errs.ErrRoleNotAssigned, "账号没有该角色")
+	}
+	return nil
+}
+
+// PurgeUser 清除已注销账号的全部角色
+func (m *Manager) PurgeUser(ctx context.Context, userId int64) error {
+	list, err := m.repo.ListByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, r := range list {
+		if _, err = m.repo.Delete(ctx, userId, r.Role, r.Scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check 校验角色和范围，并检查操作者能否在该范围内授予该角色
+func (m *Manager) check(operator []rbac.Grant, roleName, scope string) (rbac.Scope, error) {
+	if !m.policy.HasRole(roleName) {
+		return rbac.Scope{}, libErrors.GrpcError(errs.ErrRoleNotExist, "角色不存在")
+	}
+	sc, err := rbac.ParseScope(scope)
+	if err != nil {
+		return rbac.Scope{}, libErrors.GrpcError(errs.ErrRoleScopeInvalid, "角色生效范围不合法")
+	}
+	if !m.policy.CanGrant(operator, roleName, sc) {
+		return rbac.Scope{}, libErrors.GrpcError(errs.ErrPermissionDenied, "无权在该范围内分配此角色")
+	}
+	return sc, nil
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
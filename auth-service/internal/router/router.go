@@ -1,14 +1,43 @@
 package router
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/MortalSC/IM-System/auth-service/config"
+	"github.com/MortalSC/IM-System/auth-service/internal/account"
+	"github.com/MortalSC/IM-System/auth-service/internal/apikey"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/captcha"
+	"github.com/MortalSC/IM-System/auth-service/internal/dao"
+	"github.com/MortalSC/IM-System/auth-service/internal/humancheck"
+	"github.com/MortalSC/IM-System/auth-service/internal/mail"
+	"github.com/MortalSC/IM-System/auth-service/internal/oauth"
+	"github.com/MortalSC/IM-System/auth-service/internal/oidc"
+	"github.com/MortalSC/IM-System/auth-service/internal/password"
+	"github.com/MortalSC/IM-System/auth-service/internal/phone"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/role"
+	"github.com/MortalSC/IM-System/auth-service/internal/session"
+	"github.com/MortalSC/IM-System/auth-service/internal/sms"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	"github.com/MortalSC/IM-System/auth-service/internal/totp"
+	"github.com/MortalSC/IM-System/auth-service/internal/userdata"
 	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	gatewayPush "github.com/MortalSC/IM-System/gateway-service/pkg/push"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	groupServiceV1 "github.com/MortalSC/IM-System/group-service/pkg/service/group.service.v1"
 	"github.com/MortalSC/IM-System/lib/cache"
 	"github.com/MortalSC/IM-System/lib/cache/redis"
+	"github.com/MortalSC/IM-System/lib/discovery"
 	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/rbac"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
 	"log"
 	"net"
 )
@@ -52,21 +81,122 @@ type gRPCConfig struct {
 	RegisterFunc func(*grpc.Server)
 }
 
-func RegisterGrpc() *grpc.Server {
+// RegisterGrpc 注册并启动 gRPC 服务，db 为 nil 时账号保存在内存中
+func RegisterGrpc(db *sql.DB) *grpc.Server {
 	// 初始化依赖
 	cacheInstance, err := InitDependencies()
 	if err != nil {
 		log.Fatalf("Failed to initialize dependencies: %v", err)
 	}
 
+	smsDispatcher, err := sms.New(config.Cfg.SmsCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize sms sender: %v", err)
+	}
+	smsDispatcher.OnStatus(logSmsReport)
+
+	mailDispatcher, err := mail.New(config.Cfg.MailCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mail sender: %v", err)
+	}
+	mailDispatcher.OnStatus(logMailReport)
+
+	tokenManager, err := token.NewManager(cacheInstance, config.Cfg.JwtCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize jwt keys: %v", err)
+	}
+
+	passwordManager, err := password.NewManager(cacheInstance, config.Cfg.PasswordCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize password hasher: %v", err)
+	}
+
+	phoneParser, err := phone.NewParser(config.Cfg.PhoneCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize phone parser: %v", err)
+	}
+
+	riskScorer, err := humancheck.NewRuleScorer(cacheInstance, config.Cfg.HumanCfg.Risk)
+	if err != nil {
+		log.Fatalf("Failed to initialize risk scorer: %v", err)
+	}
+
+	var userRepo repo.UserRepository = dao.NewUserMemoryDao()
+	var twoFactorRepo repo.TwoFactorRepository = dao.NewTwoFactorMemoryDao()
+	var identityRepo repo.IdentityRepository = dao.NewIdentityMemoryDao()
+	var oauthRepo repo.OAuthRepository = dao.NewOAuthMemoryDao()
+	var roleRepo repo.RoleRepository = dao.NewRoleMemoryDao()
+	var apiKeyRepo repo.ApiKeyRepository = dao.NewApiKeyMemoryDao()
+	if db != nil {
+		userRepo = dao.NewUserSqlDao(db)
+		twoFactorRepo = dao.NewTwoFactorSqlDao(db)
+		identityRepo = dao.NewIdentitySqlDao(db)
+		oauthRepo = dao.NewOAuthSqlDao(db)
+		roleRepo = dao.NewRoleSqlDao(db)
+		apiKeyRepo = dao.NewApiKeySqlDao(db)
+	}
+
+	// 第一方令牌携带账号当前的角色，每次签发和刷新时重新查询
+	roleManager, err := role.NewManager(roleRepo, config.Cfg.RoleCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize rbac: %v", err)
+	}
+	tokenManager.UseRoles(roleManager.Grants)
+
+	totpManager, err := totp.NewManager(twoFactorRepo, cacheInstance, config.Cfg.TotpCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize totp: %v", err)
+	}
+
+	// 审计日志写入独立文件，由 gRPC 拦截器统一记录认证相关事件
+	auditLog := audit.NewLogger(config.Cfg.AuditCfg)
+
+	// 账号注销后由清除任务依次调用各模块清除数据
+	accountManager := account.NewManager(userRepo, cacheInstance, config.Cfg.AccountCfg)
+	apiKeyManager := apikey.NewManager(apiKeyRepo, userRepo, config.Cfg.ApiKeyCfg)
+	oauthManager := oauth.NewManager(oauthRepo, cacheInstance, tokenManager, config.Cfg.OAuthCfg)
+
+	loginService := loginServiceV1.New(
+		cacheInstance,
+		userRepo,
+		captcha.NewManager(cacheInstance, config.Cfg.CaptchaCfg),
+		smsDispatcher,
+		mailDispatcher,
+		tokenManager,
+		session.NewStore(cacheInstance, config.Cfg.SessionCfg),
+		passwordManager,
+		totpManager,
+		phoneParser,
+		oidc.NewManager(cacheInstance, config.Cfg.OidcCfg),
+		identityRepo,
+		oauthManager,
+		humancheck.NewManager(cacheInstance, config.Cfg.HumanCfg, riskScorer),
+		auditLog,
+		roleManager,
+		apiKeyManager,
+		accountManager,
+	)
+	// 下线设备时通过路由表找到设备所在的网关节点，立即断开长连接
+	loginService.UseGateways(gatewayPush.New(route.NewTable(cacheInstance, config.Cfg.RouteCfg), config.Cfg.GatewayCfg))
+	accountManager.Register(loginService, totpManager, oauthManager, roleManager, apiKeyManager)
+	accountManager.Register(initUserData()...)
+	go accountManager.Run(context.Background())
+	// 管理类方法在注册服务时声明所需权限，由拦截器根据透传的访问令牌检查
+	enforcer := rbac.NewGrpcEnforcer(roleManager.Policy(), loginService.Subject, loginServiceV1.PermissionDenied)
+	loginService.DeclarePermissions(enforcer)
+
 	c := gRPCConfig{
 		Addr: config.Cfg.GC.Addr,
 		RegisterFunc: func(g *grpc.Server) {
-			loginServiceV1.RegisterLoginServiceServer(g, loginServiceV1.New(cacheInstance))
+			loginServiceV1.RegisterLoginServiceServer(g, loginService)
 		},
 	}
 
-	s := grpc.NewServer()
+	// 审计拦截器在外层，权限检查失败同样记录审计事件
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		auditLog.UnaryServerInterceptor(),
+		enforcer.UnaryServerInterceptor(),
+	))
 	c.RegisterFunc(s)
 	lis, err := net.Listen("tcp", c.Addr)
 	if err != nil {
@@ -82,6 +212,35 @@ func RegisterGrpc() *grpc.Server {
 	return s
 }
 
+// initUserData 通过 etcd 发现保存账号数据的其他服务，清除账号时一并清除，未配置的服务跳过
+func initUserData() []account.UserData {
+	cfg := config.Cfg.PurgeCfg
+	var modules []account.UserData
+	resolver.Register(discovery.NewResolver(config.Cfg.EtcdCfg.Addrs, libLog.IMLog))
+	if cfg.GroupService != "" {
+		modules = append(modules, userdata.NewGroup(groupServiceV1.NewGroupServiceClient(dialService(cfg.GroupService))))
+	}
+	if cfg.MessageService != "" {
+		modules = append(modules, userdata.NewMessage(messageServiceV1.NewMessageServiceClient(dialService(cfg.MessageService))))
+	}
+	if cfg.PresenceService != "" {
+		modules = append(modules, userdata.NewPresence(presenceServiceV1.NewPresenceServiceClient(dialService(cfg.PresenceService))))
+	}
+	return modules
+}
+
+// dialService 按服务名连接，轮询选择节点
+func dialService(service string) *grpc.ClientConn {
+	conn, err := grpc.Dial(discovery.BuildResolverUrl(service),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	return conn
+}
+
 // InitDependencies 初始化服务依赖
 func InitDependencies() (cache.Cache, error) {
 	cfg := config.Cfg
@@ -92,3 +251,21 @@ func InitDependencies() (cache.Cache, error) {
 	libLog.IMLog.Debug("Redis initialized successfully")
 	return cacheInstance, nil
 }
+
+// logSmsReport 短信投递状态回调，记录每条短信的最终投递结果
+func logSmsReport(r *sms.Report) {
+	if r.Status == sms.StatusSent {
+		libLog.IMLog.Info(fmt.Sprintf("短信发送成功，手机号: %s，模板: %s，消息ID: %s，尝试次数: %d", r.Mobile, r.Template, r.MsgId, r.Attempts))
+		return
+	}
+	libLog.IMLog.Error(fmt.Sprintf("短信发送失败，手机号: %s，模板: %s，尝试次数: %d，原因: %v", r.Mobile, r.Template, r.Attempts, r.Err))
+}
+
+// logMailReport 邮件投递状态回调，记录每封邮件的最终投递结果
+func logMailReport(r *mail.Report) {
+	if r.Status == mail.StatusSent {
+		libLog.IMLog.Info(fmt.Sprintf("邮件发送成功，邮箱: %s，模板: %s/%s，消息ID: %s，尝试次数: %d", r.To, r.Locale, r.Template, r.MsgId, r.Attempts))
+		return
+	}
+	libLog.IMLog.Error(fmt.Sprintf("邮件发送失败，邮箱: %s，模板: %s/%s，尝试次数: %d，原因: %v", r.To, r.Locale, r.Template, r.Attempts, r.Err))
+}
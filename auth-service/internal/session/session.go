@@ -0,0 +1,185 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	keyPrefix = "SESSIONS_" // SESSIONS_<userId> -> 哈希表，字段为设备 ID，值为会话 JSON
+
+	PlatformUnknown = "unknown"
+)
+
+// Config 会话配置
+type Config struct {
+	Expire        time.Duration     // 会话有效期，与刷新令牌有效期一致
+	TouchInterval time.Duration     // 最近活跃时间的最小刷新间隔，避免每次请求都写缓存
+	DefaultLimit  int               // 未单独配置的平台分组允许同时在线的设备数，<=0 表示不限制
+	Limits        map[string]int    // 平台分组 -> 允许同时在线的设备数，如 mobile: 1、desktop: 1
+	Groups        map[string]string // 平台 -> 平台分组，如 ios/android 归入 mobile，未配置的平台自成一组
+}
+
+// Session 账号在某个设备上的登录会话
+type Session struct {
+	UserId          int64  `json:"userId"`
+	DeviceId        string `json:"deviceId"`
+	DeviceName      string `json:"deviceName"`
+	Platform        string `json:"platform"`
+	Ip              string `json:"ip"`
+	LoginTime       int64  `json:"loginTime"` // 毫秒时间戳
+	LastSeen        int64  `json:"lastSeen"`
+	AccessTokenId   string `json:"accessTokenId"`
+	AccessTokenExp  int64  `json:"accessTokenExp"`
+	RefreshTokenId  string `json:"refreshTokenId"`
+	RefreshTokenExp int64  `json:"refreshTokenExp"`
+}
+
+// Store 基于 lib/cache 的会话存储，同一账号的所有设备会话保存在一个哈希表中
+type Store struct {
+	cache LibCache.Cache
+	cfg   *Config
+}
+
+func NewStore(cache LibCache.Cache, cfg *Config) *Store {
+	return &Store{cache: cache, cfg: cfg}
+}
+
+// NormalizePlatform 统一平台名称的大小写，未上报时记为 unknown
+func NormalizePlatform(platform string) string {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if platform == "" {
+		return PlatformUnknown
+	}
+	return platform
+}
+
+// List 按最近活跃时间倒序返回账号的所有会话，刷新令牌已过期的会话会被清理
+func (s *Store) List(ctx context.Context, userId int64) ([]*Session, error) {
+	key := sessionKey(userId)
+	fields, err := s.cache.HGetAll(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	sessions := make([]*Session, 0, len(fields))
+	for deviceId, v := range fields {
+		sess := &Session{}
+		if err := json.Unmarshal([]byte(v), sess); err != nil || sess.RefreshTokenExp <= now {
+			if _, err := s.cache.HDel(ctx, key, deviceId); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen > sessions[j].LastSeen
+	})
+	return sessions, nil
+}
+
+// Get 查询账号在某个设备上的会话，不存在时返回 nil
+func (s *Store) Get(ctx context.Context, userId int64, deviceId string) (*Session, error) {
+	key := sessionKey(userId)
+	v, err := s.cache.HGet(ctx, key, deviceId)
+	if err != nil || v == "" {
+		return nil, err
+	}
+	sess := &Session{}
+	if err = json.Unmarshal([]byte(v), sess); err != nil || sess.RefreshTokenExp <= time.Now().UnixMilli() {
+		// 与 List 一致，顺手清理损坏或已过期的会话
+		if _, err = s.cache.HDel(ctx, key, deviceId); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return sess, nil
+}
+
+// Save 保存会话，并将账号会话表的过期时间顺延
+func (s *Store) Save(ctx context.Context, sess *Session) error {
+	b, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	key := sessionKey(sess.UserId)
+	if err = s.cache.HSet(ctx, key, sess.DeviceId, string(b)); err != nil {
+		return err
+	}
+	return s.cache.Expire(ctx, key, s.cfg.Expire)
+}
+
+// Remove 删除账号在某个设备上的会话，返回被删除的会话，不存在时返回 nil
+func (s *Store) Remove(ctx context.Context, userId int64, deviceId string) (*Session, error) {
+	sess, err := s.Get(ctx, userId, deviceId)
+	if err != nil || sess == nil {
+		return nil, err
+	}
+	if _, err = s.cache.HDel(ctx, sessionKey(userId), deviceId); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// Touch 刷新会话的最近活跃时间，距上次刷新不足 TouchInterval 时忽略
+func (s *Store) Touch(ctx context.Context, userId int64, deviceId string) error {
+	sess, err := s.Get(ctx, userId, deviceId)
+	if err != nil || sess == nil {
+		return err
+	}
+	now := time.Now()
+	if now.Sub(time.UnixMilli(sess.LastSeen)) < s.cfg.TouchInterval {
+		return nil
+	}
+	sess.LastSeen = now.UnixMilli()
+	return s.Save(ctx, sess)
+}
+
+// Evicted 按并发策略计算新会话登录后需要下线的已有会话：
+// 同一设备上的旧会话总是被替换；同一平台分组的会话数超出上限时，最久未活跃的会话被挤下线
+func (s *Store) Evicted(existing []*Session, incoming *Session) []*Session {
+	var evicted, sameGroup []*Session
+	group := s.group(incoming.Platform)
+	for _, sess := range existing {
+		switch {
+		case sess.DeviceId == incoming.DeviceId:
+			evicted = append(evicted, sess)
+		case s.group(sess.Platform) == group:
+			sameGroup = append(sameGroup, sess)
+		}
+	}
+
+	limit := s.limit(group)
+	if limit <= 0 || len(sameGroup) < limit {
+		return evicted
+	}
+	sort.Slice(sameGroup, func(i, j int) bool {
+		return sameGroup[i].LastSeen < sameGroup[j].LastSeen
+	})
+	// 为新会话腾出一个位置
+	return append(evicted, sameGroup[:len(sameGroup)-limit+1]...)
+}
+
+func (s *Store) group(platform string) string {
+	if g, ok := s.cfg.Groups[platform]; ok {
+		return g
+	}
+	return platform
+}
+
+func (s *Store) limit(group string) int {
+	if l, ok := s.cfg.Limits[group]; ok {
+		return l
+	}
+	return s.cfg.DefaultLimit
+}
+
+func sessionKey(userId int64) string {
+	return keyPrefix + strconv.FormatInt(userId, 10)
+}
@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FileSender 将短信以 JSON 行的形式追加写入本地 spool 文件，供本地开发和集成测试读取
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+// spoolRecord spool 文件中的一行记录
+type spoolRecord struct {
+	MsgId string `json:"msgId"`
+	Time  int64  `json:"time"`
+	*Message
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) Send(ctx context.Context, msg *Message) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	rec := spoolRecord{
+		MsgId:   "file-" + strconv.FormatInt(now.UnixNano(), 10),
+		Time:    now.UnixMilli(),
+		Message: msg,
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+	return rec.MsgId, nil
+}
@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HttpSender 将短信以 JSON 形式 POST 到本地 HTTP sink，sink 可返回 {"msgId": "..."}
+type HttpSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewHttpSender(url string, timeout time.Duration) *HttpSender {
+	return &HttpSender{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HttpSender) Send(ctx context.Context, msg *Message) (string, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return "", fmt.Errorf("sms sink responded %s", rsp.Status)
+	}
+
+	var ack struct {
+		MsgId string `json:"msgId"`
+	}
+	// sink 可以不返回消息 ID，解析失败不视为发送失败
+	if b, err := io.ReadAll(rsp.Body); err == nil && len(b) > 0 {
+		_ = json.Unmarshal(b, &ack)
+	}
+	return ack.MsgId, nil
+}
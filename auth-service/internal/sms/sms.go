@@ -0,0 +1,145 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+const (
+	TemplateCaptcha = "captcha" // 验证码短信模板
+
+	StatusSent   = "SENT"   // 短信平台已受理
+	StatusFailed = "FAILED" // 重试后仍发送失败
+)
+
+// Message 待发送的短信
+type Message struct {
+	Mobile   string            `json:"mobile"`
+	Template string            `json:"template"`
+	Params   map[string]string `json:"params"`
+	Content  string            `json:"content"` // 由模板渲染得到的短信正文
+}
+
+// SmsSender 短信平台抽象，返回平台侧的消息 ID
+type SmsSender interface {
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// Report 短信投递状态报告
+type Report struct {
+	MsgId    string
+	Mobile   string
+	Template string
+	Status   string
+	Attempts int
+	Err      error
+}
+
+// StatusCallback 短信投递状态回调
+type StatusCallback func(report *Report)
+
+// Config 短信配置
+type Config struct {
+	Provider  string            // 短信平台：file（本地 spool 文件）| http（本地 HTTP sink）
+	SpoolFile string            // file 平台的 spool 文件路径
+	HttpSink  string            // http 平台的接收地址
+	Timeout   time.Duration     // 单次发送超时时间
+	Retry     int               // 失败后的最大重试次数
+	Backoff   time.Duration     // 首次重试等待时间，之后按指数递增
+	Templates map[string]string // 模板名 -> text/template 模板内容
+}
+
+// Dispatcher 负责模板渲染、失败重试和投递状态回调
+type Dispatcher struct {
+	sender    SmsSender
+	cfg       *Config
+	templates map[string]*template.Template
+	callback  StatusCallback
+}
+
+// New 按配置创建短信平台及对应的 Dispatcher
+func New(cfg *Config) (*Dispatcher, error) {
+	var sender SmsSender
+	switch cfg.Provider {
+	case "file":
+		sender = NewFileSender(cfg.SpoolFile)
+	case "http":
+		sender = NewHttpSender(cfg.HttpSink, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown sms provider: %q", cfg.Provider)
+	}
+	return NewDispatcher(sender, cfg)
+}
+
+func NewDispatcher(sender SmsSender, cfg *Config) (*Dispatcher, error) {
+	d := &Dispatcher{
+		sender:    sender,
+		cfg:       cfg,
+		templates: make(map[string]*template.Template, len(cfg.Templates)),
+	}
+	for name, text := range cfg.Templates {
+		t, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parse sms template %s: %w", name, err)
+		}
+		d.templates[name] = t
+	}
+	return d, nil
+}
+
+// OnStatus 设置投递状态回调
+func (d *Dispatcher) OnStatus(cb StatusCallback) {
+	d.callback = cb
+}
+
+// SendTemplate 渲染模板并发送短信，失败时按指数退避重试，最终结果通过状态回调通知
+func (d *Dispatcher) SendTemplate(ctx context.Context, mobile, name string, params map[string]string) error {
+	t, ok := d.templates[name]
+	if !ok {
+		return fmt.Errorf("sms template %s not found", name)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, params); err != nil {
+		return fmt.Errorf("render sms template %s: %w", name, err)
+	}
+	msg := &Message{Mobile: mobile, Template: name, Params: params, Content: buf.String()}
+
+	report := &Report{Mobile: mobile, Template: name}
+	backoff := d.cfg.Backoff
+	for report.Attempts = 1; ; report.Attempts++ {
+		report.MsgId, report.Err = d.send(ctx, msg)
+		if report.Err == nil || report.Attempts > d.cfg.Retry {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			report.Err = ctx.Err()
+		case <-time.After(backoff):
+			backoff *= 2
+			continue
+		}
+		break
+	}
+
+	report.Status = StatusSent
+	if report.Err != nil {
+		report.Status = StatusFailed
+	}
+	if d.callback != nil {
+		d.callback(report)
+	}
+	return report.Err
+}
+
+// send 单次发送，带超时控制
+func (d *Dispatcher) send(ctx context.Context, msg *Message) (string, error) {
+	if d.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.cfg.Timeout)
+		defer cancel()
+	}
+	return d.sender.Send(ctx, msg)
+}
@@ -0,0 +1,285 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+	"time"
+)
+
+const (
+	refreshKeyPrefix = "TOKEN_REFRESH_" // TOKEN_REFRESH_<jti> -> 账号 ID，存在即表示刷新令牌仍可使用
+	revokedKeyPrefix = "TOKEN_REVOKED_" // TOKEN_REVOKED_<jti> -> 吊销列表，过期时间与令牌剩余有效期一致
+)
+
+var (
+	ErrTokenRevoked      = errors.New("token revoked")       // 令牌已被吊销
+	ErrRefreshTokenReuse = errors.New("refresh token reuse") // 刷新令牌已被使用过
+)
+
+// Pair 一组访问令牌和刷新令牌
+type Pair struct {
+	AccessToken     string
+	AccessTokenExp  int64 // 毫秒时间戳
+	RefreshToken    string
+	RefreshTokenExp int64
+	AccessTokenId   string
+	RefreshTokenId  string
+}
+
+// Manager 负责令牌签发、轮换刷新、吊销与校验
+// 刷新令牌每次使用后即作废并签发新的一组令牌（轮换），吊销列表保存在 lib/cache 中
+type Manager struct {
+	keys  *jwts.Keys
+	cache LibCache.Cache
+	cfg   *jwts.Config
+	roles RoleLoader
+}
+
+// RoleLoader 查询账号当前拥有的角色，签发第一方令牌时写入令牌
+type RoleLoader func(ctx context.Context, userId int64) ([]string, error)
+
+func NewManager(cache LibCache.Cache, cfg *jwts.Config) (*Manager, error) {
+	keys, err := jwts.LoadKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{keys: keys, cache: cache, cfg: cfg}, nil
+}
+
+// UseRoles 设置角色查询，未设置时令牌不携带角色
+// 每次签发（包括刷新）都重新查询，角色变更在访问令牌过期或刷新后生效
+func (m *Manager) UseRoles(loader RoleLoader) {
+	m.roles = loader
+}
+
+// Keys 当前使用的签名密钥
+func (m *Manager) Keys() *jwts.Keys {
+	return m.keys
+}
+
+// Issuer 令牌签发方
+func (m *Manager) Issuer() string {
+	return m.cfg.Issuer
+}
+
+// Grant 令牌代表的授权，第一方客户端登录只有 UserId 和 DeviceId
+type Grant struct {
+	UserId   int64
+	DeviceId string
+	ClientId string // OAuth2 客户端
+	Scope    string // 空格分隔的授权范围
+	GrantId  string // 授权记录 ID，第一方登录为会话族 ID，见 RevokeGrant
+	Roles    []string
+}
+
+// Issue 为账号的某个设备签发一组新令牌
+// 每次登录生成新的会话族 ID 作为 GrantId，轮换刷新时沿用，刷新令牌被重复使用时整族吊销
+func (m *Manager) Issue(ctx context.Context, userId int64, deviceId string) (*Pair, error) {
+	familyId, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return m.IssueGrant(ctx, &Grant{UserId: userId, DeviceId: deviceId, GrantId: familyId}, true)
+}
+
+// IssueGrant 按授权签发访问令牌，withRefresh 为 true 时同时签发刷新令牌
+// 第一方令牌由 UseRoles 设置的角色查询填充 Roles，第三方应用令牌只受授权范围限制，不携带角色
+func (m *Manager) IssueGrant(ctx context.Context, g *Grant, withRefresh bool) (*Pair, error) {
+	if g.ClientId == "" && g.UserId != 0 && m.roles != nil {
+		roles, err := m.roles(ctx, g.UserId)
+		if err != nil {
+			return nil, err
+		}
+		g.Roles = roles
+	}
+	now := time.Now()
+	access, err := m.newClaims(now, g, jwts.TypeAccess, m.cfg.AccessExpire)
+	if err != nil {
+		return nil, err
+	}
+	pair := &Pair{AccessTokenExp: access.ExpiresAt.UnixMilli(), AccessTokenId: access.ID}
+	if pair.AccessToken, err = m.keys.Sign(access); err != nil {
+		return nil, err
+	}
+	if !withRefresh {
+		return pair, nil
+	}
+
+	refresh, err := m.newClaims(now, g, jwts.TypeRefresh, m.cfg.RefreshExpire)
+	if err != nil {
+		return nil, err
+	}
+	pair.RefreshTokenExp, pair.RefreshTokenId = refresh.ExpiresAt.UnixMilli(), refresh.ID
+	if pair.RefreshToken, err = m.keys.Sign(refresh); err != nil {
+		return nil, err
+	}
+	err = m.cache.Put(ctx, refreshKeyPrefix+refresh.ID, strconv.FormatInt(g.UserId, 10), m.cfg.RefreshExpire)
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh 使用刷新令牌换取新的一组令牌，旧的刷新令牌立即作废，新令牌沿用原令牌的授权
+// clientId 为发起刷新的 OAuth2 客户端，第一方客户端传空，与令牌所属客户端不一致时视为无效令牌
+func (m *Manager) Refresh(ctx context.Context, refreshToken, clientId string) (*jwts.Claims, *Pair, error) {
+	claims, err := m.keys.Parse(refreshToken, jwts.TypeRefresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.ClientId != clientId {
+		return nil, nil, jwts.ErrTokenInvalid
+	}
+	revoked, err := m.IsRevoked(ctx, claims.GrantId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if revoked {
+		return claims, nil, ErrTokenRevoked
+	}
+	// 并发刷新时只有成功删除的一方可以换取新令牌
+	consumed, err := m.cache.Delete(ctx, refreshKeyPrefix+claims.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !consumed {
+		// 已轮换的刷新令牌被再次使用，说明令牌可能已泄露，吊销整个会话族，持有最新令牌的一方也需要重新登录
+		// OAuth2 的 GrantId 是用户授权记录，由撤销授权管理，不在这里吊销
+		if claims.ClientId == "" {
+			if err = m.RevokeGrant(ctx, claims.GrantId); err != nil {
+				return nil, nil, err
+			}
+		}
+		return claims, nil, ErrRefreshTokenReuse
+	}
+	pair, err := m.IssueGrant(ctx, &Grant{
+		UserId:   claims.UserId,
+		DeviceId: claims.DeviceId,
+		ClientId: claims.ClientId,
+		Scope:    claims.Scope,
+		GrantId:  claims.GrantId,
+	}, true)
+	return claims, pair, err
+}
+
+// Revoke 吊销访问令牌或刷新令牌；已过期的令牌无需吊销，视为成功并返回 nil 声明
+func (m *Manager) Revoke(ctx context.Context, token string) (*jwts.Claims, error) {
+	claims, err := m.keys.Parse(token, jwts.TypeAccess)
+	if errors.Is(err, jwts.ErrTokenInvalid) {
+		claims, err = m.keys.Parse(token, jwts.TypeRefresh)
+	}
+	if errors.Is(err, jwts.ErrTokenExpired) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claims, m.RevokeId(ctx, claims.ID, claims.TokenType, claims.ExpiresAt.Time)
+}
+
+// RevokeId 按令牌 ID 吊销，expireAt 为令牌原本的过期时间
+func (m *Manager) RevokeId(ctx context.Context, tokenId, tokenType string, expireAt time.Time) error {
+	if tokenType == jwts.TypeRefresh {
+		if _, err := m.cache.Delete(ctx, refreshKeyPrefix+tokenId); err != nil {
+			return err
+		}
+	}
+	ttl := time.Until(expireAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return m.cache.Put(ctx, revokedKeyPrefix+tokenId, tokenType, ttl)
+}
+
+// Verify 校验访问令牌的签名、有效期以及是否已被吊销
+func (m *Manager) Verify(ctx context.Context, accessToken string) (*jwts.Claims, error) {
+	claims, err := m.keys.Parse(accessToken, jwts.TypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range []string{claims.ID, claims.GrantId} {
+		revoked, err := m.IsRevoked(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+	return claims, nil
+}
+
+// Inspect 校验访问令牌或刷新令牌，刷新令牌还需尚未被使用，用于令牌内省
+func (m *Manager) Inspect(ctx context.Context, token string) (*jwts.Claims, error) {
+	claims, err := m.Verify(ctx, token)
+	if !errors.Is(err, jwts.ErrTokenInvalid) {
+		return claims, err
+	}
+	if claims, err = m.keys.Parse(token, jwts.TypeRefresh); err != nil {
+		return nil, err
+	}
+	v, err := m.cache.Get(ctx, refreshKeyPrefix+claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if v == "" {
+		return nil, ErrTokenRevoked
+	}
+	revoked, err := m.IsRevoked(ctx, claims.GrantId)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// RevokeGrant 撤销授权或会话族，其下已签发的令牌全部失效；令牌最长有效期为刷新令牌有效期，吊销标记保留同样时长即可
+// grantId 为空时（升级前签发的第一方令牌）没有可吊销的会话族，直接返回
+func (m *Manager) RevokeGrant(ctx context.Context, grantId string) error {
+	if grantId == "" {
+		return nil
+	}
+	return m.cache.Put(ctx, revokedKeyPrefix+grantId, "grant", m.cfg.RefreshExpire)
+}
+
+// IsRevoked 令牌或授权是否在吊销列表中，id 为空时返回 false
+func (m *Manager) IsRevoked(ctx context.Context, id string) (bool, error) {
+	if id == "" {
+		return false, nil
+	}
+	v, err := m.cache.Get(ctx, revokedKeyPrefix+id)
+	if err != nil {
+		return false, err
+	}
+	return v != "", nil
+}
+
+func (m *Manager) newClaims(now time.Time, g *Grant, tokenType string, expire time.Duration) (*jwts.Claims, error) {
+	jti, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return &jwts.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   strconv.FormatInt(g.UserId, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+		},
+		UserId:    g.UserId,
+		DeviceId:  g.DeviceId,
+		TokenType: tokenType,
+		ClientId:  g.ClientId,
+		Scope:     g.Scope,
+		GrantId:   g.GrantId,
+		Roles:     g.Roles,
+	}, nil
+}
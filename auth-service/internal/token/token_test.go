@@ -0,0 +1,221 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memCache 测试用的进程内缓存，忽略过期时间
+type memCache struct {
+	mu     sync.Mutex
+	values map[string]string
+	hashes map[string]map[string]string
+}
+
+func newMemCache() *memCache {
+	return &memCache{values: make(map[string]string), hashes: make(map[string]map[string]string)}
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key], nil
+}
+
+func (c *memCache) Put(ctx context.Context, key string, value string, expire time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *memCache) PutNX(ctx context.Context, key string, value string, expire time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	return true, nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.values[key]
+	delete(c.values, key)
+	return ok, nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	return c.IncrExpire(ctx, key, 0)
+}
+
+func (c *memCache) IncrExpire(ctx context.Context, key string, expire time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, _ := strconv.ParseInt(c.values[key], 10, 64)
+	n++
+	c.values[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, expire time.Duration) error {
+	return nil
+}
+
+func (c *memCache) HSet(ctx context.Context, key, field, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hashes[key] == nil {
+		c.hashes[key] = make(map[string]string)
+	}
+	c.hashes[key][field] = value
+	return nil
+}
+
+func (c *memCache) HGet(ctx context.Context, key, field string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hashes[key][field], nil
+}
+
+func (c *memCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	all := make(map[string]string, len(c.hashes[key]))
+	for k, v := range c.hashes[key] {
+		all[k] = v
+	}
+	return all, nil
+}
+
+func (c *memCache) HDel(ctx context.Context, key, field string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.hashes[key][field]
+	delete(c.hashes[key], field)
+	return ok, nil
+}
+
+func (c *memCache) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hashes[key] == nil {
+		c.hashes[key] = make(map[string]string)
+	}
+	n, _ := strconv.ParseInt(c.hashes[key][field], 10, 64)
+	n += incr
+	c.hashes[key][field] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
+func (c *memCache) HIncrByKeys(ctx context.Context, keys []string, field string, incr int64) error {
+	for _, key := range keys {
+		if _, err := c.HIncrBy(ctx, key, field, incr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestManager(t *testing.T) *Manager {
+	m, err := NewManager(newMemCache(), &jwts.Config{
+		Algorithm:     jwts.AlgHS256,
+		Secret:        "test-secret",
+		Issuer:        "im-test",
+		AccessExpire:  time.Minute,
+		RefreshExpire: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m
+}
+
+func TestRefreshRotates(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t)
+	first, err := m.Issue(ctx, 1, "phone")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, second, err := m.Refresh(ctx, first.RefreshToken, "")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if claims.UserId != 1 || claims.DeviceId != "phone" {
+		t.Fatalf("Refresh claims = (%d, %s), want (1, phone)", claims.UserId, claims.DeviceId)
+	}
+	if second.RefreshTokenId == first.RefreshTokenId {
+		t.Fatal("Refresh did not rotate the refresh token")
+	}
+	next, err := m.Verify(ctx, second.AccessToken)
+	if err != nil {
+		t.Fatalf("Verify rotated access token: %v", err)
+	}
+	if next.GrantId == "" || next.GrantId != claims.GrantId {
+		t.Fatalf("rotated token family = %q, want %q", next.GrantId, claims.GrantId)
+	}
+}
+
+// 已轮换的刷新令牌被再次使用时，同一次登录签发的全部令牌失效，其他登录不受影响
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t)
+	first, err := m.Issue(ctx, 1, "phone")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	other, err := m.Issue(ctx, 1, "laptop")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	_, second, err := m.Refresh(ctx, first.RefreshToken, "")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if _, _, err = m.Refresh(ctx, first.RefreshToken, ""); !errors.Is(err, ErrRefreshTokenReuse) {
+		t.Fatalf("reused refresh token: err = %v, want ErrRefreshTokenReuse", err)
+	}
+	for name, access := range map[string]string{"first": first.AccessToken, "rotated": second.AccessToken} {
+		if _, err = m.Verify(ctx, access); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("Verify %s access token: err = %v, want ErrTokenRevoked", name, err)
+		}
+	}
+	if _, _, err = m.Refresh(ctx, second.RefreshToken, ""); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("Refresh rotated token: err = %v, want ErrTokenRevoked", err)
+	}
+
+	if _, err = m.Verify(ctx, other.AccessToken); err != nil {
+		t.Fatalf("Verify other session: %v", err)
+	}
+	if _, _, err = m.Refresh(ctx, other.RefreshToken, ""); err != nil {
+		t.Fatalf("Refresh other session: %v", err)
+	}
+}
+
+// OAuth2 的 GrantId 是用户授权记录，刷新令牌重复使用时只拒绝本次请求
+func TestRefreshReuseKeepsOAuthGrant(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t)
+	pair, err := m.IssueGrant(ctx, &Grant{UserId: 1, ClientId: "app", Scope: "profile", GrantId: "grant"}, true)
+	if err != nil {
+		t.Fatalf("IssueGrant: %v", err)
+	}
+	if _, _, err = m.Refresh(ctx, pair.RefreshToken, "app"); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, _, err = m.Refresh(ctx, pair.RefreshToken, "app"); !errors.Is(err, ErrRefreshTokenReuse) {
+		t.Fatalf("reused refresh token: err = %v, want ErrRefreshTokenReuse", err)
+	}
+	if revoked, _ := m.IsRevoked(ctx, "grant"); revoked {
+		t.Fatal("refresh token reuse revoked the OAuth2 grant")
+	}
+}
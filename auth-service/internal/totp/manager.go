@@ -0,0 +1,345 @@
+package totp
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strings"
+	"time"
+)
+
+const (
+	challengeKeyPrefix     = "TOTP_CHALLENGE_"      // TOTP_CHALLENGE_<token> -> 待完成两步验证的登录 JSON
+	challengeFailKeyPrefix = "TOTP_CHALLENGE_FAIL_" // TOTP_CHALLENGE_FAIL_<token> -> 校验失败次数
+
+	encryptedPrefix = "enc:" // 加密保存的密钥前缀
+)
+
+// Config 两步验证配置
+type Config struct {
+	Issuer            string        // 验证器中显示的服务名称
+	SecretKey         string        // 加密保存 TOTP 密钥的 AES-256 密钥（Base64），仅开发模式下允许为空，此时明文保存
+	DevMode           bool          // 开发模式，允许不配置 SecretKey
+	Skew              int           // 允许的时钟偏差（时间步）
+	RecoveryCodes     int           // 启用时生成的恢复码数量
+	ChallengeExpire   time.Duration // 登录挑战令牌有效期
+	ChallengeAttempts int64         // 单个挑战令牌允许的最大错误次数
+}
+
+// Challenge 已通过第一步认证、等待校验动态码的登录
+type Challenge struct {
+	UserId     int64  `json:"userId"`
+	DeviceId   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	Platform   string `json:"platform"`
+	Ip         string `json:"ip"`
+}
+
+// Manager 负责 TOTP 绑定、校验、恢复码以及登录挑战令牌
+// 绑定信息和恢复码保存在 TwoFactorRepository 中，挑战令牌保存在 lib/cache 中
+type Manager struct {
+	repo  repo.TwoFactorRepository
+	cache LibCache.Cache
+	cfg   *Config
+	aead  cipher.AEAD
+}
+
+func NewManager(tfRepo repo.TwoFactorRepository, cache LibCache.Cache, cfg *Config) (*Manager, error) {
+	m := &Manager{repo: tfRepo, cache: cache, cfg: cfg}
+	if cfg.SecretKey == "" {
+		if !cfg.DevMode {
+			return nil, errors.New("totp secret key is required unless dev mode is enabled")
+		}
+		return m, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode totp secret key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, errors.New("totp secret key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if m.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Enabled 账号是否已启用两步验证
+func (m *Manager) Enabled(ctx context.Context, userId int64) (bool, error) {
+	tf, err := m.repo.Find(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return tf != nil && tf.Enabled, nil
+}
+
+// Enroll 为账号生成新的 TOTP 密钥，需调用 Confirm 校验一次动态码后才启用
+// 未启用前重复调用会覆盖之前生成的密钥；account 为验证器中显示的账号名
+func (m *Manager) Enroll(ctx context.Context, userId int64, account string) (string, string, error) {
+	tf, err := m.repo.Find(ctx, userId)
+	if err != nil {
+		return "", "", internalError("查询两步验证设置", err)
+	}
+	if tf != nil && tf.Enabled {
+		return "", "", libErrors.GrpcError(errs.ErrTotpAlreadyEnabled, "已启用两步验证，如需更换请先关闭")
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return "", "", internalError("生成 TOTP 密钥", err)
+	}
+	sealed, err := m.seal(secret)
+	if err != nil {
+		return "", "", internalError("加密 TOTP 密钥", err)
+	}
+	err = m.repo.Save(ctx, &data.TwoFactor{UserId: userId, Secret: sealed, CreateTime: time.Now().UnixMilli()})
+	if err != nil {
+		return "", "", internalError("保存两步验证设置", err)
+	}
+	return secret, URI(m.cfg.Issuer, account, secret), nil
+}
+
+// Confirm 校验动态码后启用两步验证，返回恢复码明文，恢复码只在此时展示一次
+func (m *Manager) Confirm(ctx context.Context, userId int64, code string) ([]string, error) {
+	tf, err := m.repo.Find(ctx, userId)
+	if err != nil {
+		return nil, internalError("查询两步验证设置", err)
+	}
+	if tf == nil {
+		return nil, libErrors.GrpcError(errs.ErrTotpNotEnrolled, "请先绑定验证器")
+	}
+	if tf.Enabled {
+		return nil, libErrors.GrpcError(errs.ErrTotpAlreadyEnabled, "已启用两步验证")
+	}
+	if err = m.verifyTotp(ctx, tf, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := m.newRecoveryCodes()
+	if err != nil {
+		return nil, internalError("生成恢复码", err)
+	}
+	if err = m.repo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, internalError("保存恢复码", err)
+	}
+	tf, err = m.repo.Find(ctx, userId)
+	if err != nil || tf == nil {
+		return nil, internalError("查询两步验证设置", err)
+	}
+	tf.Enabled = true
+	tf.EnableTime = time.Now().UnixMilli()
+	if err = m.repo.Save(ctx, tf); err != nil {
+		return nil, internalError("启用两步验证", err)
+	}
+	return codes, nil
+}
+
+// Disable 校验动态码或恢复码后关闭两步验证，并删除密钥和恢复码
+func (m *Manager) Disable(ctx context.Context, userId int64, code string) error {
+	if err := m.Verify(ctx, userId, code); err != nil {
+		return err
+	}
+	if err := m.repo.Delete(ctx, userId); err != nil {
+		return internalError("删除两步验证设置", err)
+	}
+	return nil
+}
+
+// Verify 校验已启用两步验证账号的动态码或恢复码，恢复码使用后作废
+func (m *Manager) Verify(ctx context.Context, userId int64, code string) error {
+	tf, err := m.repo.Find(ctx, userId)
+	if err != nil {
+		return internalError("查询两步验证设置", err)
+	}
+	if tf == nil || !tf.Enabled {
+		return libErrors.GrpcError(errs.ErrTotpNotEnabled, "未启用两步验证")
+	}
+
+	code = normalizeCode(code)
+	if len(code) == digits {
+		return m.verifyTotp(ctx, tf, code)
+	}
+	ok, err := m.repo.UseRecoveryCode(ctx, userId, hashRecoveryCode(code))
+	if err != nil {
+		return internalError("使用恢复码", err)
+	}
+	if !ok {
+		return libErrors.GrpcError(errs.ErrTotpCodeError, "验证码错误")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 使用恢复码完成两步验证", userId))
+	return nil
+}
+
+// PurgeUser 清除已注销账号的两步验证设置和恢复码
+func (m *Manager) PurgeUser(ctx context.Context, userId int64) error {
+	return m.repo.Delete(ctx, userId)
+}
+
+// NewChallenge 为已通过第一步认证的登录创建挑战令牌，返回令牌及其过期时间（毫秒时间戳）
+func (m *Manager) NewChallenge(ctx context.Context, c *Challenge) (string, int64, error) {
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return "", 0, err
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", 0, err
+	}
+	if err = m.cache.Put(ctx, challengeKeyPrefix+token, string(b), m.cfg.ChallengeExpire); err != nil {
+		return "", 0, err
+	}
+	return token, time.Now().Add(m.cfg.ChallengeExpire).UnixMilli(), nil
+}
+
+// VerifyChallenge 校验挑战令牌对应账号的动态码或恢复码，通过后令牌作废并返回挑战内容
+// 错误次数达到上限后令牌作废，需要重新登录
+func (m *Manager) VerifyChallenge(ctx context.Context, token, code string) (*Challenge, error) {
+	key := challengeKeyPrefix + token
+	val, err := m.cache.Get(ctx, key)
+	if err != nil {
+		return nil, internalError("读取挑战令牌", err)
+	}
+	if token == "" || val == "" {
+		return nil, libErrors.GrpcError(errs.ErrChallengeInvalid, "两步验证已过期，请重新登录")
+	}
+	c := &Challenge{}
+	if err = json.Unmarshal([]byte(val), c); err != nil {
+		return nil, internalError("解析挑战令牌", err)
+	}
+
+	if err = m.Verify(ctx, c.UserId, code); err != nil {
+		if errCode, _ := libErrors.ParseGrpcError(err); errCode != errs.ErrTotpCodeError {
+			return nil, err
+		}
+		return nil, m.failChallenge(ctx, token, err)
+	}
+
+	// 并发请求中只有成功删除的一方完成登录
+	consumed, err := m.cache.Delete(ctx, key)
+	if err != nil {
+		return nil, internalError("删除挑战令牌", err)
+	}
+	if !consumed {
+		return nil, libErrors.GrpcError(errs.ErrChallengeInvalid, "两步验证已过期，请重新登录")
+	}
+	_, _ = m.cache.Delete(ctx, challengeFailKeyPrefix+token)
+	return c, nil
+}
+
+// failChallenge 记录挑战令牌的一次错误，达到上限时作废令牌
+func (m *Manager) failChallenge(ctx context.Context, token string, cause error) error {
+	failKey := challengeFailKeyPrefix + token
+	n, err := m.cache.IncrExpire(ctx, failKey, m.cfg.ChallengeExpire)
+	if err != nil {
+		return internalError("记录两步验证错误次数", err)
+	}
+	if n < m.cfg.ChallengeAttempts {
+		return cause
+	}
+	for _, key := range []string{challengeKeyPrefix + token, failKey} {
+		_, _ = m.cache.Delete(ctx, key)
+	}
+	return libErrors.GrpcError(errs.ErrChallengeAttemptsExceeded, "验证码错误次数过多，请重新登录")
+}
+
+// verifyTotp 校验动态码并原子地记录时间步，同一个动态码只能使用一次
+func (m *Manager) verifyTotp(ctx context.Context, tf *data.TwoFactor, code string) error {
+	secret, err := m.open(tf.Secret)
+	if err != nil {
+		return internalError("解密 TOTP 密钥", err)
+	}
+	step, ok := Validate(secret, normalizeCode(code), time.Now(), m.cfg.Skew, tf.LastStep)
+	if !ok {
+		return libErrors.GrpcError(errs.ErrTotpCodeError, "验证码错误")
+	}
+	advanced, err := m.repo.AdvanceStep(ctx, tf.UserId, step)
+	if err != nil {
+		return internalError("记录 TOTP 时间步", err)
+	}
+	if !advanced {
+		return libErrors.GrpcError(errs.ErrTotpCodeError, "验证码已使用，请等待下一个验证码")
+	}
+	return nil
+}
+
+// newRecoveryCodes 生成一组恢复码，格式为 xxxxx-xxxxx（小写 Base32），返回明文和哈希
+func (m *Manager) newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, m.cfg.RecoveryCodes)
+	hashes := make([]string, 0, m.cfg.RecoveryCodes)
+	for i := 0; i < m.cfg.RecoveryCodes; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		s := strings.ToLower(b32.EncodeToString(b))[:10]
+		codes = append(codes, s[:5]+"-"+s[5:])
+		hashes = append(hashes, hashRecoveryCode(s))
+	}
+	return codes, hashes, nil
+}
+
+// seal 加密 TOTP 密钥，未配置加密密钥时原样返回
+func (m *Manager) seal(secret string) (string, error) {
+	if m.aead == nil {
+		return secret, nil
+	}
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := m.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (m *Manager) open(stored string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	if m.aead == nil {
+		return "", errors.New("totp secret is encrypted but no secret key is configured")
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	n := m.aead.NonceSize()
+	if len(b) < n {
+		return "", errors.New("totp secret ciphertext too short")
+	}
+	plain, err := m.aead.Open(nil, b[:n], b[n:], nil)
+	return string(plain), err
+}
+
+// normalizeCode 去掉用户输入中的空格和连字符并转为小写
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func internalError(action string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("%s出错，原因: %v", action, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
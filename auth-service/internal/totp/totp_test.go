@@ -0,0 +1,162 @@
+package totp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/MortalSC/IM-System/auth-service/internal/dao"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	libLog.IMLog = zap.NewNop()
+}
+
+// rfcSecret RFC 6238 附录 B 中 SHA1 测试向量的密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 附录 B 给出的是 8 位动态码，6 位动态码取其后 6 位
+func TestCodeRFC6238Vectors(t *testing.T) {
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, v := range vectors {
+		step := Step(time.Unix(v.unix, 0))
+		got, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code(%d): %v", v.unix, err)
+		}
+		if want := v.code[len(v.code)-digits:]; got != want {
+			t.Errorf("Code at %d = %s, want %s", v.unix, got, want)
+		}
+		// 小写密钥同样可以解码
+		if lower, _ := Code(strings.ToLower(rfcSecret), step); lower != got {
+			t.Errorf("Code with lowercase secret at %d = %s, want %s", v.unix, lower, got)
+		}
+	}
+}
+
+func TestValidateSkewAndLastStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	prev, _ := Code(rfcSecret, current-1)
+
+	if step, ok := Validate(rfcSecret, prev, now, 1, 0); !ok || step != current-1 {
+		t.Fatalf("Validate previous step = (%d, %v), want (%d, true)", step, ok, current-1)
+	}
+	if _, ok := Validate(rfcSecret, prev, now, 0, 0); ok {
+		t.Fatal("Validate accepted previous step without skew")
+	}
+	if _, ok := Validate(rfcSecret, prev, now, 1, current-1); ok {
+		t.Fatal("Validate accepted a step not after lastStep")
+	}
+	if _, ok := Validate(rfcSecret, prev[1:], now, 1, 0); ok {
+		t.Fatal("Validate accepted a code with wrong length")
+	}
+}
+
+func newTestManager(t *testing.T) (*Manager, *dao.TwoFactorMemoryDao) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	tfRepo := dao.NewTwoFactorMemoryDao()
+	m, err := NewManager(tfRepo, nil, &Config{
+		Issuer:        "IM-System",
+		SecretKey:     base64.StdEncoding.EncodeToString(key),
+		Skew:          1,
+		RecoveryCodes: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return m, tfRepo
+}
+
+func assertCode(t *testing.T, err error, want int) {
+	t.Helper()
+	if code, msg := libErrors.ParseGrpcError(err); code != want {
+		t.Fatalf("error code = %d (%s), want %d", code, msg, want)
+	}
+}
+
+// 同一个动态码只能使用一次：重复提交时 Validate 会跳过已记录的时间步；
+// 并发请求都通过 Validate 时，只有一方能通过 AdvanceStep 推进时间步
+func TestVerifyRejectsReplay(t *testing.T) {
+	ctx := context.Background()
+	m, tfRepo := newTestManager(t)
+	const userId = 1
+	secret, _, err := m.Enroll(ctx, userId, "alice")
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	code, _ := Code(secret, Step(time.Now()))
+	if _, err = m.Confirm(ctx, userId, code); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	assertCode(t, m.Verify(ctx, userId, code), errs.ErrTotpCodeError)
+
+	// 模拟并发：读取时间步记录之前的绑定信息，再用新的动态码各校验一次
+	stale, _ := tfRepo.Find(ctx, userId)
+	next, _ := Code(secret, stale.LastStep+1)
+	if err = m.verifyTotp(ctx, stale, next); err != nil {
+		t.Fatalf("verifyTotp: %v", err)
+	}
+	assertCode(t, m.verifyTotp(ctx, stale, next), errs.ErrTotpCodeError)
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	m, _ := newTestManager(t)
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	sealed, err := m.seal(secret)
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, secret) {
+		t.Fatalf("sealed secret %q is not encrypted", sealed)
+	}
+	opened, err := m.open(sealed)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if opened != secret {
+		t.Fatalf("open = %q, want %q", opened, secret)
+	}
+
+	// 其他密钥无法解密
+	other, _ := newTestManager(t)
+	if _, err = other.open(sealed); err == nil {
+		t.Fatal("open with another key succeeded")
+	}
+}
+
+func TestNewManagerRequiresSecretKey(t *testing.T) {
+	repo := dao.NewTwoFactorMemoryDao()
+	if _, err := NewManager(repo, nil, &Config{}); err == nil {
+		t.Fatal("NewManager accepted an empty secret key outside dev mode")
+	}
+	m, err := NewManager(repo, nil, &Config{DevMode: true})
+	if err != nil {
+		t.Fatalf("NewManager in dev mode: %v", err)
+	}
+	if sealed, _ := m.seal("SECRET"); sealed != "SECRET" {
+		t.Fatalf("seal in dev mode = %q, want plaintext", sealed)
+	}
+}
@@ -0,0 +1,24 @@
+package userdata
+
+import (
+	"encoding/json"
+)
+
+// exportPages 从空 cursor 开始逐页调用 page，直到返回的 cursor 为空，合并各页的记录
+func exportPages(page func(cursor string) ([]string, string, error)) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	cursor := ""
+	for {
+		list, next, err := page(cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range list {
+			records = append(records, json.RawMessage(r))
+		}
+		if next == "" {
+			return records, nil
+		}
+		cursor = next
+	}
+}
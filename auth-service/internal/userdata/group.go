@@ -0,0 +1,38 @@
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	groupServiceV1 "github.com/MortalSC/IM-System/group-service/pkg/service/group.service.v1"
+)
+
+// Group 清除 group-service 中已注销账号的群成员身份
+type Group struct {
+	client groupServiceV1.GroupServiceClient
+}
+
+func NewGroup(client groupServiceV1.GroupServiceClient) *Group {
+	return &Group{client: client}
+}
+
+// PurgeUser 作为群主的群组转让给其他成员或解散，然后退出全部群组
+func (g *Group) PurgeUser(ctx context.Context, userId int64) error {
+	_, err := g.client.PurgeUser(ctx, &groupServiceV1.PurgeUserMessage{UserId: userId})
+	return err
+}
+
+// Service 导出数据中该服务的名称
+func (g *Group) Service() string {
+	return "group"
+}
+
+// ExportUser 导出加入的群组和在群内的角色
+func (g *Group) ExportUser(ctx context.Context, userId int64) ([]json.RawMessage, error) {
+	return exportPages(func(cursor string) ([]string, string, error) {
+		rsp, err := g.client.ExportUser(ctx, &groupServiceV1.ExportUserMessage{UserId: userId, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return rsp.Records, rsp.Cursor, nil
+	})
+}
@@ -0,0 +1,38 @@
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+)
+
+// Message 清除 message-service 中已注销账号的消息数据
+type Message struct {
+	client messageServiceV1.MessageServiceClient
+}
+
+func NewMessage(client messageServiceV1.MessageServiceClient) *Message {
+	return &Message{client: client}
+}
+
+// PurgeUser 删除用户发送的消息以及用户的收件箱、同步位置、已读位置和未读数
+func (m *Message) PurgeUser(ctx context.Context, userId int64) error {
+	_, err := m.client.PurgeUser(ctx, &messageServiceV1.PurgeUserMessage{UserId: userId})
+	return err
+}
+
+// Service 导出数据中该服务的名称
+func (m *Message) Service() string {
+	return "message"
+}
+
+// ExportUser 导出各会话中的位置和发送的消息
+func (m *Message) ExportUser(ctx context.Context, userId int64) ([]json.RawMessage, error) {
+	return exportPages(func(cursor string) ([]string, string, error) {
+		rsp, err := m.client.ExportUser(ctx, &messageServiceV1.ExportUserMessage{UserId: userId, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return rsp.Records, rsp.Cursor, nil
+	})
+}
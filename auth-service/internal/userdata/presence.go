@@ -0,0 +1,38 @@
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
+)
+
+// Presence 清除 presence-service 中已注销账号的在线状态数据
+type Presence struct {
+	client presenceServiceV1.PresenceServiceClient
+}
+
+func NewPresence(client presenceServiceV1.PresenceServiceClient) *Presence {
+	return &Presence{client: client}
+}
+
+// PurgeUser 删除用户订阅的和订阅了用户的订阅关系、隐私设置、设备状态和最后在线时间
+func (p *Presence) PurgeUser(ctx context.Context, userId int64) error {
+	_, err := p.client.PurgeUser(ctx, &presenceServiceV1.PurgeUserMessage{UserId: userId})
+	return err
+}
+
+// Service 导出数据中该服务的名称
+func (p *Presence) Service() string {
+	return "presence"
+}
+
+// ExportUser 导出隐私设置和订阅的用户
+func (p *Presence) ExportUser(ctx context.Context, userId int64) ([]json.RawMessage, error) {
+	return exportPages(func(cursor string) ([]string, string, error) {
+		rsp, err := p.client.ExportUser(ctx, &presenceServiceV1.ExportUserMessage{UserId: userId, Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return rsp.Records, rsp.Cursor, nil
+	})
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+)
+
+// RandomToken 生成 n 字节长度的随机令牌（十六进制编码）
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RandomDigits 生成 n 位随机数字串，首位不为 0（如 4 位为 1000~9999）
+func RandomDigits(n int) (string, error) {
+	min := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n-1)), nil)
+	span := new(big.Int).Sub(new(big.Int).Mul(min, big.NewInt(10)), min)
+	v, err := rand.Int(rand.Reader, span)
+	if err != nil {
+		return "", err
+	}
+	return v.Add(v, min).String(), nil
+}
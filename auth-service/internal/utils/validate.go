@@ -1,13 +1,23 @@
 package utils
 
-import "regexp"
+import (
+	"net/mail"
+	"regexp"
+)
 
-// VerifyMobile 验证手机合法性
-func VerifyMobile(mobile string) bool {
-	if mobile == "" {
+// usernameReg 用户名：字母开头，4~20 位字母、数字或下划线
+var usernameReg = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{3,19}$`)
+
+// VerifyUsername 验证用户名合法性
+func VerifyUsername(username string) bool {
+	return usernameReg.MatchString(username)
+}
+
+// VerifyEmail 验证邮箱合法性，只接受不带显示名的纯地址
+func VerifyEmail(email string) bool {
+	if email == "" || len(email) > 254 {
 		return false
 	}
-	regular := "^((13[0-9])|(14[5,7])|(15[0-3,5-9])|(17[0,3,5-8])|(18[0-9])|166|198|199|(147))\\d{8}$"
-	reg := regexp.MustCompile(regular)
-	return reg.MatchString(mobile)
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
 }
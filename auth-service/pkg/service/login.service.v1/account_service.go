@@ -0,0 +1,298 @@
+package login_service_v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"time"
+)
+
+// ChangeMobile 更换绑定的手机号，需同时校验当前手机号和新手机号收到的验证码；账号未绑定手机号时只校验新手机号
+func (ls *LoginService) ChangeMobile(ctx context.Context, msg *ChangeMobileMessage) (*ChangeMobileResponse, error) {
+	// 1. 校验参数
+	number, err := ls.parseMobile(msg.Mobile)
+	if err != nil {
+		return nil, err
+	}
+	mobile := number.E164
+	user, err := ls.activeUser(ctx, "ChangeMobile", msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user.Mobile == mobile {
+		return nil, libErrors.GrpcError(errs.ErrMobileUnchanged, "新手机号与当前手机号相同")
+	}
+
+	// 2. 先检查新手机号是否可用，避免验证码被白白消费
+	existing, err := ls.userRepo.FindByMobile(ctx, mobile)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ChangeMobile 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if existing != nil {
+		return nil, libErrors.GrpcError(errs.ErrMobileExists, "手机号已注册")
+	}
+
+	// 3. 依次校验并消费当前手机号和新手机号的验证码，分别证明账号归属和新手机号归属
+	if user.Mobile != "" {
+		if err = ls.captcha.Verify(ctx, user.Mobile, msg.OldCaptcha); err != nil {
+			return nil, err
+		}
+	}
+	if err = ls.captcha.Verify(ctx, mobile, msg.Captcha); err != nil {
+		return nil, err
+	}
+
+	// 4. 保存手机号，并发绑定同一号码时由存储层的唯一性保证
+	old := user.Mobile
+	user.Mobile = mobile
+	switch err = ls.userRepo.Save(ctx, user); {
+	case errors.Is(err, repo.ErrMobileExists):
+		return nil, libErrors.GrpcError(errs.ErrMobileExists, "手机号已注册")
+	case err != nil:
+		libLog.IMLog.Error(fmt.Sprintf("ChangeMobile 更新账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 更换手机号 %s -> %s", user.Id, old, mobile))
+	return &ChangeMobileResponse{Member: toMemberMessage(user)}, nil
+}
+
+// RequestAccountDeletion 申请注销账号：通过验证码确认身份后下线全部设备、撤销第三方应用授权，进入冷静期
+// 冷静期内账号的 API Key 不可用，可重新登录并撤销注销；冷静期结束后由清除任务异步清除账号数据
+func (ls *LoginService) RequestAccountDeletion(ctx context.Context, msg *RequestAccountDeletionMessage) (*RequestAccountDeletionResponse, error) {
+	user, err := ls.activeUser(ctx, "RequestAccountDeletion", msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletionPending() {
+		return nil, libErrors.GrpcError(errs.ErrDeletionPending, "账号已申请注销")
+	}
+
+	// 1. 验证码发送到账号绑定的手机号，未绑定时使用已验证的邮箱
+	target := user.Mobile
+	if target == "" && user.EmailVerified {
+		target = user.Email
+	}
+	if target == "" {
+		return nil, libErrors.GrpcError(errs.ErrNoVerifiedContact, "账号未绑定手机号或已验证的邮箱")
+	}
+	if err = ls.captcha.Verify(ctx, target, msg.Captcha); err != nil {
+		return nil, err
+	}
+
+	// 2. 立即下线全部设备并撤销第三方应用授权
+	if _, err = ls.kickAll(ctx, user.Id, ""); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("RequestAccountDeletion 下线设备出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if err = ls.oauth.RevokeAllConsents(ctx, user.Id); err != nil {
+		return nil, err
+	}
+
+	// 3. 记录计划清除时间
+	purgeTime, err := ls.accounts.RequestDeletion(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &RequestAccountDeletionResponse{PurgeTime: purgeTime}, nil
+}
+
+// CancelAccountDeletion 在冷静期内撤销注销，已撤销的第三方应用授权需重新授权
+func (ls *LoginService) CancelAccountDeletion(ctx context.Context, msg *CancelAccountDeletionMessage) (*CancelAccountDeletionResponse, error) {
+	user, err := ls.activeUser(ctx, "CancelAccountDeletion", msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err = ls.accounts.CancelDeletion(ctx, user); err != nil {
+		return nil, err
+	}
+	return &CancelAccountDeletionResponse{}, nil
+}
+
+// accountExport 导出的账号数据，不含密码哈希、令牌和密钥等凭证
+type accountExport struct {
+	ExportTime       int64               `json:"exportTime"`
+	Account          exportedAccount     `json:"account"`
+	TwoFactorEnabled bool                `json:"twoFactorEnabled"`
+	Identities       []*data.Identity    `json:"identities"`
+	Sessions         []exportedSession   `json:"sessions"`
+	OAuthClients     []*data.OAuthClient `json:"oauthClients"`
+	OAuthConsents    []exportedConsent   `json:"oauthConsents"`
+	Roles            []*data.UserRole    `json:"roles"`
+	ApiKeys          []*data.ApiKey      `json:"apiKeys"`
+	ServiceAccounts  []exportedAccount   `json:"serviceAccounts"`
+	AuditEvents      []*audit.Event      `json:"auditEvents"` // 保留期内的全部审计事件，按时间倒序
+	// 群组、消息和在线状态等其他服务中的数据，按服务名称分组
+	Services map[string][]json.RawMessage `json:"services"`
+}
+
+type exportedAccount struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	Avatar        string `json:"avatar,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"emailVerified,omitempty"`
+	Mobile        string `json:"mobile,omitempty"`
+	HasPassword   bool   `json:"hasPassword,omitempty"`
+	CreateTime    int64  `json:"createTime"`
+	LastLoginTime int64  `json:"lastLoginTime,omitempty"`
+	PurgeTime     int64  `json:"purgeTime,omitempty"`
+}
+
+type exportedSession struct {
+	DeviceId   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	Platform   string `json:"platform"`
+	Ip         string `json:"ip"`
+	LoginTime  int64  `json:"loginTime"`
+	LastSeen   int64  `json:"lastSeen"`
+}
+
+type exportedConsent struct {
+	ClientId   string   `json:"clientId"`
+	ClientName string   `json:"clientName"`
+	Scopes     []string `json:"scopes"`
+	CreateTime int64    `json:"createTime"`
+	UpdateTime int64    `json:"updateTime"`
+}
+
+// ExportAccountData 导出账号在认证服务和其他服务中保存的全部数据，供用户在注销前下载
+func (ls *LoginService) ExportAccountData(ctx context.Context, msg *ExportAccountDataMessage) (*ExportAccountDataResponse, error) {
+	user, err := ls.activeUser(ctx, "ExportAccountData", msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	out := &accountExport{ExportTime: time.Now().UnixMilli(), Account: toExportedAccount(user)}
+
+	if out.TwoFactorEnabled, err = ls.totp.Enabled(ctx, user.Id); err != nil {
+		return nil, exportError("两步验证设置", err)
+	}
+	if out.Identities, err = ls.identities.ListByUser(ctx, user.Id); err != nil {
+		return nil, exportError("第三方身份", err)
+	}
+	sessions, err := ls.sessions.List(ctx, user.Id)
+	if err != nil {
+		return nil, exportError("登录设备", err)
+	}
+	for _, s := range sessions {
+		out.Sessions = append(out.Sessions, exportedSession{
+			DeviceId:   s.DeviceId,
+			DeviceName: s.DeviceName,
+			Platform:   s.Platform,
+			Ip:         s.Ip,
+			LoginTime:  s.LoginTime,
+			LastSeen:   s.LastSeen,
+		})
+	}
+	// 以下管理器返回的错误已记录日志并转换为 GrpcError
+	if out.OAuthClients, err = ls.oauth.ListClients(ctx, user.Id); err != nil {
+		return nil, err
+	}
+	consents, err := ls.oauth.ListConsents(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range consents {
+		out.OAuthConsents = append(out.OAuthConsents, exportedConsent{
+			ClientId:   c.ClientId,
+			ClientName: c.ClientName,
+			Scopes:     c.Scopes,
+			CreateTime: c.CreateTime,
+			UpdateTime: c.UpdateTime,
+		})
+	}
+	if out.Roles, err = ls.roles.List(ctx, user.Id); err != nil {
+		return nil, err
+	}
+	if out.ApiKeys, err = ls.apiKeys.List(ctx, user.Id, 0); err != nil {
+		return nil, err
+	}
+	accounts, err := ls.apiKeys.ListServiceAccounts(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range accounts {
+		out.ServiceAccounts = append(out.ServiceAccounts, toExportedAccount(a))
+	}
+	// 审计事件按查询上限分页读取，固定截止时间避免导出期间新写入的事件导致分页错位
+	filter := &audit.Filter{ActorId: user.Id, To: out.ExportTime + 1}
+	for {
+		events, err := ls.audit.Query(ctx, filter)
+		if err != nil {
+			return nil, exportError("审计事件", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		out.AuditEvents = append(out.AuditEvents, events...)
+		filter.Offset += len(events)
+	}
+	if out.Services, err = ls.accounts.Export(ctx, user.Id); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, exportError("序列化", err)
+	}
+	return &ExportAccountDataResponse{Data: string(raw), ExportTime: out.ExportTime}, nil
+}
+
+// PurgeUser 清除已注销账号的登录会话和第三方身份关联，由账号清除任务调用
+func (ls *LoginService) PurgeUser(ctx context.Context, userId int64) error {
+	if _, err := ls.kickAll(ctx, userId, ""); err != nil {
+		return err
+	}
+	identities, err := ls.identities.ListByUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, i := range identities {
+		if _, err = ls.identities.Delete(ctx, userId, i.Provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeUser 查询未注销的账号
+func (ls *LoginService) activeUser(ctx context.Context, method string, userId int64) (*data.User, error) {
+	user, err := ls.userRepo.FindById(ctx, userId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("%s 查询账号出错，原因: %v", method, err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	return user, nil
+}
+
+func toExportedAccount(u *data.User) exportedAccount {
+	return exportedAccount{
+		Id:            u.Id,
+		Name:          u.Name,
+		Avatar:        u.Avatar,
+		Username:      u.Username,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Mobile:        u.Mobile,
+		HasPassword:   u.PasswordHash != "",
+		CreateTime:    u.CreateTime,
+		LastLoginTime: u.LastLoginTime,
+		PurgeTime:     u.PurgeTime,
+	}
+}
+
+func exportError(part string, err error) error {
+	libLog.IMLog.Error(fmt.Sprintf("ExportAccountData 导出%s出错，原因: %v", part, err))
+	return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+}
@@ -0,0 +1,127 @@
+package login_service_v1
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/apikey"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"time"
+)
+
+// CreateServiceAccount 为账号创建服务账号，服务账号只能通过 API Key 调用接口
+func (ls *LoginService) CreateServiceAccount(ctx context.Context, msg *CreateServiceAccountMessage) (*CreateServiceAccountResponse, error) {
+	account, err := ls.apiKeys.CreateServiceAccount(ctx, msg.UserId, msg.Name)
+	if err != nil {
+		return nil, err
+	}
+	audit.SetTarget(ctx, account.Id)
+	return &CreateServiceAccountResponse{Account: toServiceAccountMessage(account)}, nil
+}
+
+// ListServiceAccounts 列出账号创建的服务账号
+func (ls *LoginService) ListServiceAccounts(ctx context.Context, msg *ListServiceAccountsMessage) (*ListServiceAccountsResponse, error) {
+	list, err := ls.apiKeys.ListServiceAccounts(ctx, msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ListServiceAccountsResponse{}
+	for _, a := range list {
+		rsp.Accounts = append(rsp.Accounts, toServiceAccountMessage(a))
+	}
+	return rsp, nil
+}
+
+// DeleteServiceAccount 注销服务账号，它的 API Key 一并吊销
+func (ls *LoginService) DeleteServiceAccount(ctx context.Context, msg *DeleteServiceAccountMessage) (*DeleteServiceAccountResponse, error) {
+	audit.SetTarget(ctx, msg.AccountId)
+	if err := ls.apiKeys.DeleteServiceAccount(ctx, msg.UserId, msg.AccountId); err != nil {
+		return nil, err
+	}
+	return &DeleteServiceAccountResponse{}, nil
+}
+
+// CreateApiKey 为账号或其服务账号创建 API Key，完整的 Key 只在此时返回一次
+func (ls *LoginService) CreateApiKey(ctx context.Context, msg *CreateApiKeyMessage) (*ApiKeySecretResponse, error) {
+	if msg.AccountId != 0 {
+		audit.SetTarget(ctx, msg.AccountId)
+	}
+	key, secret, err := ls.apiKeys.Create(ctx, msg.UserId, &apikey.Spec{
+		AccountId: msg.AccountId,
+		Name:      msg.Name,
+		Scopes:    msg.Scopes,
+		ExpireIn:  time.Duration(msg.ExpireIn) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ApiKeySecretResponse{Key: toApiKeyMessage(key), Secret: secret}, nil
+}
+
+// ListApiKeys 列出账号或其服务账号的 API Key，不返回 Key 的明文和哈希
+func (ls *LoginService) ListApiKeys(ctx context.Context, msg *ListApiKeysMessage) (*ListApiKeysResponse, error) {
+	list, err := ls.apiKeys.List(ctx, msg.UserId, msg.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ListApiKeysResponse{}
+	for _, k := range list {
+		rsp.Keys = append(rsp.Keys, toApiKeyMessage(k))
+	}
+	return rsp, nil
+}
+
+// RotateApiKey 轮换 API Key，旧的 Key 立即失效
+func (ls *LoginService) RotateApiKey(ctx context.Context, msg *ApiKeyPrefixMessage) (*ApiKeySecretResponse, error) {
+	key, secret, err := ls.apiKeys.Rotate(ctx, msg.UserId, msg.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &ApiKeySecretResponse{Key: toApiKeyMessage(key), Secret: secret}, nil
+}
+
+// RevokeApiKey 吊销 API Key
+func (ls *LoginService) RevokeApiKey(ctx context.Context, msg *ApiKeyPrefixMessage) (*RevokeApiKeyResponse, error) {
+	if err := ls.apiKeys.Revoke(ctx, msg.UserId, msg.Prefix); err != nil {
+		return nil, err
+	}
+	return &RevokeApiKeyResponse{}, nil
+}
+
+// VerifyApiKey 供 api-center 校验请求中的 API Key
+func (ls *LoginService) VerifyApiKey(ctx context.Context, msg *VerifyApiKeyMessage) (*VerifyApiKeyResponse, error) {
+	key, owner, err := ls.apiKeys.Verify(ctx, msg.Key, msg.Ip)
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyApiKeyResponse{
+		UserId:         key.OwnerId,
+		Prefix:         key.Prefix,
+		Scopes:         key.Scopes,
+		ExpireAt:       key.ExpireTime,
+		ServiceAccount: owner.IsServiceAccount(),
+	}, nil
+}
+
+func toServiceAccountMessage(u *data.User) *ServiceAccountMessage {
+	return &ServiceAccountMessage{
+		Id:         u.Id,
+		Name:       u.Name,
+		OwnerId:    u.OwnerId,
+		CreateTime: u.CreateTime,
+	}
+}
+
+func toApiKeyMessage(k *data.ApiKey) *ApiKeyMessage {
+	return &ApiKeyMessage{
+		Prefix:       k.Prefix,
+		OwnerId:      k.OwnerId,
+		Name:         k.Name,
+		Scopes:       k.Scopes,
+		ExpireTime:   k.ExpireTime,
+		LastUsedTime: k.LastUsedTime,
+		LastUsedIp:   k.LastUsedIp,
+		CreateTime:   k.CreateTime,
+		RotateTime:   k.RotateTime,
+		RevokeTime:   k.RevokeTime,
+	}
+}
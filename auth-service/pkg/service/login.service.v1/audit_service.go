@@ -0,0 +1,45 @@
+package login_service_v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+)
+
+// QueryAuditEvents 按时间、账号、事件类型和结果查询审计事件，供管理后台使用
+func (ls *LoginService) QueryAuditEvents(ctx context.Context, msg *QueryAuditEventsMessage) (*QueryAuditEventsResponse, error) {
+	events, err := ls.audit.Query(ctx, &audit.Filter{
+		From:    msg.From,
+		To:      msg.To,
+		ActorId: msg.UserId,
+		Type:    msg.Type,
+		Outcome: msg.Outcome,
+		Limit:   int(msg.Limit),
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("QueryAuditEvents 读取审计日志出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	rsp := &QueryAuditEventsResponse{Events: make([]*AuditEventMessage, 0, len(events))}
+	for _, e := range events {
+		rsp.Events = append(rsp.Events, &AuditEventMessage{
+			Id:         e.Id,
+			Time:       e.Time,
+			Type:       e.Type,
+			Outcome:    e.Outcome,
+			ActorId:    e.ActorId,
+			Account:    e.Account,
+			ClientId:   e.ClientId,
+			DeviceId:   e.DeviceId,
+			Ip:         e.Ip,
+			UserAgent:  e.UserAgent,
+			ReasonCode: e.ReasonCode,
+			Reason:     e.Reason,
+			TargetId:   e.TargetId,
+		})
+	}
+	return rsp, nil
+}
@@ -0,0 +1,56 @@
+package login_service_v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strings"
+)
+
+// BindEmail 通过邮件验证码绑定或更换邮箱，绑定后邮箱标记为已验证，可用于找回密码
+func (ls *LoginService) BindEmail(ctx context.Context, msg *BindEmailMessage) (*BindEmailResponse, error) {
+	// 1. 校验参数
+	email, err := parseEmail(msg.Email)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ls.userRepo.FindById(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("BindEmail 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+
+	// 2. 校验并消费验证码，证明邮箱归当前用户所有
+	if err = ls.captcha.Verify(ctx, email, msg.Captcha); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存邮箱，唯一性由存储层保证
+	user.Email, user.EmailVerified = email, true
+	switch err = ls.userRepo.Save(ctx, user); {
+	case errors.Is(err, repo.ErrEmailExists):
+		return nil, libErrors.GrpcError(errs.ErrEmailExists, "邮箱已被使用")
+	case err != nil:
+		libLog.IMLog.Error(fmt.Sprintf("BindEmail 更新账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 绑定邮箱 %s", user.Id, email))
+	return &BindEmailResponse{Member: toMemberMessage(user)}, nil
+}
+
+// parseEmail 校验邮箱并统一为小写，作为验证码缓存和账号的唯一键
+func parseEmail(raw string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	if !utils.VerifyEmail(email) {
+		return "", libErrors.GrpcError(errs.ErrNoLegalEmail, "邮箱不合法")
+	}
+	return email, nil
+}
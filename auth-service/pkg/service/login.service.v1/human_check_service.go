@@ -0,0 +1,40 @@
+package login_service_v1
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/humancheck"
+)
+
+// CreateHumanCheck 按客户端风险下发人机验证题，低风险为滑块拼图，风险越高验证越严格
+func (ls *LoginService) CreateHumanCheck(ctx context.Context, msg *HumanCheckMessage) (*HumanCheckResponse, error) {
+	c, err := ls.human.Create(ctx, &humancheck.RiskInput{
+		Ip:          msg.Ip,
+		Fingerprint: msg.Fingerprint,
+		UserAgent:   msg.UserAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &HumanCheckResponse{
+		Id:       c.Id,
+		Kind:     c.Kind,
+		Image:    c.Image,
+		Piece:    c.Piece,
+		PieceY:   int32(c.PieceY),
+		Width:    int32(c.Width),
+		Height:   int32(c.Height),
+		ExpireAt: c.ExpireAt,
+	}, nil
+}
+
+// VerifyHumanCheck 校验人机验证答案，通过后返回发送短信验证码所需的一次性凭证
+func (ls *LoginService) VerifyHumanCheck(ctx context.Context, msg *VerifyHumanCheckMessage) (*VerifyHumanCheckResponse, error) {
+	ticket, expireAt, err := ls.human.Verify(ctx, msg.Id, msg.Answer, &humancheck.RiskInput{
+		Ip:          msg.Ip,
+		Fingerprint: msg.Fingerprint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyHumanCheckResponse{Ticket: ticket, ExpireAt: expireAt}, nil
+}
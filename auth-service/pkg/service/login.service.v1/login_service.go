@@ -2,55 +2,312 @@ package login_service_v1
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/account"
+	"github.com/MortalSC/IM-System/auth-service/internal/apikey"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/captcha"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/humancheck"
+	"github.com/MortalSC/IM-System/auth-service/internal/mail"
+	"github.com/MortalSC/IM-System/auth-service/internal/oauth"
+	"github.com/MortalSC/IM-System/auth-service/internal/oidc"
+	"github.com/MortalSC/IM-System/auth-service/internal/password"
+	"github.com/MortalSC/IM-System/auth-service/internal/phone"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/role"
+	"github.com/MortalSC/IM-System/auth-service/internal/session"
+	"github.com/MortalSC/IM-System/auth-service/internal/sms"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	"github.com/MortalSC/IM-System/auth-service/internal/totp"
 	"github.com/MortalSC/IM-System/auth-service/internal/utils"
 	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
 	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strconv"
 	"time"
 )
 
+const (
+	smsSendTimeout  = 30 * time.Second // 单条短信发送（含重试）的总超时
+	mailSendTimeout = 60 * time.Second // 单封邮件发送（含重试）的总超时
+	tokenType       = "bearer"
+
+	captchaChannelSms   = "sms"   // 短信验证码
+	captchaChannelEmail = "email" // 邮件验证码
+)
+
 type LoginService struct {
 	UnimplementedLoginServiceServer
-	cache LibCache.Cache
+	cache      LibCache.Cache
+	userRepo   repo.UserRepository
+	captcha    *captcha.Manager
+	sms        *sms.Dispatcher
+	mail       *mail.Dispatcher
+	tokens     *token.Manager
+	sessions   *session.Store
+	password   *password.Manager
+	totp       *totp.Manager
+	phones     *phone.Parser
+	oidc       *oidc.Manager
+	identities repo.IdentityRepository
+	oauth      *oauth.Manager
+	human      *humancheck.Manager
+	audit      *audit.Logger
+	roles      *role.Manager
+	apiKeys    *apikey.Manager
+	accounts   *account.Manager
+	gateways   Gateways
 }
 
-func New(cache LibCache.Cache) *LoginService {
+// Gateways 断开设备在网关上的长连接
+type Gateways interface {
+	Kick(ctx context.Context, userId int64, deviceId, reason string) (int, error)
+}
+
+func New(cache LibCache.Cache, userRepo repo.UserRepository, captchaMgr *captcha.Manager,
+	smsDispatcher *sms.Dispatcher, mailDispatcher *mail.Dispatcher, tokens *token.Manager, sessions *session.Store, passwords *password.Manager,
+	totpMgr *totp.Manager, phones *phone.Parser, oidcMgr *oidc.Manager, identities repo.IdentityRepository,
+	oauthMgr *oauth.Manager, human *humancheck.Manager, auditLog *audit.Logger, roles *role.Manager,
+	apiKeys *apikey.Manager, accounts *account.Manager) *LoginService {
 	return &LoginService{
-		cache: cache,
+		cache:      cache,
+		userRepo:   userRepo,
+		captcha:    captchaMgr,
+		sms:        smsDispatcher,
+		mail:       mailDispatcher,
+		tokens:     tokens,
+		sessions:   sessions,
+		password:   passwords,
+		totp:       totpMgr,
+		phones:     phones,
+		oidc:       oidcMgr,
+		identities: identities,
+		oauth:      oauthMgr,
+		human:      human,
+		audit:      auditLog,
+		roles:      roles,
+		apiKeys:    apiKeys,
+		accounts:   accounts,
 	}
 }
 
+// UseGateways 设置网关，设置后下线设备时立即断开设备的长连接；未设置时长连接保持到访问令牌过期
+func (ls *LoginService) UseGateways(gateways Gateways) {
+	ls.gateways = gateways
+}
+
 func (ls *LoginService) GetCaptcha(ctx context.Context, msg *CaptchaMessage) (*CaptchaResponse, error) {
-	// 1. 获取参数
-	mobile := msg.Mobile // 解析获取请求表单中的手机号
+	// 1. 校验参数，手机号规范化为 E.164 格式、邮箱统一小写，作为验证码缓存和账号的唯一键
+	target, err := ls.captchaTarget(msg)
+	if err != nil {
+		return nil, err
+	}
 
-	// 2. 校验参数
-	if !utils.VerifyMobile(mobile) {
-		return nil, errors.New("手机号不合法")
+	// 2. 消费人机验证通过凭证，每次发送验证码都需要先完成一次人机验证
+	if err = ls.human.Consume(ctx, msg.Ticket, &humancheck.RiskInput{Ip: msg.Ip, Fingerprint: msg.Fingerprint}); err != nil {
+		return nil, err
 	}
 
-	// 3. 生成随机验证码（随机的4位1000~9999或6位100000~999999）
-	// 此处为模拟，固定使用 "123456" 作为验证码。
-	code := "123456"
+	// 3. 频控检查通过后生成随机验证码（4位1000~9999或6位100000~999999）并保存，有效期内可用于登录
+	code, err := ls.captcha.Issue(ctx, target, msg.Ip)
+	if err != nil {
+		return nil, err
+	}
 
-	// 4. 调用短信平台
-	// 使用 Goroutine 异步调用短信平台，以便快速响应接口请求
-	go func() {
-		time.Sleep(2 * time.Second) // 模拟调用短信平台的耗时操作
-		libLog.IMLog.Info("短信平台调用成功，发送短信")
+	// 4. 调用短信平台或邮件服务
+	// 验证码已在返回前保存，使用 Goroutine 异步发送以便快速响应接口请求，投递结果由状态回调记录
+	params := map[string]string{
+		"Code":    code,
+		"Minutes": strconv.Itoa(int(ls.captcha.Expire().Minutes())),
+	}
+	if msg.Channel == captchaChannelEmail {
+		go func() {
+			c, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+			defer cancel()
+			if err := ls.mail.SendTemplate(c, target, mail.TemplateCaptcha, msg.Locale, params); err != nil {
+				libLog.IMLog.Error(fmt.Sprintf("验证码邮件发送失败，原因: %v", err))
+			}
+		}()
+	} else {
+		go func() {
+			c, cancel := context.WithTimeout(context.Background(), smsSendTimeout)
+			defer cancel()
+			if err := ls.sms.SendTemplate(c, target, sms.TemplateCaptcha, params); err != nil {
+				libLog.IMLog.Error(fmt.Sprintf("验证码短信发送失败，原因: %v", err))
+			}
+		}()
+	}
 
-		// 5. 存储验证码到 Redis
-		// 使用带超时的上下文，避免 Redis 请求阻塞主进程
-		c, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
+	// 5. 仅开发模式下返回验证码，避免验证码泄露给调用方
+	rsp := &CaptchaResponse{}
+	if ls.captcha.DevMode() {
+		rsp.Code = code
+	}
+	return rsp, nil
+}
 
-		// 将验证码存储到 Redis，设置过期时间为 15 分钟
-		err := ls.cache.Put(c, "REGISTER_"+mobile, code, 15*time.Minute)
+// captchaTarget 按发送渠道校验并规范化验证码接收方
+func (ls *LoginService) captchaTarget(msg *CaptchaMessage) (string, error) {
+	switch msg.Channel {
+	case "", captchaChannelSms:
+		number, err := ls.parseMobile(msg.Mobile)
 		if err != nil {
-			libLog.IMLog.Error(fmt.Sprintf("验证码存入 Redis 出错，原因: %v\n", err))
+			return "", err
 		}
-	}()
+		return number.E164, nil
+	case captchaChannelEmail:
+		return parseEmail(msg.Email)
+	default:
+		return "", libErrors.GrpcError(errors.ErrCaptchaChannel, "不支持的验证码发送渠道")
+	}
+}
+
+// Login 使用手机号 + 验证码登录，账号不存在时自动注册
+func (ls *LoginService) Login(ctx context.Context, msg *LoginMessage) (*LoginResponse, error) {
+	// 1. 校验参数
+	number, err := ls.parseMobile(msg.Mobile)
+	if err != nil {
+		return nil, err
+	}
+	mobile := number.E164
+
+	// 2. 校验并消费验证码
+	if err = ls.captcha.Verify(ctx, mobile, msg.Captcha); err != nil {
+		return nil, err
+	}
+
+	// 3. 查询账号，不存在则注册
+	user, err := ls.loadOrCreateUser(ctx, mobile)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("Login 加载账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	// 4. 签发令牌并记录设备会话
+	return ls.signIn(ctx, "Login", user, &device{
+		Id:       msg.DeviceId,
+		Name:     msg.DeviceName,
+		Platform: msg.Platform,
+		Ip:       msg.Ip,
+	})
+}
+
+// device 发起登录的设备
+type device struct {
+	Id       string
+	Name     string
+	Platform string
+	Ip       string
+}
 
-	return &CaptchaResponse{Code: code}, nil
+// signIn 完成第一步认证后调用：已启用两步验证的账号返回挑战令牌，否则直接签发令牌
+func (ls *LoginService) signIn(ctx context.Context, method string, user *data.User, dev *device) (*LoginResponse, error) {
+	audit.SetActor(ctx, user.Id)
+	enabled, err := ls.totp.Enabled(ctx, user.Id)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("%s 查询两步验证设置出错，原因: %v", method, err))
+		return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if !enabled {
+		return ls.issueCredentials(ctx, method, user, dev)
+	}
+
+	token, expireAt, err := ls.totp.NewChallenge(ctx, &totp.Challenge{
+		UserId:     user.Id,
+		DeviceId:   dev.Id,
+		DeviceName: dev.Name,
+		Platform:   dev.Platform,
+		Ip:         dev.Ip,
+	})
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("%s 创建两步验证挑战出错，原因: %v", method, err))
+		return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	audit.Challenge(ctx)
+	return &LoginResponse{TwoFactorRequired: true, ChallengeToken: token, ChallengeExpire: expireAt}, nil
+}
+
+// issueCredentials 为账号签发访问令牌和刷新令牌，并记录设备会话，同平台在线设备超出上限时将最久未活跃的设备挤下线
+func (ls *LoginService) issueCredentials(ctx context.Context, method string, user *data.User, dev *device) (*LoginResponse, error) {
+	// 客户端未上报设备 ID 时为其生成一个
+	deviceId := dev.Id
+	if deviceId == "" {
+		var err error
+		if deviceId, err = utils.RandomToken(16); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("%s 生成设备 ID 出错，原因: %v", method, err))
+			return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+		}
+	}
+	audit.SetDevice(ctx, deviceId)
+	pair, err := ls.tokens.Issue(ctx, user.Id, deviceId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("%s 签发令牌出错，原因: %v", method, err))
+		return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	sess := &session.Session{
+		UserId:     user.Id,
+		DeviceId:   deviceId,
+		DeviceName: dev.Name,
+		Platform:   session.NormalizePlatform(dev.Platform),
+		Ip:         dev.Ip,
+		LoginTime:  user.LastLoginTime,
+		LastSeen:   user.LastLoginTime,
+	}
+	setSessionTokens(sess, pair)
+	if err = ls.startSession(ctx, sess); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("%s 记录会话出错，原因: %v", method, err))
+		return nil, libErrors.GrpcError(errors.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	return &LoginResponse{
+		Member:    toMemberMessage(user),
+		TokenList: toTokenMessage(pair),
+		DeviceId:  deviceId,
+	}, nil
+}
+
+// loadOrCreateUser 按手机号加载账号，不存在时创建，并刷新最近登录时间
+func (ls *LoginService) loadOrCreateUser(ctx context.Context, mobile string) (*data.User, error) {
+	now := time.Now().UnixMilli()
+	user, err := ls.userRepo.FindByMobile(ctx, mobile)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user = &data.User{
+			Name:          "用户" + mobile[len(mobile)-4:],
+			Mobile:        mobile,
+			CreateTime:    now,
+			LastLoginTime: now,
+		}
+		return user, ls.userRepo.Create(ctx, user)
+	}
+	user.LastLoginTime = now
+	return user, ls.userRepo.Save(ctx, user)
+}
+
+// parseMobile 解析并规范化手机号，校验失败时返回带具体错误码的 gRPC 错误
+func (ls *LoginService) parseMobile(raw string) (*phone.Number, error) {
+	number, err := ls.phones.Parse(raw)
+	if e, ok := err.(*phone.Error); ok {
+		return nil, libErrors.GrpcError(e.Code, e.Reason)
+	}
+	return number, err
+}
+
+func toMemberMessage(user *data.User) *MemberMessage {
+	return &MemberMessage{
+		Id:            user.Id,
+		Name:          user.Name,
+		Mobile:        user.Mobile,
+		CreateTime:    user.CreateTime,
+		LastLoginTime: user.LastLoginTime,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		PurgeTime:     user.PurgeTime,
+	}
 }
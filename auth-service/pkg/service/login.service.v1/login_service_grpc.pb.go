@@ -22,7 +22,56 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LoginServiceClient interface {
+	CreateHumanCheck(ctx context.Context, in *HumanCheckMessage, opts ...grpc.CallOption) (*HumanCheckResponse, error)
+	VerifyHumanCheck(ctx context.Context, in *VerifyHumanCheckMessage, opts ...grpc.CallOption) (*VerifyHumanCheckResponse, error)
 	GetCaptcha(ctx context.Context, in *CaptchaMessage, opts ...grpc.CallOption) (*CaptchaResponse, error)
+	Login(ctx context.Context, in *LoginMessage, opts ...grpc.CallOption) (*LoginResponse, error)
+	Register(ctx context.Context, in *RegisterMessage, opts ...grpc.CallOption) (*LoginResponse, error)
+	PasswordLogin(ctx context.Context, in *PasswordLoginMessage, opts ...grpc.CallOption) (*LoginResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordMessage, opts ...grpc.CallOption) (*ResetPasswordResponse, error)
+	BindEmail(ctx context.Context, in *BindEmailMessage, opts ...grpc.CallOption) (*BindEmailResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenMessage, opts ...grpc.CallOption) (*TokenMessage, error)
+	RevokeToken(ctx context.Context, in *RevokeTokenMessage, opts ...grpc.CallOption) (*RevokeTokenResponse, error)
+	VerifyToken(ctx context.Context, in *VerifyTokenMessage, opts ...grpc.CallOption) (*VerifyTokenResponse, error)
+	GetPublicKeys(ctx context.Context, in *PublicKeysMessage, opts ...grpc.CallOption) (*PublicKeysResponse, error)
+	EnrollTotp(ctx context.Context, in *EnrollTotpMessage, opts ...grpc.CallOption) (*EnrollTotpResponse, error)
+	ConfirmTotp(ctx context.Context, in *ConfirmTotpMessage, opts ...grpc.CallOption) (*ConfirmTotpResponse, error)
+	DisableTotp(ctx context.Context, in *DisableTotpMessage, opts ...grpc.CallOption) (*DisableTotpResponse, error)
+	VerifyTwoFactor(ctx context.Context, in *VerifyTwoFactorMessage, opts ...grpc.CallOption) (*LoginResponse, error)
+	ListSessions(ctx context.Context, in *ListSessionsMessage, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	KickDevice(ctx context.Context, in *KickDeviceMessage, opts ...grpc.CallOption) (*KickDeviceResponse, error)
+	LogoutAll(ctx context.Context, in *LogoutAllMessage, opts ...grpc.CallOption) (*LogoutAllResponse, error)
+	ListOidcProviders(ctx context.Context, in *ListOidcProvidersMessage, opts ...grpc.CallOption) (*ListOidcProvidersResponse, error)
+	OidcAuthorize(ctx context.Context, in *OidcAuthorizeMessage, opts ...grpc.CallOption) (*OidcAuthorizeResponse, error)
+	OidcCallback(ctx context.Context, in *OidcCallbackMessage, opts ...grpc.CallOption) (*OidcCallbackResponse, error)
+	ListIdentities(ctx context.Context, in *ListIdentitiesMessage, opts ...grpc.CallOption) (*ListIdentitiesResponse, error)
+	UnlinkIdentity(ctx context.Context, in *UnlinkIdentityMessage, opts ...grpc.CallOption) (*UnlinkIdentityResponse, error)
+	RegisterOAuthClient(ctx context.Context, in *RegisterOAuthClientMessage, opts ...grpc.CallOption) (*RegisterOAuthClientResponse, error)
+	ListOAuthClients(ctx context.Context, in *ListOAuthClientsMessage, opts ...grpc.CallOption) (*ListOAuthClientsResponse, error)
+	RotateOAuthClientSecret(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*RotateOAuthClientSecretResponse, error)
+	DeleteOAuthClient(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*DeleteOAuthClientResponse, error)
+	GetOAuthConsent(ctx context.Context, in *OAuthAuthorizeMessage, opts ...grpc.CallOption) (*GetOAuthConsentResponse, error)
+	ApproveOAuthConsent(ctx context.Context, in *ApproveOAuthConsentMessage, opts ...grpc.CallOption) (*ApproveOAuthConsentResponse, error)
+	OAuthToken(ctx context.Context, in *OAuthTokenMessage, opts ...grpc.CallOption) (*OAuthTokenResponse, error)
+	IntrospectToken(ctx context.Context, in *IntrospectTokenMessage, opts ...grpc.CallOption) (*IntrospectTokenResponse, error)
+	ListOAuthConsents(ctx context.Context, in *ListOAuthConsentsMessage, opts ...grpc.CallOption) (*ListOAuthConsentsResponse, error)
+	RevokeOAuthConsent(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*RevokeOAuthConsentResponse, error)
+	QueryAuditEvents(ctx context.Context, in *QueryAuditEventsMessage, opts ...grpc.CallOption) (*QueryAuditEventsResponse, error)
+	AssignRole(ctx context.Context, in *AssignRoleMessage, opts ...grpc.CallOption) (*AssignRoleResponse, error)
+	RevokeRole(ctx context.Context, in *RevokeRoleMessage, opts ...grpc.CallOption) (*RevokeRoleResponse, error)
+	ListUserRoles(ctx context.Context, in *ListUserRolesMessage, opts ...grpc.CallOption) (*ListUserRolesResponse, error)
+	ChangeMobile(ctx context.Context, in *ChangeMobileMessage, opts ...grpc.CallOption) (*ChangeMobileResponse, error)
+	RequestAccountDeletion(ctx context.Context, in *RequestAccountDeletionMessage, opts ...grpc.CallOption) (*RequestAccountDeletionResponse, error)
+	CancelAccountDeletion(ctx context.Context, in *CancelAccountDeletionMessage, opts ...grpc.CallOption) (*CancelAccountDeletionResponse, error)
+	ExportAccountData(ctx context.Context, in *ExportAccountDataMessage, opts ...grpc.CallOption) (*ExportAccountDataResponse, error)
+	CreateServiceAccount(ctx context.Context, in *CreateServiceAccountMessage, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error)
+	ListServiceAccounts(ctx context.Context, in *ListServiceAccountsMessage, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error)
+	DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountMessage, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error)
+	CreateApiKey(ctx context.Context, in *CreateApiKeyMessage, opts ...grpc.CallOption) (*ApiKeySecretResponse, error)
+	ListApiKeys(ctx context.Context, in *ListApiKeysMessage, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	RotateApiKey(ctx context.Context, in *ApiKeyPrefixMessage, opts ...grpc.CallOption) (*ApiKeySecretResponse, error)
+	RevokeApiKey(ctx context.Context, in *ApiKeyPrefixMessage, opts ...grpc.CallOption) (*RevokeApiKeyResponse, error)
+	VerifyApiKey(ctx context.Context, in *VerifyApiKeyMessage, opts ...grpc.CallOption) (*VerifyApiKeyResponse, error)
 }
 
 type loginServiceClient struct {
@@ -33,57 +82,1576 @@ func NewLoginServiceClient(cc grpc.ClientConnInterface) LoginServiceClient {
 	return &loginServiceClient{cc}
 }
 
+func (c *loginServiceClient) CreateHumanCheck(ctx context.Context, in *HumanCheckMessage, opts ...grpc.CallOption) (*HumanCheckResponse, error) {
+	out := new(HumanCheckResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/CreateHumanCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) VerifyHumanCheck(ctx context.Context, in *VerifyHumanCheckMessage, opts ...grpc.CallOption) (*VerifyHumanCheckResponse, error) {
+	out := new(VerifyHumanCheckResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/VerifyHumanCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *loginServiceClient) GetCaptcha(ctx context.Context, in *CaptchaMessage, opts ...grpc.CallOption) (*CaptchaResponse, error) {
 	out := new(CaptchaResponse)
 	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/GetCaptcha", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *loginServiceClient) Login(ctx context.Context, in *LoginMessage, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) Register(ctx context.Context, in *RegisterMessage, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/Register", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) PasswordLogin(ctx context.Context, in *PasswordLoginMessage, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/PasswordLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordMessage, opts ...grpc.CallOption) (*ResetPasswordResponse, error) {
+	out := new(ResetPasswordResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ResetPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) BindEmail(ctx context.Context, in *BindEmailMessage, opts ...grpc.CallOption) (*BindEmailResponse, error) {
+	out := new(BindEmailResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/BindEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenMessage, opts ...grpc.CallOption) (*TokenMessage, error) {
+	out := new(TokenMessage)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RefreshToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RevokeToken(ctx context.Context, in *RevokeTokenMessage, opts ...grpc.CallOption) (*RevokeTokenResponse, error) {
+	out := new(RevokeTokenResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RevokeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) VerifyToken(ctx context.Context, in *VerifyTokenMessage, opts ...grpc.CallOption) (*VerifyTokenResponse, error) {
+	out := new(VerifyTokenResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/VerifyToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) GetPublicKeys(ctx context.Context, in *PublicKeysMessage, opts ...grpc.CallOption) (*PublicKeysResponse, error) {
+	out := new(PublicKeysResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/GetPublicKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) EnrollTotp(ctx context.Context, in *EnrollTotpMessage, opts ...grpc.CallOption) (*EnrollTotpResponse, error) {
+	out := new(EnrollTotpResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/EnrollTotp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ConfirmTotp(ctx context.Context, in *ConfirmTotpMessage, opts ...grpc.CallOption) (*ConfirmTotpResponse, error) {
+	out := new(ConfirmTotpResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ConfirmTotp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) DisableTotp(ctx context.Context, in *DisableTotpMessage, opts ...grpc.CallOption) (*DisableTotpResponse, error) {
+	out := new(DisableTotpResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/DisableTotp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) VerifyTwoFactor(ctx context.Context, in *VerifyTwoFactorMessage, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/VerifyTwoFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListSessions(ctx context.Context, in *ListSessionsMessage, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) KickDevice(ctx context.Context, in *KickDeviceMessage, opts ...grpc.CallOption) (*KickDeviceResponse, error) {
+	out := new(KickDeviceResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/KickDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) LogoutAll(ctx context.Context, in *LogoutAllMessage, opts ...grpc.CallOption) (*LogoutAllResponse, error) {
+	out := new(LogoutAllResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/LogoutAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListOidcProviders(ctx context.Context, in *ListOidcProvidersMessage, opts ...grpc.CallOption) (*ListOidcProvidersResponse, error) {
+	out := new(ListOidcProvidersResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListOidcProviders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) OidcAuthorize(ctx context.Context, in *OidcAuthorizeMessage, opts ...grpc.CallOption) (*OidcAuthorizeResponse, error) {
+	out := new(OidcAuthorizeResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/OidcAuthorize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) OidcCallback(ctx context.Context, in *OidcCallbackMessage, opts ...grpc.CallOption) (*OidcCallbackResponse, error) {
+	out := new(OidcCallbackResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/OidcCallback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListIdentities(ctx context.Context, in *ListIdentitiesMessage, opts ...grpc.CallOption) (*ListIdentitiesResponse, error) {
+	out := new(ListIdentitiesResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListIdentities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) UnlinkIdentity(ctx context.Context, in *UnlinkIdentityMessage, opts ...grpc.CallOption) (*UnlinkIdentityResponse, error) {
+	out := new(UnlinkIdentityResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/UnlinkIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RegisterOAuthClient(ctx context.Context, in *RegisterOAuthClientMessage, opts ...grpc.CallOption) (*RegisterOAuthClientResponse, error) {
+	out := new(RegisterOAuthClientResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RegisterOAuthClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListOAuthClients(ctx context.Context, in *ListOAuthClientsMessage, opts ...grpc.CallOption) (*ListOAuthClientsResponse, error) {
+	out := new(ListOAuthClientsResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListOAuthClients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RotateOAuthClientSecret(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*RotateOAuthClientSecretResponse, error) {
+	out := new(RotateOAuthClientSecretResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RotateOAuthClientSecret", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) DeleteOAuthClient(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*DeleteOAuthClientResponse, error) {
+	out := new(DeleteOAuthClientResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/DeleteOAuthClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) GetOAuthConsent(ctx context.Context, in *OAuthAuthorizeMessage, opts ...grpc.CallOption) (*GetOAuthConsentResponse, error) {
+	out := new(GetOAuthConsentResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/GetOAuthConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ApproveOAuthConsent(ctx context.Context, in *ApproveOAuthConsentMessage, opts ...grpc.CallOption) (*ApproveOAuthConsentResponse, error) {
+	out := new(ApproveOAuthConsentResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ApproveOAuthConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) OAuthToken(ctx context.Context, in *OAuthTokenMessage, opts ...grpc.CallOption) (*OAuthTokenResponse, error) {
+	out := new(OAuthTokenResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/OAuthToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) IntrospectToken(ctx context.Context, in *IntrospectTokenMessage, opts ...grpc.CallOption) (*IntrospectTokenResponse, error) {
+	out := new(IntrospectTokenResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/IntrospectToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListOAuthConsents(ctx context.Context, in *ListOAuthConsentsMessage, opts ...grpc.CallOption) (*ListOAuthConsentsResponse, error) {
+	out := new(ListOAuthConsentsResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListOAuthConsents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RevokeOAuthConsent(ctx context.Context, in *OAuthClientIdMessage, opts ...grpc.CallOption) (*RevokeOAuthConsentResponse, error) {
+	out := new(RevokeOAuthConsentResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RevokeOAuthConsent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) QueryAuditEvents(ctx context.Context, in *QueryAuditEventsMessage, opts ...grpc.CallOption) (*QueryAuditEventsResponse, error) {
+	out := new(QueryAuditEventsResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/QueryAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) AssignRole(ctx context.Context, in *AssignRoleMessage, opts ...grpc.CallOption) (*AssignRoleResponse, error) {
+	out := new(AssignRoleResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/AssignRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RevokeRole(ctx context.Context, in *RevokeRoleMessage, opts ...grpc.CallOption) (*RevokeRoleResponse, error) {
+	out := new(RevokeRoleResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RevokeRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListUserRoles(ctx context.Context, in *ListUserRolesMessage, opts ...grpc.CallOption) (*ListUserRolesResponse, error) {
+	out := new(ListUserRolesResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListUserRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ChangeMobile(ctx context.Context, in *ChangeMobileMessage, opts ...grpc.CallOption) (*ChangeMobileResponse, error) {
+	out := new(ChangeMobileResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ChangeMobile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RequestAccountDeletion(ctx context.Context, in *RequestAccountDeletionMessage, opts ...grpc.CallOption) (*RequestAccountDeletionResponse, error) {
+	out := new(RequestAccountDeletionResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RequestAccountDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) CancelAccountDeletion(ctx context.Context, in *CancelAccountDeletionMessage, opts ...grpc.CallOption) (*CancelAccountDeletionResponse, error) {
+	out := new(CancelAccountDeletionResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/CancelAccountDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ExportAccountData(ctx context.Context, in *ExportAccountDataMessage, opts ...grpc.CallOption) (*ExportAccountDataResponse, error) {
+	out := new(ExportAccountDataResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ExportAccountData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) CreateServiceAccount(ctx context.Context, in *CreateServiceAccountMessage, opts ...grpc.CallOption) (*CreateServiceAccountResponse, error) {
+	out := new(CreateServiceAccountResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/CreateServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListServiceAccounts(ctx context.Context, in *ListServiceAccountsMessage, opts ...grpc.CallOption) (*ListServiceAccountsResponse, error) {
+	out := new(ListServiceAccountsResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListServiceAccounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) DeleteServiceAccount(ctx context.Context, in *DeleteServiceAccountMessage, opts ...grpc.CallOption) (*DeleteServiceAccountResponse, error) {
+	out := new(DeleteServiceAccountResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/DeleteServiceAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) CreateApiKey(ctx context.Context, in *CreateApiKeyMessage, opts ...grpc.CallOption) (*ApiKeySecretResponse, error) {
+	out := new(ApiKeySecretResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/CreateApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) ListApiKeys(ctx context.Context, in *ListApiKeysMessage, opts ...grpc.CallOption) (*ListApiKeysResponse, error) {
+	out := new(ListApiKeysResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/ListApiKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RotateApiKey(ctx context.Context, in *ApiKeyPrefixMessage, opts ...grpc.CallOption) (*ApiKeySecretResponse, error) {
+	out := new(ApiKeySecretResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RotateApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) RevokeApiKey(ctx context.Context, in *ApiKeyPrefixMessage, opts ...grpc.CallOption) (*RevokeApiKeyResponse, error) {
+	out := new(RevokeApiKeyResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/RevokeApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loginServiceClient) VerifyApiKey(ctx context.Context, in *VerifyApiKeyMessage, opts ...grpc.CallOption) (*VerifyApiKeyResponse, error) {
+	out := new(VerifyApiKeyResponse)
+	err := c.cc.Invoke(ctx, "/login.service.v1.LoginService/VerifyApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoginServiceServer is the server API for LoginService service.
+// All implementations must embed UnimplementedLoginServiceServer
+// for forward compatibility
+type LoginServiceServer interface {
+	CreateHumanCheck(context.Context, *HumanCheckMessage) (*HumanCheckResponse, error)
+	VerifyHumanCheck(context.Context, *VerifyHumanCheckMessage) (*VerifyHumanCheckResponse, error)
+	GetCaptcha(context.Context, *CaptchaMessage) (*CaptchaResponse, error)
+	Login(context.Context, *LoginMessage) (*LoginResponse, error)
+	Register(context.Context, *RegisterMessage) (*LoginResponse, error)
+	PasswordLogin(context.Context, *PasswordLoginMessage) (*LoginResponse, error)
+	ResetPassword(context.Context, *ResetPasswordMessage) (*ResetPasswordResponse, error)
+	BindEmail(context.Context, *BindEmailMessage) (*BindEmailResponse, error)
+	RefreshToken(context.Context, *RefreshTokenMessage) (*TokenMessage, error)
+	RevokeToken(context.Context, *RevokeTokenMessage) (*RevokeTokenResponse, error)
+	VerifyToken(context.Context, *VerifyTokenMessage) (*VerifyTokenResponse, error)
+	GetPublicKeys(context.Context, *PublicKeysMessage) (*PublicKeysResponse, error)
+	EnrollTotp(context.Context, *EnrollTotpMessage) (*EnrollTotpResponse, error)
+	ConfirmTotp(context.Context, *ConfirmTotpMessage) (*ConfirmTotpResponse, error)
+	DisableTotp(context.Context, *DisableTotpMessage) (*DisableTotpResponse, error)
+	VerifyTwoFactor(context.Context, *VerifyTwoFactorMessage) (*LoginResponse, error)
+	ListSessions(context.Context, *ListSessionsMessage) (*ListSessionsResponse, error)
+	KickDevice(context.Context, *KickDeviceMessage) (*KickDeviceResponse, error)
+	LogoutAll(context.Context, *LogoutAllMessage) (*LogoutAllResponse, error)
+	ListOidcProviders(context.Context, *ListOidcProvidersMessage) (*ListOidcProvidersResponse, error)
+	OidcAuthorize(context.Context, *OidcAuthorizeMessage) (*OidcAuthorizeResponse, error)
+	OidcCallback(context.Context, *OidcCallbackMessage) (*OidcCallbackResponse, error)
+	ListIdentities(context.Context, *ListIdentitiesMessage) (*ListIdentitiesResponse, error)
+	UnlinkIdentity(context.Context, *UnlinkIdentityMessage) (*UnlinkIdentityResponse, error)
+	RegisterOAuthClient(context.Context, *RegisterOAuthClientMessage) (*RegisterOAuthClientResponse, error)
+	ListOAuthClients(context.Context, *ListOAuthClientsMessage) (*ListOAuthClientsResponse, error)
+	RotateOAuthClientSecret(context.Context, *OAuthClientIdMessage) (*RotateOAuthClientSecretResponse, error)
+	DeleteOAuthClient(context.Context, *OAuthClientIdMessage) (*DeleteOAuthClientResponse, error)
+	GetOAuthConsent(context.Context, *OAuthAuthorizeMessage) (*GetOAuthConsentResponse, error)
+	ApproveOAuthConsent(context.Context, *ApproveOAuthConsentMessage) (*ApproveOAuthConsentResponse, error)
+	OAuthToken(context.Context, *OAuthTokenMessage) (*OAuthTokenResponse, error)
+	IntrospectToken(context.Context, *IntrospectTokenMessage) (*IntrospectTokenResponse, error)
+	ListOAuthConsents(context.Context, *ListOAuthConsentsMessage) (*ListOAuthConsentsResponse, error)
+	RevokeOAuthConsent(context.Context, *OAuthClientIdMessage) (*RevokeOAuthConsentResponse, error)
+	QueryAuditEvents(context.Context, *QueryAuditEventsMessage) (*QueryAuditEventsResponse, error)
+	AssignRole(context.Context, *AssignRoleMessage) (*AssignRoleResponse, error)
+	RevokeRole(context.Context, *RevokeRoleMessage) (*RevokeRoleResponse, error)
+	ListUserRoles(context.Context, *ListUserRolesMessage) (*ListUserRolesResponse, error)
+	ChangeMobile(context.Context, *ChangeMobileMessage) (*ChangeMobileResponse, error)
+	RequestAccountDeletion(context.Context, *RequestAccountDeletionMessage) (*RequestAccountDeletionResponse, error)
+	CancelAccountDeletion(context.Context, *CancelAccountDeletionMessage) (*CancelAccountDeletionResponse, error)
+	ExportAccountData(context.Context, *ExportAccountDataMessage) (*ExportAccountDataResponse, error)
+	CreateServiceAccount(context.Context, *CreateServiceAccountMessage) (*CreateServiceAccountResponse, error)
+	ListServiceAccounts(context.Context, *ListServiceAccountsMessage) (*ListServiceAccountsResponse, error)
+	DeleteServiceAccount(context.Context, *DeleteServiceAccountMessage) (*DeleteServiceAccountResponse, error)
+	CreateApiKey(context.Context, *CreateApiKeyMessage) (*ApiKeySecretResponse, error)
+	ListApiKeys(context.Context, *ListApiKeysMessage) (*ListApiKeysResponse, error)
+	RotateApiKey(context.Context, *ApiKeyPrefixMessage) (*ApiKeySecretResponse, error)
+	RevokeApiKey(context.Context, *ApiKeyPrefixMessage) (*RevokeApiKeyResponse, error)
+	VerifyApiKey(context.Context, *VerifyApiKeyMessage) (*VerifyApiKeyResponse, error)
+	mustEmbedUnimplementedLoginServiceServer()
+}
+
+// UnimplementedLoginServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLoginServiceServer struct {
+}
+
+func (UnimplementedLoginServiceServer) CreateHumanCheck(context.Context, *HumanCheckMessage) (*HumanCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateHumanCheck not implemented")
+}
+func (UnimplementedLoginServiceServer) VerifyHumanCheck(context.Context, *VerifyHumanCheckMessage) (*VerifyHumanCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyHumanCheck not implemented")
+}
+func (UnimplementedLoginServiceServer) GetCaptcha(context.Context, *CaptchaMessage) (*CaptchaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCaptcha not implemented")
+}
+func (UnimplementedLoginServiceServer) Login(context.Context, *LoginMessage) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedLoginServiceServer) Register(context.Context, *RegisterMessage) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedLoginServiceServer) PasswordLogin(context.Context, *PasswordLoginMessage) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PasswordLogin not implemented")
+}
+func (UnimplementedLoginServiceServer) ResetPassword(context.Context, *ResetPasswordMessage) (*ResetPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (UnimplementedLoginServiceServer) BindEmail(context.Context, *BindEmailMessage) (*BindEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BindEmail not implemented")
+}
+func (UnimplementedLoginServiceServer) RefreshToken(context.Context, *RefreshTokenMessage) (*TokenMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedLoginServiceServer) RevokeToken(context.Context, *RevokeTokenMessage) (*RevokeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (UnimplementedLoginServiceServer) VerifyToken(context.Context, *VerifyTokenMessage) (*VerifyTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyToken not implemented")
+}
+func (UnimplementedLoginServiceServer) GetPublicKeys(context.Context, *PublicKeysMessage) (*PublicKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKeys not implemented")
+}
+func (UnimplementedLoginServiceServer) EnrollTotp(context.Context, *EnrollTotpMessage) (*EnrollTotpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTotp not implemented")
+}
+func (UnimplementedLoginServiceServer) ConfirmTotp(context.Context, *ConfirmTotpMessage) (*ConfirmTotpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTotp not implemented")
+}
+func (UnimplementedLoginServiceServer) DisableTotp(context.Context, *DisableTotpMessage) (*DisableTotpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTotp not implemented")
+}
+func (UnimplementedLoginServiceServer) VerifyTwoFactor(context.Context, *VerifyTwoFactorMessage) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyTwoFactor not implemented")
+}
+func (UnimplementedLoginServiceServer) ListSessions(context.Context, *ListSessionsMessage) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedLoginServiceServer) KickDevice(context.Context, *KickDeviceMessage) (*KickDeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickDevice not implemented")
+}
+func (UnimplementedLoginServiceServer) LogoutAll(context.Context, *LogoutAllMessage) (*LogoutAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LogoutAll not implemented")
+}
+func (UnimplementedLoginServiceServer) ListOidcProviders(context.Context, *ListOidcProvidersMessage) (*ListOidcProvidersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOidcProviders not implemented")
+}
+func (UnimplementedLoginServiceServer) OidcAuthorize(context.Context, *OidcAuthorizeMessage) (*OidcAuthorizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OidcAuthorize not implemented")
+}
+func (UnimplementedLoginServiceServer) OidcCallback(context.Context, *OidcCallbackMessage) (*OidcCallbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OidcCallback not implemented")
+}
+func (UnimplementedLoginServiceServer) ListIdentities(context.Context, *ListIdentitiesMessage) (*ListIdentitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIdentities not implemented")
+}
+func (UnimplementedLoginServiceServer) UnlinkIdentity(context.Context, *UnlinkIdentityMessage) (*UnlinkIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlinkIdentity not implemented")
+}
+func (UnimplementedLoginServiceServer) RegisterOAuthClient(context.Context, *RegisterOAuthClientMessage) (*RegisterOAuthClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterOAuthClient not implemented")
+}
+func (UnimplementedLoginServiceServer) ListOAuthClients(context.Context, *ListOAuthClientsMessage) (*ListOAuthClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOAuthClients not implemented")
+}
+func (UnimplementedLoginServiceServer) RotateOAuthClientSecret(context.Context, *OAuthClientIdMessage) (*RotateOAuthClientSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateOAuthClientSecret not implemented")
+}
+func (UnimplementedLoginServiceServer) DeleteOAuthClient(context.Context, *OAuthClientIdMessage) (*DeleteOAuthClientResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOAuthClient not implemented")
+}
+func (UnimplementedLoginServiceServer) GetOAuthConsent(context.Context, *OAuthAuthorizeMessage) (*GetOAuthConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOAuthConsent not implemented")
+}
+func (UnimplementedLoginServiceServer) ApproveOAuthConsent(context.Context, *ApproveOAuthConsentMessage) (*ApproveOAuthConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveOAuthConsent not implemented")
+}
+func (UnimplementedLoginServiceServer) OAuthToken(context.Context, *OAuthTokenMessage) (*OAuthTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OAuthToken not implemented")
+}
+func (UnimplementedLoginServiceServer) IntrospectToken(context.Context, *IntrospectTokenMessage) (*IntrospectTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IntrospectToken not implemented")
+}
+func (UnimplementedLoginServiceServer) ListOAuthConsents(context.Context, *ListOAuthConsentsMessage) (*ListOAuthConsentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOAuthConsents not implemented")
+}
+func (UnimplementedLoginServiceServer) RevokeOAuthConsent(context.Context, *OAuthClientIdMessage) (*RevokeOAuthConsentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeOAuthConsent not implemented")
+}
+func (UnimplementedLoginServiceServer) QueryAuditEvents(context.Context, *QueryAuditEventsMessage) (*QueryAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditEvents not implemented")
+}
+func (UnimplementedLoginServiceServer) AssignRole(context.Context, *AssignRoleMessage) (*AssignRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedLoginServiceServer) RevokeRole(context.Context, *RevokeRoleMessage) (*RevokeRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
+func (UnimplementedLoginServiceServer) ListUserRoles(context.Context, *ListUserRolesMessage) (*ListUserRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserRoles not implemented")
+}
+func (UnimplementedLoginServiceServer) ChangeMobile(context.Context, *ChangeMobileMessage) (*ChangeMobileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeMobile not implemented")
+}
+func (UnimplementedLoginServiceServer) RequestAccountDeletion(context.Context, *RequestAccountDeletionMessage) (*RequestAccountDeletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestAccountDeletion not implemented")
+}
+func (UnimplementedLoginServiceServer) CancelAccountDeletion(context.Context, *CancelAccountDeletionMessage) (*CancelAccountDeletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAccountDeletion not implemented")
+}
+func (UnimplementedLoginServiceServer) ExportAccountData(context.Context, *ExportAccountDataMessage) (*ExportAccountDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportAccountData not implemented")
+}
+func (UnimplementedLoginServiceServer) CreateServiceAccount(context.Context, *CreateServiceAccountMessage) (*CreateServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateServiceAccount not implemented")
+}
+func (UnimplementedLoginServiceServer) ListServiceAccounts(context.Context, *ListServiceAccountsMessage) (*ListServiceAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServiceAccounts not implemented")
+}
+func (UnimplementedLoginServiceServer) DeleteServiceAccount(context.Context, *DeleteServiceAccountMessage) (*DeleteServiceAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteServiceAccount not implemented")
+}
+func (UnimplementedLoginServiceServer) CreateApiKey(context.Context, *CreateApiKeyMessage) (*ApiKeySecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (UnimplementedLoginServiceServer) ListApiKeys(context.Context, *ListApiKeysMessage) (*ListApiKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (UnimplementedLoginServiceServer) RotateApiKey(context.Context, *ApiKeyPrefixMessage) (*ApiKeySecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateApiKey not implemented")
+}
+func (UnimplementedLoginServiceServer) RevokeApiKey(context.Context, *ApiKeyPrefixMessage) (*RevokeApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeApiKey not implemented")
+}
+func (UnimplementedLoginServiceServer) VerifyApiKey(context.Context, *VerifyApiKeyMessage) (*VerifyApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyApiKey not implemented")
+}
+func (UnimplementedLoginServiceServer) mustEmbedUnimplementedLoginServiceServer() {}
+
+// UnsafeLoginServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoginServiceServer will
+// result in compilation errors.
+type UnsafeLoginServiceServer interface {
+	mustEmbedUnimplementedLoginServiceServer()
+}
+
+func RegisterLoginServiceServer(s grpc.ServiceRegistrar, srv LoginServiceServer) {
+	s.RegisterService(&LoginService_ServiceDesc, srv)
+}
+
+func _LoginService_CreateHumanCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HumanCheckMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).CreateHumanCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/CreateHumanCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).CreateHumanCheck(ctx, req.(*HumanCheckMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_VerifyHumanCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyHumanCheckMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).VerifyHumanCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/VerifyHumanCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).VerifyHumanCheck(ctx, req.(*VerifyHumanCheckMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_GetCaptcha_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptchaMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).GetCaptcha(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/GetCaptcha",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).GetCaptcha(ctx, req.(*CaptchaMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).Login(ctx, req.(*LoginMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).Register(ctx, req.(*RegisterMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_PasswordLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordLoginMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).PasswordLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/PasswordLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).PasswordLogin(ctx, req.(*PasswordLoginMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ResetPassword(ctx, req.(*ResetPasswordMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_BindEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BindEmailMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).BindEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/BindEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).BindEmail(ctx, req.(*BindEmailMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RefreshToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RefreshToken(ctx, req.(*RefreshTokenMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RevokeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RevokeToken(ctx, req.(*RevokeTokenMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_VerifyToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTokenMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).VerifyToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/VerifyToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).VerifyToken(ctx, req.(*VerifyTokenMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_GetPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublicKeysMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).GetPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/GetPublicKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).GetPublicKeys(ctx, req.(*PublicKeysMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_EnrollTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollTotpMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).EnrollTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/EnrollTotp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).EnrollTotp(ctx, req.(*EnrollTotpMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ConfirmTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTotpMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ConfirmTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ConfirmTotp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ConfirmTotp(ctx, req.(*ConfirmTotpMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_DisableTotp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTotpMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).DisableTotp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/DisableTotp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).DisableTotp(ctx, req.(*DisableTotpMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_VerifyTwoFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTwoFactorMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).VerifyTwoFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/VerifyTwoFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).VerifyTwoFactor(ctx, req.(*VerifyTwoFactorMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListSessions(ctx, req.(*ListSessionsMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_KickDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickDeviceMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).KickDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/KickDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).KickDevice(ctx, req.(*KickDeviceMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_LogoutAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutAllMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).LogoutAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/LogoutAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).LogoutAll(ctx, req.(*LogoutAllMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListOidcProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOidcProvidersMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListOidcProviders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListOidcProviders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListOidcProviders(ctx, req.(*ListOidcProvidersMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_OidcAuthorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OidcAuthorizeMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).OidcAuthorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/OidcAuthorize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).OidcAuthorize(ctx, req.(*OidcAuthorizeMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_OidcCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OidcCallbackMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).OidcCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/OidcCallback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).OidcCallback(ctx, req.(*OidcCallbackMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIdentitiesMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListIdentities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListIdentities(ctx, req.(*ListIdentitiesMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_UnlinkIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkIdentityMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).UnlinkIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/UnlinkIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).UnlinkIdentity(ctx, req.(*UnlinkIdentityMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RegisterOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterOAuthClientMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RegisterOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RegisterOAuthClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RegisterOAuthClient(ctx, req.(*RegisterOAuthClientMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListOAuthClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOAuthClientsMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListOAuthClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListOAuthClients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListOAuthClients(ctx, req.(*ListOAuthClientsMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RotateOAuthClientSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthClientIdMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RotateOAuthClientSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RotateOAuthClientSecret",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RotateOAuthClientSecret(ctx, req.(*OAuthClientIdMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_DeleteOAuthClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthClientIdMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).DeleteOAuthClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/DeleteOAuthClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).DeleteOAuthClient(ctx, req.(*OAuthClientIdMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_GetOAuthConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthAuthorizeMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).GetOAuthConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/GetOAuthConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).GetOAuthConsent(ctx, req.(*OAuthAuthorizeMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ApproveOAuthConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveOAuthConsentMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ApproveOAuthConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ApproveOAuthConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ApproveOAuthConsent(ctx, req.(*ApproveOAuthConsentMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_OAuthToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthTokenMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).OAuthToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/OAuthToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).OAuthToken(ctx, req.(*OAuthTokenMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_IntrospectToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntrospectTokenMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).IntrospectToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/IntrospectToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).IntrospectToken(ctx, req.(*IntrospectTokenMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListOAuthConsents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOAuthConsentsMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListOAuthConsents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListOAuthConsents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListOAuthConsents(ctx, req.(*ListOAuthConsentsMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RevokeOAuthConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OAuthClientIdMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RevokeOAuthConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RevokeOAuthConsent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RevokeOAuthConsent(ctx, req.(*OAuthClientIdMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_QueryAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAuditEventsMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).QueryAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/QueryAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).QueryAuditEvents(ctx, req.(*QueryAuditEventsMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/AssignRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).AssignRole(ctx, req.(*AssignRoleMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRoleMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RevokeRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RevokeRole(ctx, req.(*RevokeRoleMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListUserRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserRolesMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListUserRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListUserRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListUserRoles(ctx, req.(*ListUserRolesMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ChangeMobile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeMobileMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ChangeMobile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ChangeMobile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ChangeMobile(ctx, req.(*ChangeMobileMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// LoginServiceServer is the server API for LoginService service.
-// All implementations must embed UnimplementedLoginServiceServer
-// for forward compatibility
-type LoginServiceServer interface {
-	GetCaptcha(context.Context, *CaptchaMessage) (*CaptchaResponse, error)
-	mustEmbedUnimplementedLoginServiceServer()
+func _LoginService_RequestAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestAccountDeletionMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RequestAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RequestAccountDeletion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RequestAccountDeletion(ctx, req.(*RequestAccountDeletionMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedLoginServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedLoginServiceServer struct {
+func _LoginService_CancelAccountDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAccountDeletionMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).CancelAccountDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/CancelAccountDeletion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).CancelAccountDeletion(ctx, req.(*CancelAccountDeletionMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedLoginServiceServer) GetCaptcha(context.Context, *CaptchaMessage) (*CaptchaResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetCaptcha not implemented")
+func _LoginService_ExportAccountData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportAccountDataMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ExportAccountData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ExportAccountData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ExportAccountData(ctx, req.(*ExportAccountDataMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLoginServiceServer) mustEmbedUnimplementedLoginServiceServer() {}
 
-// UnsafeLoginServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to LoginServiceServer will
-// result in compilation errors.
-type UnsafeLoginServiceServer interface {
-	mustEmbedUnimplementedLoginServiceServer()
+func _LoginService_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateServiceAccountMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/CreateServiceAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).CreateServiceAccount(ctx, req.(*CreateServiceAccountMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterLoginServiceServer(s grpc.ServiceRegistrar, srv LoginServiceServer) {
-	s.RegisterService(&LoginService_ServiceDesc, srv)
+func _LoginService_ListServiceAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServiceAccountsMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListServiceAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListServiceAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListServiceAccounts(ctx, req.(*ListServiceAccountsMessage))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _LoginService_GetCaptcha_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CaptchaMessage)
+func _LoginService_DeleteServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteServiceAccountMessage)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LoginServiceServer).GetCaptcha(ctx, in)
+		return srv.(LoginServiceServer).DeleteServiceAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/login.service.v1.LoginService/GetCaptcha",
+		FullMethod: "/login.service.v1.LoginService/DeleteServiceAccount",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LoginServiceServer).GetCaptcha(ctx, req.(*CaptchaMessage))
+		return srv.(LoginServiceServer).DeleteServiceAccount(ctx, req.(*DeleteServiceAccountMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).CreateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/CreateApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApiKeysMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).ListApiKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/ListApiKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).ListApiKeys(ctx, req.(*ListApiKeysMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RotateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKeyPrefixMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RotateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RotateApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RotateApiKey(ctx, req.(*ApiKeyPrefixMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_RevokeApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApiKeyPrefixMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).RevokeApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/RevokeApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).RevokeApiKey(ctx, req.(*ApiKeyPrefixMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoginService_VerifyApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyApiKeyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoginServiceServer).VerifyApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/login.service.v1.LoginService/VerifyApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoginServiceServer).VerifyApiKey(ctx, req.(*VerifyApiKeyMessage))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -95,10 +1663,206 @@ var LoginService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "login.service.v1.LoginService",
 	HandlerType: (*LoginServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateHumanCheck",
+			Handler:    _LoginService_CreateHumanCheck_Handler,
+		},
+		{
+			MethodName: "VerifyHumanCheck",
+			Handler:    _LoginService_VerifyHumanCheck_Handler,
+		},
 		{
 			MethodName: "GetCaptcha",
 			Handler:    _LoginService_GetCaptcha_Handler,
 		},
+		{
+			MethodName: "Login",
+			Handler:    _LoginService_Login_Handler,
+		},
+		{
+			MethodName: "Register",
+			Handler:    _LoginService_Register_Handler,
+		},
+		{
+			MethodName: "PasswordLogin",
+			Handler:    _LoginService_PasswordLogin_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _LoginService_ResetPassword_Handler,
+		},
+		{
+			MethodName: "BindEmail",
+			Handler:    _LoginService_BindEmail_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _LoginService_RefreshToken_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _LoginService_RevokeToken_Handler,
+		},
+		{
+			MethodName: "VerifyToken",
+			Handler:    _LoginService_VerifyToken_Handler,
+		},
+		{
+			MethodName: "GetPublicKeys",
+			Handler:    _LoginService_GetPublicKeys_Handler,
+		},
+		{
+			MethodName: "EnrollTotp",
+			Handler:    _LoginService_EnrollTotp_Handler,
+		},
+		{
+			MethodName: "ConfirmTotp",
+			Handler:    _LoginService_ConfirmTotp_Handler,
+		},
+		{
+			MethodName: "DisableTotp",
+			Handler:    _LoginService_DisableTotp_Handler,
+		},
+		{
+			MethodName: "VerifyTwoFactor",
+			Handler:    _LoginService_VerifyTwoFactor_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _LoginService_ListSessions_Handler,
+		},
+		{
+			MethodName: "KickDevice",
+			Handler:    _LoginService_KickDevice_Handler,
+		},
+		{
+			MethodName: "LogoutAll",
+			Handler:    _LoginService_LogoutAll_Handler,
+		},
+		{
+			MethodName: "ListOidcProviders",
+			Handler:    _LoginService_ListOidcProviders_Handler,
+		},
+		{
+			MethodName: "OidcAuthorize",
+			Handler:    _LoginService_OidcAuthorize_Handler,
+		},
+		{
+			MethodName: "OidcCallback",
+			Handler:    _LoginService_OidcCallback_Handler,
+		},
+		{
+			MethodName: "ListIdentities",
+			Handler:    _LoginService_ListIdentities_Handler,
+		},
+		{
+			MethodName: "UnlinkIdentity",
+			Handler:    _LoginService_UnlinkIdentity_Handler,
+		},
+		{
+			MethodName: "RegisterOAuthClient",
+			Handler:    _LoginService_RegisterOAuthClient_Handler,
+		},
+		{
+			MethodName: "ListOAuthClients",
+			Handler:    _LoginService_ListOAuthClients_Handler,
+		},
+		{
+			MethodName: "RotateOAuthClientSecret",
+			Handler:    _LoginService_RotateOAuthClientSecret_Handler,
+		},
+		{
+			MethodName: "DeleteOAuthClient",
+			Handler:    _LoginService_DeleteOAuthClient_Handler,
+		},
+		{
+			MethodName: "GetOAuthConsent",
+			Handler:    _LoginService_GetOAuthConsent_Handler,
+		},
+		{
+			MethodName: "ApproveOAuthConsent",
+			Handler:    _LoginService_ApproveOAuthConsent_Handler,
+		},
+		{
+			MethodName: "OAuthToken",
+			Handler:    _LoginService_OAuthToken_Handler,
+		},
+		{
+			MethodName: "IntrospectToken",
+			Handler:    _LoginService_IntrospectToken_Handler,
+		},
+		{
+			MethodName: "ListOAuthConsents",
+			Handler:    _LoginService_ListOAuthConsents_Handler,
+		},
+		{
+			MethodName: "RevokeOAuthConsent",
+			Handler:    _LoginService_RevokeOAuthConsent_Handler,
+		},
+		{
+			MethodName: "QueryAuditEvents",
+			Handler:    _LoginService_QueryAuditEvents_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _LoginService_AssignRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _LoginService_RevokeRole_Handler,
+		},
+		{
+			MethodName: "ListUserRoles",
+			Handler:    _LoginService_ListUserRoles_Handler,
+		},
+		{
+			MethodName: "ChangeMobile",
+			Handler:    _LoginService_ChangeMobile_Handler,
+		},
+		{
+			MethodName: "RequestAccountDeletion",
+			Handler:    _LoginService_RequestAccountDeletion_Handler,
+		},
+		{
+			MethodName: "CancelAccountDeletion",
+			Handler:    _LoginService_CancelAccountDeletion_Handler,
+		},
+		{
+			MethodName: "ExportAccountData",
+			Handler:    _LoginService_ExportAccountData_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _LoginService_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "ListServiceAccounts",
+			Handler:    _LoginService_ListServiceAccounts_Handler,
+		},
+		{
+			MethodName: "DeleteServiceAccount",
+			Handler:    _LoginService_DeleteServiceAccount_Handler,
+		},
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _LoginService_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "ListApiKeys",
+			Handler:    _LoginService_ListApiKeys_Handler,
+		},
+		{
+			MethodName: "RotateApiKey",
+			Handler:    _LoginService_RotateApiKey_Handler,
+		},
+		{
+			MethodName: "RevokeApiKey",
+			Handler:    _LoginService_RevokeApiKey_Handler,
+		},
+		{
+			MethodName: "VerifyApiKey",
+			Handler:    _LoginService_VerifyApiKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "login_service.proto",
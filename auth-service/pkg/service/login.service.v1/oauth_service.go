@@ -0,0 +1,179 @@
+package login_service_v1
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	"github.com/MortalSC/IM-System/auth-service/internal/oauth"
+)
+
+// RegisterOAuthClient 为账号注册第三方应用（机器人、集成），客户端密钥只在此时返回一次
+func (ls *LoginService) RegisterOAuthClient(ctx context.Context, msg *RegisterOAuthClientMessage) (*RegisterOAuthClientResponse, error) {
+	client, secret, err := ls.oauth.RegisterClient(ctx, msg.UserId, &oauth.ClientSpec{
+		Name:         msg.Name,
+		RedirectUris: msg.RedirectUris,
+		Scopes:       msg.Scopes,
+		GrantTypes:   msg.GrantTypes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RegisterOAuthClientResponse{Client: toOAuthClientMessage(client), ClientSecret: secret}, nil
+}
+
+// ListOAuthClients 列出账号注册的应用
+func (ls *LoginService) ListOAuthClients(ctx context.Context, msg *ListOAuthClientsMessage) (*ListOAuthClientsResponse, error) {
+	list, err := ls.oauth.ListClients(ctx, msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ListOAuthClientsResponse{}
+	for _, c := range list {
+		rsp.Clients = append(rsp.Clients, toOAuthClientMessage(c))
+	}
+	return rsp, nil
+}
+
+// RotateOAuthClientSecret 重置应用的客户端密钥
+func (ls *LoginService) RotateOAuthClientSecret(ctx context.Context, msg *OAuthClientIdMessage) (*RotateOAuthClientSecretResponse, error) {
+	secret, err := ls.oauth.RotateSecret(ctx, msg.UserId, msg.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	return &RotateOAuthClientSecretResponse{ClientSecret: secret}, nil
+}
+
+// DeleteOAuthClient 删除应用，用户授权和已签发的令牌一并失效
+func (ls *LoginService) DeleteOAuthClient(ctx context.Context, msg *OAuthClientIdMessage) (*DeleteOAuthClientResponse, error) {
+	if err := ls.oauth.DeleteClient(ctx, msg.UserId, msg.ClientId); err != nil {
+		return nil, err
+	}
+	return &DeleteOAuthClientResponse{}, nil
+}
+
+// GetOAuthConsent 校验授权请求，返回授权页需要展示的应用和授权范围
+func (ls *LoginService) GetOAuthConsent(ctx context.Context, msg *OAuthAuthorizeMessage) (*GetOAuthConsentResponse, error) {
+	auth, err := ls.oauth.Authorize(ctx, msg.UserId, toAuthorizeRequest(msg))
+	if err != nil {
+		return nil, err
+	}
+	rsp := &GetOAuthConsentResponse{
+		ClientId:    auth.Client.ClientId,
+		ClientName:  auth.Client.Name,
+		RedirectUri: auth.RedirectUri,
+		NeedConsent: auth.NeedConsent,
+	}
+	for _, s := range auth.Scopes {
+		rsp.Scopes = append(rsp.Scopes, &OAuthScopeMessage{
+			Scope:       s,
+			Description: oauth.DescribeScope(s),
+			Granted:     auth.IsGranted(s),
+		})
+	}
+	return rsp, nil
+}
+
+// ApproveOAuthConsent 用户确认或拒绝授权，返回重定向回应用的地址（同意时携带授权码）
+func (ls *LoginService) ApproveOAuthConsent(ctx context.Context, msg *ApproveOAuthConsentMessage) (*ApproveOAuthConsentResponse, error) {
+	if msg.Request == nil {
+		msg.Request = &OAuthAuthorizeMessage{}
+	}
+	audit.SetActor(ctx, msg.Request.UserId)
+	audit.SetClient(ctx, msg.Request.ClientId)
+	redirectUrl, err := ls.oauth.Approve(ctx, msg.Request.UserId, toAuthorizeRequest(msg.Request), msg.Approved)
+	if err != nil {
+		return nil, err
+	}
+	return &ApproveOAuthConsentResponse{RedirectUrl: redirectUrl}, nil
+}
+
+// OAuthToken 令牌端点，支持 authorization_code、client_credentials 和 refresh_token
+func (ls *LoginService) OAuthToken(ctx context.Context, msg *OAuthTokenMessage) (*OAuthTokenResponse, error) {
+	t, err := ls.oauth.Token(ctx, &oauth.TokenRequest{
+		GrantType:    msg.GrantType,
+		ClientId:     msg.ClientId,
+		ClientSecret: msg.ClientSecret,
+		Code:         msg.Code,
+		RedirectUri:  msg.RedirectUri,
+		CodeVerifier: msg.CodeVerifier,
+		RefreshToken: msg.RefreshToken,
+		Scope:        msg.Scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthTokenResponse{
+		AccessToken:     t.AccessToken,
+		AccessTokenExp:  t.AccessTokenExp,
+		RefreshToken:    t.RefreshToken,
+		RefreshTokenExp: t.RefreshTokenExp,
+		Scope:           t.Scope,
+	}, nil
+}
+
+// IntrospectToken 令牌内省（RFC 7662），应用只能查询自己获得的令牌
+func (ls *LoginService) IntrospectToken(ctx context.Context, msg *IntrospectTokenMessage) (*IntrospectTokenResponse, error) {
+	in, err := ls.oauth.Introspect(ctx, msg.ClientId, msg.ClientSecret, msg.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &IntrospectTokenResponse{
+		Active:    in.Active,
+		ClientId:  in.ClientId,
+		UserId:    in.UserId,
+		Scope:     in.Scope,
+		TokenType: in.TokenType,
+		TokenId:   in.TokenId,
+		IssuedAt:  in.IssuedAt,
+		ExpireAt:  in.ExpireAt,
+	}, nil
+}
+
+// ListOAuthConsents 列出账号授权过的应用
+func (ls *LoginService) ListOAuthConsents(ctx context.Context, msg *ListOAuthConsentsMessage) (*ListOAuthConsentsResponse, error) {
+	list, err := ls.oauth.ListConsents(ctx, msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ListOAuthConsentsResponse{}
+	for _, c := range list {
+		rsp.Consents = append(rsp.Consents, &OAuthConsentMessage{
+			ClientId:   c.ClientId,
+			ClientName: c.ClientName,
+			Scopes:     c.Scopes,
+			CreateTime: c.CreateTime,
+			UpdateTime: c.UpdateTime,
+		})
+	}
+	return rsp, nil
+}
+
+// RevokeOAuthConsent 撤销对应用的授权，该应用代表账号持有的令牌全部失效
+func (ls *LoginService) RevokeOAuthConsent(ctx context.Context, msg *OAuthClientIdMessage) (*RevokeOAuthConsentResponse, error) {
+	if err := ls.oauth.RevokeConsent(ctx, msg.UserId, msg.ClientId); err != nil {
+		return nil, err
+	}
+	return &RevokeOAuthConsentResponse{}, nil
+}
+
+func toOAuthClientMessage(c *data.OAuthClient) *OAuthClientMessage {
+	return &OAuthClientMessage{
+		ClientId:     c.ClientId,
+		Name:         c.Name,
+		RedirectUris: c.RedirectUris,
+		Scopes:       c.Scopes,
+		GrantTypes:   c.GrantTypes,
+		CreateTime:   c.CreateTime,
+	}
+}
+
+func toAuthorizeRequest(msg *OAuthAuthorizeMessage) *oauth.AuthorizeRequest {
+	return &oauth.AuthorizeRequest{
+		ClientId:            msg.ClientId,
+		RedirectUri:         msg.RedirectUri,
+		Scope:               msg.Scope,
+		State:               msg.State,
+		CodeChallenge:       msg.CodeChallenge,
+		CodeChallengeMethod: msg.CodeChallengeMethod,
+	}
+}
@@ -0,0 +1,257 @@
+package login_service_v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/oidc"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"strings"
+	"time"
+)
+
+// ListOidcProviders 列出已配置的身份提供方，供登录页展示第三方登录入口
+func (ls *LoginService) ListOidcProviders(ctx context.Context, msg *ListOidcProvidersMessage) (*ListOidcProvidersResponse, error) {
+	rsp := &ListOidcProvidersResponse{}
+	for _, p := range ls.oidc.Providers() {
+		rsp.Providers = append(rsp.Providers, &OidcProviderMessage{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return rsp, nil
+}
+
+// OidcAuthorize 发起第三方登录或绑定，返回身份提供方的授权地址
+func (ls *LoginService) OidcAuthorize(ctx context.Context, msg *OidcAuthorizeMessage) (*OidcAuthorizeResponse, error) {
+	authorizeUrl, state, binding, err := ls.oidc.Begin(ctx, &oidc.Flow{
+		Provider:   msg.Provider,
+		BindUserId: msg.BindUserId,
+		DeviceId:   msg.DeviceId,
+		DeviceName: msg.DeviceName,
+		Platform:   msg.Platform,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OidcAuthorizeResponse{AuthorizeUrl: authorizeUrl, State: state, Binding: binding}, nil
+}
+
+// OidcCallback 处理身份提供方回调：绑定流程将身份关联到发起绑定的账号，登录流程按关联的账号登录
+// 身份未关联时依次尝试按已验证的手机号、邮箱关联已有账号，仍未找到时按配置自动注册
+func (ls *LoginService) OidcCallback(ctx context.Context, msg *OidcCallbackMessage) (*OidcCallbackResponse, error) {
+	// 1. 用户拒绝授权等情况下身份提供方只回传 error
+	if msg.Error != "" {
+		ls.oidc.Cancel(ctx, msg.State)
+		libLog.IMLog.Warn(fmt.Sprintf("身份提供方回调错误: %s", msg.Error))
+		return nil, libErrors.GrpcError(errs.ErrOidcProviderFail, "第三方登录未完成，请重试")
+	}
+
+	// 2. 换取并校验 ID 令牌
+	flow, claims, err := ls.oidc.Finish(ctx, msg.Provider, msg.State, msg.Code, msg.Binding)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := ls.identities.Find(ctx, flow.Provider, claims.Subject)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("OidcCallback 查询身份关联出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	// 3. 绑定流程
+	if flow.BindUserId != 0 {
+		audit.SetActor(ctx, flow.BindUserId)
+		if identity != nil && identity.UserId != flow.BindUserId {
+			return nil, libErrors.GrpcError(errs.ErrOidcIdentityExists, "该第三方账号已关联其他账号")
+		}
+		if identity == nil {
+			if err = ls.link(ctx, flow.BindUserId, flow.Provider, claims); err != nil {
+				return nil, err
+			}
+		}
+		return &OidcCallbackResponse{Provider: flow.Provider, Bound: true}, nil
+	}
+
+	// 4. 登录流程
+	var user *data.User
+	if identity != nil {
+		if user, err = ls.userRepo.FindById(ctx, identity.UserId); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("OidcCallback 查询账号出错，原因: %v", err))
+			return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+		}
+		if user == nil {
+			return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+		}
+	} else {
+		if user, err = ls.matchOidcUser(ctx, ls.oidc.Provider(flow.Provider), claims); err != nil {
+			return nil, err
+		}
+		if err = ls.link(ctx, user.Id, flow.Provider, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	user.LastLoginTime = time.Now().UnixMilli()
+	if err = ls.userRepo.Save(ctx, user); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("OidcCallback 更新账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	login, err := ls.signIn(ctx, "OidcCallback", user, &device{
+		Id:       flow.DeviceId,
+		Name:     flow.DeviceName,
+		Platform: flow.Platform,
+		Ip:       msg.Ip,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OidcCallbackResponse{Provider: flow.Provider, Login: login}, nil
+}
+
+// ListIdentities 列出账号关联的第三方身份
+func (ls *LoginService) ListIdentities(ctx context.Context, msg *ListIdentitiesMessage) (*ListIdentitiesResponse, error) {
+	list, err := ls.identities.ListByUser(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ListIdentities 查询身份关联出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	rsp := &ListIdentitiesResponse{}
+	for _, i := range list {
+		rsp.Identities = append(rsp.Identities, &IdentityMessage{
+			Provider:   i.Provider,
+			Subject:    i.Subject,
+			Email:      i.Email,
+			CreateTime: i.CreateTime,
+		})
+	}
+	return rsp, nil
+}
+
+// UnlinkIdentity 解除账号与身份提供方的关联，账号没有手机号、密码或其他第三方身份时不允许解除
+func (ls *LoginService) UnlinkIdentity(ctx context.Context, msg *UnlinkIdentityMessage) (*UnlinkIdentityResponse, error) {
+	user, err := ls.userRepo.FindById(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("UnlinkIdentity 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	list, err := ls.identities.ListByUser(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("UnlinkIdentity 查询身份关联出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user.Mobile == "" && user.PasswordHash == "" && len(list) <= 1 {
+		return nil, libErrors.GrpcError(errs.ErrOidcLastLoginMethod, "这是账号唯一的登录方式，请先绑定手机号或设置密码")
+	}
+
+	ok, err := ls.identities.Delete(ctx, msg.UserId, msg.Provider)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("UnlinkIdentity 删除身份关联出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if !ok {
+		return nil, libErrors.GrpcError(errs.ErrOidcIdentityNotExist, "账号未关联该第三方账号")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 解除关联身份提供方 %s", msg.UserId, msg.Provider))
+	return &UnlinkIdentityResponse{}, nil
+}
+
+// matchOidcUser 为未关联的第三方身份查找或创建账号
+func (ls *LoginService) matchOidcUser(ctx context.Context, p *oidc.ProviderConfig, claims *oidc.Claims) (*data.User, error) {
+	var mobile, email string
+	if p.TrustPhone && bool(claims.PhoneNumberVerified) {
+		if number, err := ls.phones.Parse(claims.PhoneNumber); err == nil {
+			mobile = number.E164
+		}
+	}
+	if p.TrustEmail && bool(claims.EmailVerified) && claims.Email != "" {
+		email = strings.ToLower(claims.Email)
+	}
+
+	// 1. 按已验证的手机号、邮箱关联已有账号
+	user, byEmail, err := ls.findOidcUser(ctx, mobile, email)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("OidcCallback 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	// 账号邮箱未经本系统验证时可能是他人抢注的，不能仅凭邮箱相同就关联，需要登录后手动绑定
+	if byEmail && !user.EmailVerified {
+		return nil, libErrors.GrpcError(errs.ErrOidcAccountNotLinked, "该第三方账号未关联，请先登录后在账号设置中绑定")
+	}
+	if user != nil {
+		return user, nil
+	}
+	if !p.AutoRegister {
+		return nil, libErrors.GrpcError(errs.ErrOidcAccountNotLinked, "该第三方账号未关联，请先登录后在账号设置中绑定")
+	}
+
+	// 2. 创建新账号，手机号和邮箱只在可信时写入，写入的邮箱已由身份提供方验证
+	now := time.Now().UnixMilli()
+	user = &data.User{
+		Name:          oidcDisplayName(p, claims),
+		Avatar:        claims.Picture,
+		Email:         email,
+		EmailVerified: email != "",
+		Mobile:        mobile,
+		CreateTime:    now,
+		LastLoginTime: now,
+	}
+	if err = ls.userRepo.Create(ctx, user); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("OidcCallback 创建账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("身份提供方 %s 的用户 %s 自动注册账号 %d", p.Name, claims.Subject, user.Id))
+	return user, nil
+}
+
+// findOidcUser 先按手机号再按邮箱查找账号，byEmail 表示账号是按邮箱找到的
+func (ls *LoginService) findOidcUser(ctx context.Context, mobile, email string) (user *data.User, byEmail bool, err error) {
+	if mobile != "" {
+		if user, err = ls.userRepo.FindByMobile(ctx, mobile); err != nil || user != nil {
+			return user, false, err
+		}
+	}
+	if email != "" {
+		user, err = ls.userRepo.FindByEmail(ctx, email)
+		return user, user != nil, err
+	}
+	return nil, false, nil
+}
+
+// link 关联第三方身份与账号
+func (ls *LoginService) link(ctx context.Context, userId int64, provider string, claims *oidc.Claims) error {
+	err := ls.identities.Create(ctx, &data.Identity{
+		UserId:     userId,
+		Provider:   provider,
+		Subject:    claims.Subject,
+		Email:      claims.Email,
+		CreateTime: time.Now().UnixMilli(),
+	})
+	switch {
+	case errors.Is(err, repo.ErrIdentityExists):
+		return libErrors.GrpcError(errs.ErrOidcIdentityExists, "该第三方账号已关联其他账号")
+	case errors.Is(err, repo.ErrProviderLinked):
+		return libErrors.GrpcError(errs.ErrOidcProviderLinked, "账号已关联该身份提供方的其他账号，请先解除关联")
+	case err != nil:
+		libLog.IMLog.Error(fmt.Sprintf("关联第三方身份出错，原因: %v", err))
+		return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 关联身份提供方 %s 的用户 %s", userId, provider, claims.Subject))
+	return nil
+}
+
+func oidcDisplayName(p *oidc.ProviderConfig, claims *oidc.Claims) string {
+	switch {
+	case claims.Name != "":
+		return claims.Name
+	case claims.PreferredUsername != "":
+		return claims.PreferredUsername
+	case p.DisplayName != "":
+		return p.DisplayName + "用户"
+	}
+	return p.Name + "用户"
+}
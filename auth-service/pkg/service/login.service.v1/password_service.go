@@ -0,0 +1,259 @@
+package login_service_v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/password"
+	"github.com/MortalSC/IM-System/auth-service/internal/phone"
+	"github.com/MortalSC/IM-System/auth-service/internal/repo"
+	"github.com/MortalSC/IM-System/auth-service/internal/utils"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Register 使用用户名 + 密码注册，需通过手机或邮箱验证码验证其中之一，注册成功后直接登录
+// 填写手机号时验证码发送到手机，邮箱仅作为可选资料且未验证；未填写手机号时验证码发送到邮箱，邮箱标记为已验证
+func (ls *LoginService) Register(ctx context.Context, msg *RegisterMessage) (*LoginResponse, error) {
+	// 1. 校验参数
+	if !utils.VerifyUsername(msg.Username) {
+		return nil, libErrors.GrpcError(errs.ErrNoLegalUsername, "用户名需为字母开头的 4~20 位字母、数字或下划线")
+	}
+	email := strings.ToLower(msg.Email)
+	if email != "" && !utils.VerifyEmail(email) {
+		return nil, libErrors.GrpcError(errs.ErrNoLegalEmail, "邮箱不合法")
+	}
+	var mobile, national, target string
+	switch {
+	case msg.Mobile != "":
+		number, err := ls.parseMobile(msg.Mobile)
+		if err != nil {
+			return nil, err
+		}
+		mobile, national, target = number.E164, number.National, number.E164
+	case email != "":
+		target = email
+	default:
+		return nil, libErrors.GrpcError(errs.ErrNoLegalMobile, "手机号和邮箱至少填写一个")
+	}
+	if err := ls.checkPassword(msg.Password, msg.Username, national); err != nil {
+		return nil, err
+	}
+
+	// 2. 校验并消费验证码
+	if err := ls.captcha.Verify(ctx, target, msg.Captcha); err != nil {
+		return nil, err
+	}
+
+	// 3. 创建账号，手机号、用户名、邮箱的唯一性由存储层保证
+	hash, err := ls.password.Hash(msg.Password)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("Register 计算密码哈希出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	now := time.Now().UnixMilli()
+	user := &data.User{
+		Name:          msg.Username,
+		Username:      msg.Username,
+		Email:         email,
+		EmailVerified: target == email,
+		Mobile:        mobile,
+		PasswordHash:  hash,
+		CreateTime:    now,
+		LastLoginTime: now,
+	}
+	switch err = ls.userRepo.Create(ctx, user); {
+	case errors.Is(err, repo.ErrMobileExists):
+		return nil, libErrors.GrpcError(errs.ErrMobileExists, "手机号已注册，请直接登录或找回密码")
+	case errors.Is(err, repo.ErrUsernameExists):
+		return nil, libErrors.GrpcError(errs.ErrUsernameExists, "用户名已被使用")
+	case errors.Is(err, repo.ErrEmailExists):
+		return nil, libErrors.GrpcError(errs.ErrEmailExists, "邮箱已被使用")
+	case err != nil:
+		libLog.IMLog.Error(fmt.Sprintf("Register 创建账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	// 4. 签发令牌并记录设备会话
+	return ls.signIn(ctx, "Register", user, &device{
+		Id:       msg.DeviceId,
+		Name:     msg.DeviceName,
+		Platform: msg.Platform,
+		Ip:       msg.Ip,
+	})
+}
+
+// PasswordLogin 使用用户名、邮箱或手机号 + 密码登录，连续错误达到上限后锁定账号
+func (ls *LoginService) PasswordLogin(ctx context.Context, msg *PasswordLoginMessage) (*LoginResponse, error) {
+	// 1. 查找账号，账号不存在与密码错误返回相同的错误
+	user, err := ls.findAccount(ctx, msg.Account)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("PasswordLogin 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		_, _ = ls.password.Verify(msg.Password, "")
+		return nil, libErrors.GrpcError(errs.ErrAccountOrPassword, "账号或密码错误")
+	}
+	audit.SetActor(ctx, user.Id)
+
+	// 2. 锁定期间不校验密码
+	until, err := ls.password.LockedUntil(ctx, user.Id)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("PasswordLogin 查询锁定状态出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if time.Now().Before(until) {
+		return nil, lockedError(until)
+	}
+
+	// 3. 校验密码，错误时计数
+	ok, err := ls.password.Verify(msg.Password, user.PasswordHash)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("PasswordLogin 校验密码出错，账号: %d，原因: %v", user.Id, err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if !ok {
+		remaining, err := ls.password.Fail(ctx, user.Id)
+		if err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("PasswordLogin 记录密码错误次数出错，原因: %v", err))
+			return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+		}
+		if remaining == 0 {
+			libLog.IMLog.Warn(fmt.Sprintf("账号 %d 密码错误次数过多，已锁定，IP: %s", user.Id, msg.Ip))
+			return nil, libErrors.GrpcError(errs.ErrAccountLocked, "密码错误次数过多，账号已锁定，请稍后再试或找回密码")
+		}
+		return nil, libErrors.GrpcError(errs.ErrAccountOrPassword, "账号或密码错误")
+	}
+
+	// 4. 登录成功，清除错误计数，哈希参数变更时顺便升级
+	if err = ls.password.Unlock(ctx, user.Id); err != nil {
+		libLog.IMLog.Warn(fmt.Sprintf("PasswordLogin 清除密码错误次数出错，原因: %v", err))
+	}
+	if ls.password.NeedsRehash(user.PasswordHash) {
+		if hash, err := ls.password.Hash(msg.Password); err == nil {
+			user.PasswordHash = hash
+		}
+	}
+	user.LastLoginTime = time.Now().UnixMilli()
+	if err = ls.userRepo.Save(ctx, user); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("PasswordLogin 更新账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	// 5. 签发令牌并记录设备会话
+	return ls.signIn(ctx, "PasswordLogin", user, &device{
+		Id:       msg.DeviceId,
+		Name:     msg.DeviceName,
+		Platform: msg.Platform,
+		Ip:       msg.Ip,
+	})
+}
+
+// ResetPassword 通过手机验证码或已验证邮箱的验证码重置密码，也可用于验证码登录的账号首次设置密码
+// 重置成功后解除锁定并下线所有设备
+func (ls *LoginService) ResetPassword(ctx context.Context, msg *ResetPasswordMessage) (*ResetPasswordResponse, error) {
+	// 1. 校验参数，未填写手机号时按邮箱重置
+	var national, target string
+	var err error
+	byMobile := msg.Mobile != "" || msg.Email == ""
+	if byMobile {
+		var number *phone.Number
+		if number, err = ls.parseMobile(msg.Mobile); err != nil {
+			return nil, err
+		}
+		national, target = number.National, number.E164
+	} else {
+		if target, err = parseEmail(msg.Email); err != nil {
+			return nil, err
+		}
+	}
+	// 先校验密码强度，避免弱密码白白消耗验证码；与用户名相关的规则在查到账号后再校验
+	if err = ls.checkPassword(msg.Password, national); err != nil {
+		return nil, err
+	}
+
+	// 2. 先校验并消费验证码，再查询账号，避免未持有验证码的调用方借此探测账号是否存在
+	if err = ls.captcha.Verify(ctx, target, msg.Captcha); err != nil {
+		return nil, err
+	}
+	var user *data.User
+	if byMobile {
+		user, err = ls.userRepo.FindByMobile(ctx, target)
+	} else {
+		user, err = ls.userRepo.FindByEmail(ctx, target)
+	}
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ResetPassword 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	audit.SetActor(ctx, user.Id)
+	// 未验证的邮箱可能属于他人，不能用于找回密码
+	if target == user.Email && !user.EmailVerified {
+		return nil, libErrors.GrpcError(errs.ErrEmailNotVerified, "邮箱未验证，无法用于找回密码")
+	}
+	if err = ls.checkPassword(msg.Password, user.Username, national); err != nil {
+		return nil, err
+	}
+
+	// 3. 保存新密码
+	if user.PasswordHash, err = ls.password.Hash(msg.Password); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ResetPassword 计算密码哈希出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if err = ls.userRepo.Save(ctx, user); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ResetPassword 更新账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+
+	// 4. 解除锁定，旧密码登录的设备全部下线
+	if err = ls.password.Unlock(ctx, user.Id); err != nil {
+		libLog.IMLog.Warn(fmt.Sprintf("ResetPassword 解除锁定出错，原因: %v", err))
+	}
+	if _, err = ls.kickAll(ctx, user.Id, ""); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ResetPassword 下线设备出错，账号: %d，原因: %v", user.Id, err))
+	}
+	return &ResetPasswordResponse{}, nil
+}
+
+// findAccount 按账号格式查找：包含 @ 视为邮箱，以数字或 + 开头视为手机号（用户名必须以字母开头），其余视为用户名
+func (ls *LoginService) findAccount(ctx context.Context, account string) (*data.User, error) {
+	switch {
+	case account == "":
+		return nil, nil
+	case strings.Contains(account, "@"):
+		return ls.userRepo.FindByEmail(ctx, account)
+	case account[0] == '+' || (account[0] >= '0' && account[0] <= '9'):
+		number, err := ls.phones.Parse(account)
+		if err != nil {
+			return nil, nil
+		}
+		return ls.userRepo.FindByMobile(ctx, number.E164)
+	default:
+		return ls.userRepo.FindByUsername(ctx, account)
+	}
+}
+
+// checkPassword 按强度策略校验密码
+func (ls *LoginService) checkPassword(pwd string, identities ...string) error {
+	err := ls.password.Check(pwd, identities...)
+	var weak *password.WeakError
+	if errors.As(err, &weak) {
+		return libErrors.GrpcError(errs.ErrPasswordWeak, weak.Reason)
+	}
+	return err
+}
+
+func lockedError(until time.Time) error {
+	minutes := int(math.Ceil(time.Until(until).Minutes()))
+	return libErrors.GrpcError(errs.ErrAccountLocked, fmt.Sprintf("密码错误次数过多，账号已锁定，请 %d 分钟后再试或找回密码", minutes))
+}
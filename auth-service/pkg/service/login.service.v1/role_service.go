@@ -0,0 +1,127 @@
+package login_service_v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	"github.com/MortalSC/IM-System/auth-service/internal/data"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/role"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/rbac"
+)
+
+// 需要权限检查的方法全名
+const (
+	methodQueryAuditEvents = "/login.service.v1.LoginService/QueryAuditEvents"
+	methodListUserRoles    = "/login.service.v1.LoginService/ListUserRoles"
+	methodAssignRole       = "/login.service.v1.LoginService/AssignRole"
+	methodRevokeRole       = "/login.service.v1.LoginService/RevokeRole"
+)
+
+// DeclarePermissions 声明管理类方法需要的权限，其余方法由 api-center 完成认证后调用，不做权限检查
+// 分配和收回角色的目标范围就是请求中的范围，租户管理员只能管理本租户内的角色
+func (ls *LoginService) DeclarePermissions(e *rbac.GrpcEnforcer) {
+	e.Require(methodQueryAuditEvents, role.PermAuditRead)
+	e.Require(methodListUserRoles, role.PermRolesManage)
+	e.Require(methodAssignRole, role.PermRolesManage, roleScope)
+	e.Require(methodRevokeRole, role.PermRolesManage, roleScope)
+}
+
+// Subject 从 metadata 中透传的访问令牌确定调用方的角色，第三方应用令牌不携带角色
+func (ls *LoginService) Subject(ctx context.Context) ([]rbac.Grant, error) {
+	t := rbac.IncomingBearer(ctx)
+	if t == "" {
+		return nil, libErrors.GrpcError(errs.ErrTokenInvalid, "未登录")
+	}
+	claims, err := ls.tokens.Verify(ctx, t)
+	if err != nil {
+		return nil, tokenError("Subject", err)
+	}
+	audit.SetOperator(ctx, claims.UserId)
+	if claims.ClientId != "" {
+		return nil, nil
+	}
+	return rbac.ParseGrants(claims.Roles), nil
+}
+
+// PermissionDenied 权限不足时返回的错误
+func PermissionDenied(method, perm string) error {
+	return libErrors.GrpcError(errs.ErrPermissionDenied, "权限不足: "+perm)
+}
+
+// roleScope 分配角色请求中的目标范围，全局角色对应全局范围
+// 范围不合法时按全局范围检查，通过检查后由角色管理返回参数错误
+func roleScope(ctx context.Context, req any) (rbac.Scope, bool) {
+	r, ok := req.(interface{ GetScope() string })
+	if !ok {
+		return rbac.Scope{}, false
+	}
+	sc, err := rbac.ParseScope(r.GetScope())
+	if err != nil {
+		return rbac.Global(), true
+	}
+	return sc, true
+}
+
+// AssignRole 为账号分配角色，新角色在该账号下次登录或刷新令牌后生效
+func (ls *LoginService) AssignRole(ctx context.Context, msg *AssignRoleMessage) (*AssignRoleResponse, error) {
+	operatorId, err := ls.operatorId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ls.userRepo.FindById(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("AssignRole 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	r, err := ls.roles.Assign(ctx, rbac.GrantsFromContext(ctx), operatorId, msg.UserId, msg.Role, msg.Scope)
+	if err != nil {
+		return nil, err
+	}
+	return &AssignRoleResponse{Role: toUserRoleMessage(r)}, nil
+}
+
+// RevokeRole 收回账号的角色，已签发的访问令牌在过期前仍带有该角色
+func (ls *LoginService) RevokeRole(ctx context.Context, msg *RevokeRoleMessage) (*RevokeRoleResponse, error) {
+	if err := ls.roles.Revoke(ctx, rbac.GrantsFromContext(ctx), msg.UserId, msg.Role, msg.Scope); err != nil {
+		return nil, err
+	}
+	return &RevokeRoleResponse{}, nil
+}
+
+// ListUserRoles 查询账号的全部角色
+func (ls *LoginService) ListUserRoles(ctx context.Context, msg *ListUserRolesMessage) (*ListUserRolesResponse, error) {
+	list, err := ls.roles.List(ctx, msg.UserId)
+	if err != nil {
+		return nil, err
+	}
+	rsp := &ListUserRolesResponse{Roles: make([]*UserRoleMessage, 0, len(list))}
+	for _, r := range list {
+		rsp.Roles = append(rsp.Roles, toUserRoleMessage(r))
+	}
+	return rsp, nil
+}
+
+// operatorId 执行管理操作的账号，权限检查已校验过令牌，这里只取出账号 ID
+func (ls *LoginService) operatorId(ctx context.Context) (int64, error) {
+	claims, err := ls.tokens.Verify(ctx, rbac.IncomingBearer(ctx))
+	if err != nil {
+		return 0, tokenError("operatorId", err)
+	}
+	return claims.UserId, nil
+}
+
+func toUserRoleMessage(r *data.UserRole) *UserRoleMessage {
+	return &UserRoleMessage{
+		UserId:     r.UserId,
+		Role:       r.Role,
+		Scope:      r.Scope,
+		GrantedBy:  r.GrantedBy,
+		CreateTime: r.CreateTime,
+	}
+}
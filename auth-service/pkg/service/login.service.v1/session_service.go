@@ -0,0 +1,145 @@
+package login_service_v1
+
+import (
+	"context"
+	"fmt"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/session"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"time"
+)
+
+// ListSessions 列出账号当前在线的设备
+func (ls *LoginService) ListSessions(ctx context.Context, msg *ListSessionsMessage) (*ListSessionsResponse, error) {
+	sessions, err := ls.sessions.List(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("ListSessions 查询会话出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	rsp := &ListSessionsResponse{}
+	for _, sess := range sessions {
+		rsp.Sessions = append(rsp.Sessions, &SessionMessage{
+			DeviceId:   sess.DeviceId,
+			DeviceName: sess.DeviceName,
+			Platform:   sess.Platform,
+			Ip:         sess.Ip,
+			LoginTime:  sess.LoginTime,
+			LastSeen:   sess.LastSeen,
+			Current:    sess.DeviceId == msg.DeviceId,
+		})
+	}
+	return rsp, nil
+}
+
+// KickDevice 下线账号的某个设备，该设备的访问令牌和刷新令牌立即失效
+func (ls *LoginService) KickDevice(ctx context.Context, msg *KickDeviceMessage) (*KickDeviceResponse, error) {
+	sess, err := ls.sessions.Remove(ctx, msg.UserId, msg.DeviceId)
+	if err == nil && sess != nil {
+		err = ls.revokeSession(ctx, sess)
+	}
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("KickDevice 下线设备出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if sess == nil {
+		return nil, libErrors.GrpcError(errs.ErrSessionNotExist, "设备不存在或已下线")
+	}
+	return &KickDeviceResponse{}, nil
+}
+
+// LogoutAll 下线账号的所有设备，可保留发起请求的设备
+func (ls *LoginService) LogoutAll(ctx context.Context, msg *LogoutAllMessage) (*LogoutAllResponse, error) {
+	count, err := ls.kickAll(ctx, msg.UserId, msg.ExceptDeviceId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("LogoutAll 下线设备出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	return &LogoutAllResponse{Count: count}, nil
+}
+
+// startSession 记录新登录的会话，并按并发策略将超出上限的设备挤下线
+func (ls *LoginService) startSession(ctx context.Context, sess *session.Session) error {
+	existing, err := ls.sessions.List(ctx, sess.UserId)
+	if err != nil {
+		return err
+	}
+	for _, old := range ls.sessions.Evicted(existing, sess) {
+		if err := ls.kick(ctx, old); err != nil {
+			return err
+		}
+		libLog.IMLog.Info(fmt.Sprintf("账号 %d 在设备 %s（%s）登录，设备 %s（%s）被下线",
+			sess.UserId, sess.DeviceId, sess.Platform, old.DeviceId, old.Platform))
+	}
+	return ls.sessions.Save(ctx, sess)
+}
+
+// renewSession 刷新令牌后更新会话中记录的令牌，会话已不存在时忽略
+func (ls *LoginService) renewSession(ctx context.Context, userId int64, deviceId string, pair *token.Pair) error {
+	sess, err := ls.sessions.Get(ctx, userId, deviceId)
+	if err != nil || sess == nil {
+		return err
+	}
+	sess.LastSeen = time.Now().UnixMilli()
+	setSessionTokens(sess, pair)
+	return ls.sessions.Save(ctx, sess)
+}
+
+// kickAll 下线账号除 exceptDeviceId 外的所有设备，返回被下线的设备数
+func (ls *LoginService) kickAll(ctx context.Context, userId int64, exceptDeviceId string) (int32, error) {
+	sessions, err := ls.sessions.List(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	var count int32
+	for _, sess := range sessions {
+		if sess.DeviceId == exceptDeviceId {
+			continue
+		}
+		if err := ls.kick(ctx, sess); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// kick 删除会话并吊销会话持有的令牌
+func (ls *LoginService) kick(ctx context.Context, sess *session.Session) error {
+	if _, err := ls.sessions.Remove(ctx, sess.UserId, sess.DeviceId); err != nil {
+		return err
+	}
+	return ls.revokeSession(ctx, sess)
+}
+
+// revokeSession 吊销会话持有的令牌，并断开设备在网关上的长连接
+func (ls *LoginService) revokeSession(ctx context.Context, sess *session.Session) error {
+	err := ls.tokens.RevokeId(ctx, sess.AccessTokenId, jwts.TypeAccess, time.UnixMilli(sess.AccessTokenExp))
+	if err != nil {
+		return err
+	}
+	if err = ls.tokens.RevokeId(ctx, sess.RefreshTokenId, jwts.TypeRefresh, time.UnixMilli(sess.RefreshTokenExp)); err != nil {
+		return err
+	}
+	ls.disconnect(ctx, sess.UserId, sess.DeviceId)
+	return nil
+}
+
+// disconnect 断开设备的长连接；令牌已吊销，断开失败时只记录日志，客户端重连时会因令牌失效被拒绝
+func (ls *LoginService) disconnect(ctx context.Context, userId int64, deviceId string) {
+	if ls.gateways == nil {
+		return
+	}
+	if _, err := ls.gateways.Kick(ctx, userId, deviceId, "session revoked"); err != nil {
+		libLog.IMLog.Warn(fmt.Sprintf("断开设备长连接出错，账号: %d，设备: %s，原因: %v", userId, deviceId, err))
+	}
+}
+
+func setSessionTokens(sess *session.Session, pair *token.Pair) {
+	sess.AccessTokenId = pair.AccessTokenId
+	sess.AccessTokenExp = pair.AccessTokenExp
+	sess.RefreshTokenId = pair.RefreshTokenId
+	sess.RefreshTokenExp = pair.RefreshTokenExp
+}
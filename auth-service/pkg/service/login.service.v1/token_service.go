@@ -0,0 +1,110 @@
+package login_service_v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	"github.com/MortalSC/IM-System/auth-service/internal/token"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"github.com/MortalSC/IM-System/lib/jwts"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+)
+
+// RefreshToken 使用刷新令牌换取新的访问令牌和刷新令牌
+func (ls *LoginService) RefreshToken(ctx context.Context, msg *RefreshTokenMessage) (*TokenMessage, error) {
+	claims, pair, err := ls.tokens.Refresh(ctx, msg.RefreshToken, "")
+	if err != nil {
+		return nil, tokenError("RefreshToken", err)
+	}
+	audit.SetActor(ctx, claims.UserId)
+	audit.SetDevice(ctx, claims.DeviceId)
+	// 会话记录更新失败不影响本次刷新，仅记录日志
+	if err = ls.renewSession(ctx, claims.UserId, claims.DeviceId, pair); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("RefreshToken 更新会话出错，原因: %v", err))
+	}
+	return toTokenMessage(pair), nil
+}
+
+// RevokeToken 吊销访问令牌或刷新令牌
+func (ls *LoginService) RevokeToken(ctx context.Context, msg *RevokeTokenMessage) (*RevokeTokenResponse, error) {
+	claims, err := ls.tokens.Revoke(ctx, msg.Token)
+	if err != nil {
+		return nil, tokenError("RevokeToken", err)
+	}
+	// 令牌已过期时没有可记录的操作者
+	if claims != nil {
+		audit.SetActor(ctx, claims.UserId)
+		audit.SetDevice(ctx, claims.DeviceId)
+	}
+	return &RevokeTokenResponse{}, nil
+}
+
+// VerifyToken 校验访问令牌，返回令牌所属的账号和设备；OAuth2 令牌还返回客户端和授权范围
+func (ls *LoginService) VerifyToken(ctx context.Context, msg *VerifyTokenMessage) (*VerifyTokenResponse, error) {
+	claims, err := ls.tokens.Verify(ctx, msg.Token)
+	if err != nil {
+		return nil, tokenError("VerifyToken", err)
+	}
+	// OAuth2 令牌不属于任何登录设备，没有会话可刷新
+	if claims.ClientId == "" {
+		if err = ls.sessions.Touch(ctx, claims.UserId, claims.DeviceId); err != nil {
+			libLog.IMLog.Warn(fmt.Sprintf("VerifyToken 刷新会话活跃时间出错，原因: %v", err))
+		}
+	}
+	return &VerifyTokenResponse{
+		UserId:   claims.UserId,
+		DeviceId: claims.DeviceId,
+		TokenId:  claims.ID,
+		ExpireAt: claims.ExpiresAt.UnixMilli(),
+		ClientId: claims.ClientId,
+		Scope:    claims.Scope,
+		Roles:    claims.Roles,
+	}, nil
+}
+
+// GetPublicKeys 返回验签公钥，供其他服务本地校验令牌；HS256 为对称密钥，不对外提供
+func (ls *LoginService) GetPublicKeys(ctx context.Context, msg *PublicKeysMessage) (*PublicKeysResponse, error) {
+	keys := ls.tokens.Keys()
+	pub, err := keys.PublicKeyPEM()
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("GetPublicKeys 导出公钥出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	rsp := &PublicKeysResponse{}
+	if pub != nil {
+		rsp.Keys = append(rsp.Keys, &PublicKeyMessage{
+			KeyId:     keys.KeyId(),
+			Algorithm: keys.Algorithm(),
+			PublicKey: string(pub),
+			Issuer:    ls.tokens.Issuer(),
+		})
+	}
+	return rsp, nil
+}
+
+// tokenError 将令牌相关错误转换为 gRPC 错误
+func tokenError(method string, err error) error {
+	switch {
+	case errors.Is(err, jwts.ErrTokenExpired):
+		return libErrors.GrpcError(errs.ErrTokenExpired, "令牌已过期")
+	case errors.Is(err, jwts.ErrTokenInvalid):
+		return libErrors.GrpcError(errs.ErrTokenInvalid, "令牌无效")
+	case errors.Is(err, token.ErrTokenRevoked), errors.Is(err, token.ErrRefreshTokenReuse):
+		return libErrors.GrpcError(errs.ErrTokenRevoked, "令牌已失效，请重新登录")
+	default:
+		libLog.IMLog.Error(fmt.Sprintf("%s 出错，原因: %v", method, err))
+		return libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+}
+
+func toTokenMessage(pair *token.Pair) *TokenMessage {
+	return &TokenMessage{
+		AccessToken:     pair.AccessToken,
+		AccessTokenExp:  pair.AccessTokenExp,
+		TokenType:       tokenType,
+		RefreshToken:    pair.RefreshToken,
+		RefreshTokenExp: pair.RefreshTokenExp,
+	}
+}
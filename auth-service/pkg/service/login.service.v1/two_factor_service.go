@@ -0,0 +1,78 @@
+package login_service_v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/auth-service/internal/audit"
+	errs "github.com/MortalSC/IM-System/auth-service/internal/errors"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+)
+
+// EnrollTotp 为账号生成 TOTP 密钥和 otpauth 地址，调用 ConfirmTotp 校验一次动态码后才启用
+func (ls *LoginService) EnrollTotp(ctx context.Context, msg *EnrollTotpMessage) (*EnrollTotpResponse, error) {
+	user, err := ls.userRepo.FindById(ctx, msg.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("EnrollTotp 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+
+	// 验证器中优先显示用户名，其次邮箱、手机号
+	account := user.Username
+	if account == "" {
+		account = user.Email
+	}
+	if account == "" {
+		account = user.Mobile
+	}
+	secret, uri, err := ls.totp.Enroll(ctx, user.Id, account)
+	if err != nil {
+		return nil, err
+	}
+	return &EnrollTotpResponse{Secret: secret, OtpauthUri: uri}, nil
+}
+
+// ConfirmTotp 校验动态码后启用两步验证，返回恢复码
+func (ls *LoginService) ConfirmTotp(ctx context.Context, msg *ConfirmTotpMessage) (*ConfirmTotpResponse, error) {
+	codes, err := ls.totp.Confirm(ctx, msg.UserId, msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 启用两步验证", msg.UserId))
+	return &ConfirmTotpResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTotp 校验动态码或恢复码后关闭两步验证
+func (ls *LoginService) DisableTotp(ctx context.Context, msg *DisableTotpMessage) (*DisableTotpResponse, error) {
+	if err := ls.totp.Disable(ctx, msg.UserId, msg.Code); err != nil {
+		return nil, err
+	}
+	libLog.IMLog.Info(fmt.Sprintf("账号 %d 关闭两步验证", msg.UserId))
+	return &DisableTotpResponse{}, nil
+}
+
+// VerifyTwoFactor 校验登录挑战令牌和动态码（或恢复码），通过后签发令牌完成登录
+func (ls *LoginService) VerifyTwoFactor(ctx context.Context, msg *VerifyTwoFactorMessage) (*LoginResponse, error) {
+	c, err := ls.totp.VerifyChallenge(ctx, msg.ChallengeToken, msg.Code)
+	if err != nil {
+		return nil, err
+	}
+	audit.SetActor(ctx, c.UserId)
+	user, err := ls.userRepo.FindById(ctx, c.UserId)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("VerifyTwoFactor 查询账号出错，原因: %v", err))
+		return nil, libErrors.GrpcError(errs.ErrCacheFail, "系统繁忙，请稍后重试")
+	}
+	if user == nil {
+		return nil, libErrors.GrpcError(errs.ErrAccountNotExist, "账号不存在")
+	}
+	return ls.issueCredentials(ctx, "VerifyTwoFactor", user, &device{
+		Id:       c.DeviceId,
+		Name:     c.DeviceName,
+		Platform: c.Platform,
+		Ip:       c.Ip,
+	})
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/client"
+	imV1 "github.com/MortalSC/IM-System/lib/protocol/im.protocol.v1"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var statusNames = map[int32]string{1: "在线", 2: "离开", 3: "忙碌", 4: "离线"}
+
+// 参考客户端：登录后先同步离线消息，之后打印收到的推送、回执和订阅的用户的在线状态变化，从标准输入读取 "<用户ID> <内容>" 发送单聊文本消息
+// 或 "/status <1 在线|2 离开|3 忙碌>" 设置本设备的状态；打印即视为已读，收到的消息按会话确认已读
+func main() {
+	url := flag.String("url", "ws://127.0.0.1:8090/ws", "网关地址")
+	token := flag.String("token", "", "访问令牌")
+	flag.Parse()
+
+	var c *client.Client
+	ready := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	c, err := client.Dial(ctx, *url, client.Options{
+		Token:    *token,
+		Platform: "cli",
+		OnPush: func(p *imV1.PushBody) {
+			for _, m := range p.Messages {
+				fmt.Printf("[%d] %d: %s\n", m.Seq, m.SenderId, m.Content)
+			}
+			// 在读协程中不能等待响应，另起协程在连接建立完成后确认
+			go func() {
+				<-ready
+				markRead(c, p.Messages)
+			}()
+		},
+		OnReceipt: func(r *imV1.ReceiptBody) {
+			state := "已送达"
+			if r.Type == 2 {
+				state = "已读"
+			}
+			fmt.Printf("会话 %d：用户 %d %s到序号 %d\n", r.ConversationId, r.UserId, state, r.Seq)
+		},
+		OnPresence: func(p *imV1.PresenceBody) {
+			for _, u := range p.Presences {
+				fmt.Printf("用户 %d 状态 %s，最后在线 %d\n", u.UserId, statusNames[u.Status], u.LastSeen)
+			}
+		},
+		OnKick: func(k *imV1.KickBody) {
+			fmt.Printf("被断开连接: %d %s\n", k.Code, k.Reason)
+		},
+	})
+	cancel()
+	if err != nil {
+		log.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+	close(ready)
+	fmt.Printf("已登录，用户 %d，设备 %s\n", c.Login.UserId, c.Login.DeviceId)
+	if err = syncOffline(c); err != nil {
+		fmt.Printf("同步离线消息失败: %v\n", err)
+	}
+
+	go func() {
+		<-c.Done()
+		log.Fatalf("disconnected: %v", c.Err())
+	}()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		to, text, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if to == "/status" {
+			setStatus(c, text)
+			continue
+		}
+		targetId, err := strconv.ParseInt(to, 10, 64)
+		if !ok || err != nil {
+			fmt.Println("格式: <用户ID> <内容>")
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		rsp, err := c.Send(ctx, &imV1.SendRequest{
+			ClientMsgId:      strconv.FormatInt(time.Now().UnixNano(), 36),
+			ConversationType: 1,
+			TargetId:         targetId,
+			ContentType:      1,
+			Content:          []byte(text),
+		})
+		cancel()
+		if err != nil {
+			fmt.Printf("发送失败: %v\n", err)
+			continue
+		}
+		fmt.Printf("已发送，序号 %d\n", rsp.Seq)
+	}
+}
+
+// syncOffline 从服务端保存的设备游标开始同步离线消息，每一页的游标在下一次请求中确认
+func syncOffline(c *client.Client) error {
+	req := &imV1.SyncRequest{Resume: true}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		rsp, err := c.Sync(ctx, req)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if rsp.Reset_ {
+			fmt.Println("离线时间超过保留期，更早的消息需通过历史消息查询")
+		}
+		for _, m := range rsp.Messages {
+			fmt.Printf("[%d] %d: %s\n", m.Seq, m.SenderId, m.Content)
+		}
+		markRead(c, rsp.Messages)
+		for _, conv := range rsp.Conversations {
+			fmt.Printf("会话 %d（对方 %d）未读 %d\n", conv.ConversationId, conv.TargetId, conv.Unread)
+		}
+
+		req = &imV1.SyncRequest{Cursor: rsp.Cursor, ConversationCursors: rsp.ConversationCursors, ConversationVersion: rsp.ConversationVersion}
+		if !rsp.HasMore {
+			// 立即确认最后一页，下次登录时不再重复返回；确认期间收到的新消息已通过推送送达
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+			_, err = c.Sync(ctx, req)
+			cancel()
+			return err
+		}
+	}
+}
+
+// markRead 按会话确认已读到收到的最大序号，自己发送的消息不需要确认
+func markRead(c *client.Client, msgs []*imV1.Message) {
+	last := make(map[int64]*imV1.Message)
+	for _, m := range msgs {
+		if m.SenderId == c.Login.UserId {
+			continue
+		}
+		if prev, ok := last[m.ConversationId]; !ok || m.Seq > prev.Seq {
+			last[m.ConversationId] = m
+		}
+	}
+	for _, m := range last {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := c.Ack(ctx, &imV1.AckRequest{Type: 2, ConversationType: m.ConversationType, ConversationId: m.ConversationId, Seq: m.Seq})
+		cancel()
+		if err != nil {
+			fmt.Printf("确认已读失败: %v\n", err)
+		}
+	}
+}
+
+// setStatus 通过心跳设置本设备的在线状态，订阅了自己的用户在聚合状态变化时收到推送
+func setStatus(c *client.Client, arg string) {
+	status, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil || status < 1 || status > 3 {
+		fmt.Println("格式: /status <1 在线|2 离开|3 忙碌>")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = c.SetStatus(ctx, int32(status)); err != nil {
+		fmt.Printf("设置状态失败: %v\n", err)
+		return
+	}
+	fmt.Printf("状态已设置为%s\n", statusNames[int32(status)])
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/MortalSC/IM-System/gateway-service/config"
+	"github.com/MortalSC/IM-System/gateway-service/internal/router"
+	srv "github.com/MortalSC/IM-System/lib/router"
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	r := gin.Default()
+
+	// 网关：连接表、路由上报和心跳
+	gw := router.InitGateway()
+
+	// 路由
+	router.InitRouter(r, gw)
+
+	// 推送服务
+	gc := router.RegisterGrpc(gw)
+	stop := func() {
+		// 先通知客户端重连到其他节点，再停止推送服务
+		gw.Close()
+		gc.Stop()
+	}
+
+	// 启动服务/中止 + grpc服务停止
+	srv.RunServer(r, config.Cfg.SrvCfg.Addr, config.Cfg.SrvCfg.Name, stop)
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	"github.com/MortalSC/IM-System/gateway-service/internal/gateway"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+	"log"
+	"os"
+)
+
+var Cfg = InitConfig()
+
+type Config struct {
+	viper       *viper.Viper
+	SrvCfg      *ServerConfig
+	GC          *GrpcConfig
+	AuthCfg     *AuthConfig
+	EtcdCfg     *EtcdConfig
+	MessageCfg  *MessageConfig
+	PresenceCfg *PresenceConfig
+	GatewayCfg  *gateway.Config
+	RouteCfg    *route.Config
+}
+
+func InitConfig() *Config {
+	v := viper.New()
+	conf := &Config{viper: v}
+
+	workDir, _ := os.Getwd()
+
+	conf.viper.SetConfigName("config")
+	conf.viper.SetConfigType("yaml")
+	conf.viper.AddConfigPath("/etc/IM-System/gateway-service/user")
+	conf.viper.AddConfigPath(workDir + "/gateway-service/config")
+
+	err := conf.viper.ReadInConfig()
+	if err != nil {
+		log.Fatalln(err)
+		return nil
+	}
+
+	// 读取服务配置
+	conf.InitServerConfig()
+	// 读取日志配置
+	conf.InitZapLog()
+	// 读取redis配置
+	conf.InitRedisOptions()
+	// 读取grpc配置
+	conf.InitGrpcConfig()
+	// 读取认证配置
+	conf.InitAuthConfig()
+	// 读取服务发现配置
+	conf.InitEtcdConfig()
+	// 读取消息服务配置
+	conf.InitMessageConfig()
+	// 读取在线状态服务配置
+	conf.InitPresenceConfig()
+	// 读取网关配置，节点地址默认使用 grpc 地址，需在 grpc 配置之后读取
+	conf.InitGatewayConfig()
+	// 读取路由表配置
+	conf.InitRouteConfig()
+
+	return conf
+}
+
+// ServerConfig 服务配置
+type ServerConfig struct {
+	Name string
+	Addr string
+}
+
+func (c *Config) InitServerConfig() {
+	sc := &ServerConfig{}
+	sc.Name = c.viper.GetString("server.name")
+	sc.Addr = c.viper.GetString("server.addr")
+	c.SrvCfg = sc
+}
+
+func (c *Config) InitZapLog() {
+	//从配置中读取日志配置，初始化日志
+	lc := &libLog.LogConfig{
+		DebugFileName: c.viper.GetString("zap.debugFileName"),
+		InfoFileName:  c.viper.GetString("zap.infoFileName"),
+		WarnFileName:  c.viper.GetString("zap.warnFileName"),
+		MaxSize:       c.viper.GetInt("maxSize"),
+		MaxAge:        c.viper.GetInt("maxAge"),
+		MaxBackups:    c.viper.GetInt("maxBackups"),
+	}
+	err := libLog.InitLogger(lc)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func (c *Config) InitRedisOptions() *redis.Options {
+	return &redis.Options{
+		Addr:     c.viper.GetString("redis.host") + ":" + c.viper.GetString("redis.port"),
+		Password: c.viper.GetString("redis.password"),
+		DB:       c.viper.GetInt("redis.db"),
+	}
+}
+
+type GrpcConfig struct {
+	Name string
+	Addr string
+}
+
+func (c *Config) InitGrpcConfig() {
+	gc := &GrpcConfig{}
+	gc.Name = c.viper.GetString("grpc.name")
+	gc.Addr = c.viper.GetString("grpc.addr")
+	c.GC = gc
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	Addr string // auth-service 的 gRPC 地址
+}
+
+func (c *Config) InitAuthConfig() {
+	ac := &AuthConfig{}
+	ac.Addr = c.viper.GetString("auth.addr")
+	c.AuthCfg = ac
+}
+
+// EtcdConfig 服务发现配置
+type EtcdConfig struct {
+	Addrs []string
+}
+
+func (c *Config) InitEtcdConfig() {
+	ec := &EtcdConfig{}
+	ec.Addrs = c.viper.GetStringSlice("etcd.addrs")
+	c.EtcdCfg = ec
+}
+
+// MessageConfig 消息服务配置
+type MessageConfig struct {
+	Service string // message-service 注册到 etcd 的服务名
+}
+
+func (c *Config) InitMessageConfig() {
+	mc := &MessageConfig{}
+	mc.Service = c.viper.GetString("message.service")
+	c.MessageCfg = mc
+}
+
+// PresenceConfig 在线状态服务配置
+type PresenceConfig struct {
+	Service   string // presence-service 注册到 etcd 的服务名，为空时不上报在线状态
+	BatchSize int    // 续期时每次上报的设备数，不超过 presence-service 的 batchLimit
+}
+
+func (c *Config) InitPresenceConfig() {
+	pc := &PresenceConfig{}
+	pc.Service = c.viper.GetString("presence.service")
+	pc.BatchSize = c.viper.GetInt("presence.batchSize")
+	if pc.Service != "" && pc.BatchSize <= 0 {
+		log.Fatalln("presence.batchSize must be greater than 0")
+	}
+	c.PresenceCfg = pc
+}
+
+func (c *Config) InitGatewayConfig() {
+	gc := &gateway.Config{}
+	gc.Node = c.viper.GetString("gateway.node")
+	if gc.Node == "" {
+		gc.Node = c.GC.Addr
+	}
+	gc.RefreshInterval = c.viper.GetDuration("gateway.refreshInterval")
+	gc.MaxConnections = c.viper.GetInt("gateway.maxConnections")
+	gc.AllowedOrigins = c.viper.GetStringSlice("gateway.allowedOrigins")
+	gc.CompressThreshold = c.viper.GetInt("gateway.compressThreshold")
+	gc.Conn = conn.Config{
+		WriteWait:      c.viper.GetDuration("gateway.writeWait"),
+		PongWait:       c.viper.GetDuration("gateway.pongWait"),
+		PingInterval:   c.viper.GetDuration("gateway.pingInterval"),
+		SendBuffer:     c.viper.GetInt("gateway.sendBuffer"),
+		MaxMessageSize: c.viper.GetInt64("gateway.maxMessageSize"),
+	}
+	if gc.Conn.PingInterval >= gc.Conn.PongWait {
+		log.Fatalln("gateway.pingInterval must be less than gateway.pongWait")
+	}
+	c.GatewayCfg = gc
+}
+
+func (c *Config) InitRouteConfig() {
+	rc := &route.Config{}
+	rc.RouteTTL = c.viper.GetDuration("route.routeTTL")
+	rc.NodeTTL = c.viper.GetDuration("route.nodeTTL")
+	if rc.RouteTTL <= c.GatewayCfg.RefreshInterval || rc.NodeTTL <= c.GatewayCfg.RefreshInterval {
+		log.Fatalln("route.routeTTL and route.nodeTTL must be greater than gateway.refreshInterval")
+	}
+	c.RouteCfg = rc
+}
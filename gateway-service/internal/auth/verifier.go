@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+)
+
+// Identity 连接的认证身份
+type Identity struct {
+	UserId   int64
+	DeviceId string
+	ExpireAt int64  // 令牌过期时间（毫秒时间戳）
+	ClientId string // OAuth2 令牌所属的第三方应用，第一方登录令牌为空
+}
+
+// Verifier 校验访问令牌
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Identity, error)
+}
+
+// RpcVerifier 调用 auth-service VerifyToken 校验
+// 长连接存活时间远长于单次请求，总是走 RPC 以感知设备下线和令牌吊销
+type RpcVerifier struct {
+	client loginServiceV1.LoginServiceClient
+}
+
+func NewRpcVerifier(client loginServiceV1.LoginServiceClient) *RpcVerifier {
+	return &RpcVerifier{client: client}
+}
+
+func (v *RpcVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	rsp, err := v.client.VerifyToken(ctx, &loginServiceV1.VerifyTokenMessage{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		UserId:   rsp.UserId,
+		DeviceId: rsp.DeviceId,
+		ExpireAt: rsp.ExpireAt,
+		ClientId: rsp.ClientId,
+	}, nil
+}
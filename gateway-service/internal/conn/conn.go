@@ -0,0 +1,238 @@
+package conn
+
+import (
+	"errors"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	"github.com/gorilla/websocket"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 关闭连接时使用的状态码，4000-4999 为应用自定义状态码，客户端据此决定是否重连
+const (
+	CloseNormal       = websocket.CloseNormalClosure
+	CloseGoingAway    = websocket.CloseGoingAway     // 网关节点下线，客户端应立即重连到其他节点
+	CloseIdle         = 4000                         // 心跳超时
+	CloseTokenExpired = 4001                         // 令牌过期，客户端刷新令牌后重连
+	CloseReplaced     = 4002                         // 同一设备建立了新连接
+	CloseKicked       = 4003                         // 设备被下线，客户端不应自动重连
+	CloseLoginFailed  = 4004                         // 未在握手时携带令牌，且第一帧登录失败
+	CloseSlowConsumer = websocket.CloseTryAgainLater // 发送队列已满，客户端重连后通过离线同步补齐
+)
+
+var (
+	ErrClosed       = errors.New("connection closed")
+	ErrSlowConsumer = errors.New("send buffer full")
+)
+
+// Config 连接配置
+type Config struct {
+	WriteWait      time.Duration // 单帧写超时
+	PongWait       time.Duration // 超过该时间未收到客户端任何数据（含 pong）视为空闲连接并断开
+	PingInterval   time.Duration // 服务端发送 ping 的间隔，需小于 PongWait
+	SendBuffer     int           // 每个连接待发送队列的长度，队列满时断开连接，避免慢连接拖住推送方
+	MaxMessageSize int64         // 客户端单帧大小上限（字节）
+}
+
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// Conn 已认证的 WebSocket 连接
+// 所有写操作由写协程串行完成，其他协程通过 Send 投递到发送队列，不会被慢连接阻塞
+type Conn struct {
+	Id       string
+	UserId   int64
+	DeviceId string
+	Ip       string
+	OpenTime int64 // 建立连接时间（毫秒时间戳）
+
+	ws       *websocket.Conn
+	cfg      *Config
+	send     chan frame
+	done     chan struct{}
+	expireAt atomic.Int64 // 令牌过期时间（毫秒时间戳），客户端重新认证后更新
+	binary   atomic.Bool  // 客户端是否使用二进制帧协议，决定控制消息的格式
+	status   atomic.Int32 // 设备的在线状态，连接建立时为在线，客户端通过心跳设置
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+}
+
+func New(ws *websocket.Conn, cfg *Config, id string, userId int64, deviceId, ip string, expireAt int64) *Conn {
+	c := &Conn{
+		Id:       id,
+		UserId:   userId,
+		DeviceId: deviceId,
+		Ip:       ip,
+		OpenTime: time.Now().UnixMilli(),
+		ws:       ws,
+		cfg:      cfg,
+		send:     make(chan frame, cfg.SendBuffer),
+		done:     make(chan struct{}),
+	}
+	c.expireAt.Store(expireAt)
+	c.status.Store(protocol.PresenceOnline)
+	return c
+}
+
+// ExpireAt 令牌过期时间
+func (c *Conn) ExpireAt() int64 {
+	return c.expireAt.Load()
+}
+
+// Renew 客户端重新认证后延长连接的有效期
+func (c *Conn) Renew(expireAt int64) {
+	c.expireAt.Store(expireAt)
+}
+
+// Binary 客户端是否使用二进制帧协议
+func (c *Conn) Binary() bool {
+	return c.binary.Load()
+}
+
+// SetBinary 标记客户端使用二进制帧协议，收到第一个二进制帧或以登录帧认证时设置
+func (c *Conn) SetBinary() {
+	c.binary.Store(true)
+}
+
+// Status 设备的在线状态
+func (c *Conn) Status() int32 {
+	return c.status.Load()
+}
+
+// SetStatus 设置设备的在线状态，返回状态是否变化
+func (c *Conn) SetStatus(status int32) bool {
+	return c.status.Swap(status) != status
+}
+
+// Send 投递二进制帧，不阻塞；发送队列已满时断开连接并返回 ErrSlowConsumer
+func (c *Conn) Send(data []byte) error {
+	return c.enqueue(frame{websocket.BinaryMessage, data})
+}
+
+// SendText 投递文本帧，用于控制消息
+func (c *Conn) SendText(data []byte) error {
+	return c.enqueue(frame{websocket.TextMessage, data})
+}
+
+func (c *Conn) enqueue(f frame) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.send <- f:
+		return nil
+	case <-c.done:
+		return ErrClosed
+	default:
+		c.Close(CloseSlowConsumer, "slow consumer")
+		return ErrSlowConsumer
+	}
+}
+
+// Close 以指定状态码关闭连接，可重复调用，只有第一次生效
+func (c *Conn) Close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// Done 连接关闭后返回的 channel 被关闭
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// CloseCode 连接关闭的状态码，连接未关闭时为 0
+func (c *Conn) CloseCode() int {
+	select {
+	case <-c.done:
+		return c.closeCode
+	default:
+		return 0
+	}
+}
+
+// Run 启动写协程并在当前协程读取客户端数据，连接关闭后返回
+// 收到的每一帧（含 pong）都会延长空闲超时，数据帧交给 onMessage 处理
+func (c *Conn) Run(onMessage func(c *Conn, messageType int, data []byte)) {
+	go c.writeLoop()
+	defer c.Close(CloseNormal, "")
+
+	c.ws.SetReadLimit(c.cfg.MaxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	})
+	for {
+		messageType, data, err := c.ws.ReadMessage()
+		if err != nil {
+			var netErr interface{ Timeout() bool }
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				c.Close(CloseIdle, "idle timeout")
+			}
+			return
+		}
+		_ = c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+		if onMessage != nil {
+			onMessage(c, messageType, data)
+		}
+	}
+}
+
+// writeLoop 串行写出发送队列中的帧并定时发送 ping
+// 连接关闭时先写出队列中剩余的帧（如下线通知），再发送关闭帧；因发送队列已满关闭时直接发送关闭帧
+func (c *Conn) writeLoop() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.ws.Close()
+	}()
+	for {
+		select {
+		case f := <-c.send:
+			if err := c.write(f); err != nil {
+				c.Close(CloseNormal, "")
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.cfg.WriteWait)); err != nil {
+				c.Close(CloseNormal, "")
+				return
+			}
+		case <-c.done:
+			if c.closeCode != CloseSlowConsumer {
+				c.flush()
+			}
+			msg := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			_ = c.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(c.cfg.WriteWait))
+			return
+		}
+	}
+}
+
+// flush 写出发送队列中剩余的帧，写失败时放弃
+func (c *Conn) flush() {
+	for {
+		select {
+		case f := <-c.send:
+			if err := c.write(f); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *Conn) write(f frame) error {
+	_ = c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+	return c.ws.WriteMessage(f.messageType, f.data)
+}
@@ -0,0 +1,20 @@
+package errors
+
+const (
+	// 与 auth-service 的令牌错误码保持一致
+	ErrNoToken        = 2100 // 缺少令牌
+	ErrTokenInvalid   = 2101 // 令牌无效
+	ErrTokenExpired   = 2102 // 令牌已过期
+	ErrFirstPartyOnly = 2110 // 第三方应用令牌不能建立实时连接
+	ErrAuthBusy       = 2199 // 认证服务不可用
+
+	ErrGatewayBusy    = 3001 // 本节点连接数已达上限
+	ErrDeviceMismatch = 3002 // 重新认证的令牌与连接不属于同一设备
+	ErrBadFrame       = 3003 // 无法解析的控制消息
+	ErrInvalidPush    = 3004 // 推送参数不正确
+
+	ErrUnsupportedCommand = 3005 // 不支持的命令
+	ErrLoginRequired      = 3006 // 握手时未携带令牌，第一帧必须为登录
+	ErrBackendBusy        = 3007 // 后端服务不可用
+	ErrInvalidStatus      = 3008 // 客户端设置的在线状态不正确
+)
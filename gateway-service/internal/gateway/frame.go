@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/gateway-service/internal/auth"
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	errs "github.com/MortalSC/IM-System/gateway-service/internal/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	imV1 "github.com/MortalSC/IM-System/lib/protocol/im.protocol.v1"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+const handlerTimeout = 10 * time.Second // 单个请求帧的处理超时，处理期间不读取该连接的后续帧，需小于 PongWait
+
+// Handler 处理客户端的请求帧，返回响应帧体；返回 GrpcError 时以错误响应帧回复
+// 同一连接的请求帧按到达顺序串行处理，保证同一设备发送的消息有序
+type Handler func(ctx context.Context, c *conn.Conn, f *protocol.Frame) (proto.Message, error)
+
+// Handle 注册命令的处理函数，需在 Start 之前调用；登录和心跳由网关处理
+func (g *Gateway) Handle(cmd uint16, h Handler) {
+	g.handlers[cmd] = h
+}
+
+// handleFrame 解码并处理二进制帧，响应帧进入发送队列
+func (g *Gateway) handleFrame(c *conn.Conn, data []byte) {
+	c.SetBinary()
+	f, err := g.codec.Decode(data)
+	if err != nil {
+		// 帧格式错误说明客户端实现有误，无法按序列号回复，直接断开
+		libLog.IMLog.Warn(fmt.Sprintf("连接 %s 发送了无法解码的帧，原因: %v", c.Id, err))
+		c.Close(websocket.CloseProtocolError, "bad frame")
+		return
+	}
+	if f.IsResponse() {
+		// 客户端对推送的确认使用 CmdAck 请求帧，其余响应帧忽略
+		return
+	}
+
+	var rsp *protocol.Frame
+	switch h, ok := g.handlers[f.Command]; {
+	case f.Command == protocol.CmdLogin:
+		rsp = g.relogin(c, f)
+	case f.Command == protocol.CmdHeartbeat:
+		rsp = g.heartbeat(c, f)
+	case !ok:
+		rsp = protocol.NewError(f, errs.ErrUnsupportedCommand, fmt.Sprintf("不支持的命令: %d", f.Command))
+	default:
+		ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+		body, err := h(ctx, c, f)
+		cancel()
+		if err != nil {
+			code, msg := handlerError(err)
+			rsp = protocol.NewError(f, code, msg)
+		} else if rsp, err = protocol.NewResponse(f, body); err != nil {
+			rsp = protocol.NewError(f, errs.ErrBadFrame, "响应编码失败")
+		}
+	}
+	g.sendFrame(c, rsp)
+}
+
+// loginFrame 握手时未携带令牌的连接，在 verifyTimeout 内读取第一帧登录，登录失败时回复错误并关闭
+func (g *Gateway) loginFrame(ws *websocket.Conn) (*auth.Identity, *protocol.Frame, bool) {
+	fail := func(req *protocol.Frame, code int, msg string) (*auth.Identity, *protocol.Frame, bool) {
+		deadline := time.Now().Add(g.cfg.Conn.WriteWait)
+		if req != nil {
+			if data, err := g.codec.Encode(protocol.NewError(req, code, msg)); err == nil {
+				_ = ws.SetWriteDeadline(deadline)
+				_ = ws.WriteMessage(websocket.BinaryMessage, data)
+			}
+		}
+		_ = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(conn.CloseLoginFailed, msg), deadline)
+		_ = ws.Close()
+		return nil, nil, false
+	}
+
+	ws.SetReadLimit(g.cfg.Conn.MaxMessageSize)
+	_ = ws.SetReadDeadline(time.Now().Add(verifyTimeout))
+	messageType, data, err := ws.ReadMessage()
+	if err != nil {
+		return fail(nil, 0, "login timeout")
+	}
+	if messageType != websocket.BinaryMessage {
+		return fail(nil, 0, "login required")
+	}
+	f, err := g.codec.Decode(data)
+	if err != nil {
+		return fail(nil, 0, "bad frame")
+	}
+	var req imV1.LoginRequest
+	if f.Command != protocol.CmdLogin || f.Unmarshal(&req) != nil {
+		return fail(f, errs.ErrLoginRequired, "第一帧必须为登录")
+	}
+	id, code, msg := g.verify(req.Token)
+	if id == nil {
+		return fail(f, code, msg)
+	}
+	return id, f, true
+}
+
+// heartbeat 回复服务端时间，请求中携带状态时设置设备的在线状态
+func (g *Gateway) heartbeat(c *conn.Conn, f *protocol.Frame) *protocol.Frame {
+	var req imV1.HeartbeatRequest
+	if err := f.Unmarshal(&req); err != nil {
+		return protocol.NewError(f, errs.ErrBadFrame, "无法解析的心跳帧")
+	}
+	if req.Status != 0 && !g.setStatus(c, req.Status) {
+		return protocol.NewError(f, errs.ErrInvalidStatus, "在线状态不正确")
+	}
+	rsp, _ := protocol.NewResponse(f, &imV1.HeartbeatResponse{ServerTime: time.Now().UnixMilli()})
+	return rsp
+}
+
+// relogin 已认证的连接通过登录帧续期，令牌必须属于同一用户的同一设备
+func (g *Gateway) relogin(c *conn.Conn, f *protocol.Frame) *protocol.Frame {
+	var req imV1.LoginRequest
+	if err := f.Unmarshal(&req); err != nil {
+		return protocol.NewError(f, errs.ErrBadFrame, "无法解析的登录帧")
+	}
+	id, code, msg := g.verify(req.Token)
+	if id == nil {
+		return protocol.NewError(f, code, msg)
+	}
+	if id.UserId != c.UserId || id.DeviceId != c.DeviceId {
+		return protocol.NewError(f, errs.ErrDeviceMismatch, "令牌与连接不属于同一设备")
+	}
+	c.Renew(id.ExpireAt)
+	return g.loginResponse(f, id)
+}
+
+func (g *Gateway) loginResponse(req *protocol.Frame, id *auth.Identity) *protocol.Frame {
+	rsp, _ := protocol.NewResponse(req, &imV1.LoginResponse{
+		UserId:            id.UserId,
+		DeviceId:          id.DeviceId,
+		ExpireAt:          id.ExpireAt,
+		ServerTime:        time.Now().UnixMilli(),
+		HeartbeatInterval: int32(g.cfg.Conn.PongWait / 2 / time.Second),
+	})
+	return rsp
+}
+
+// sendFrame 编码并投递一帧
+func (g *Gateway) sendFrame(c *conn.Conn, f *protocol.Frame) {
+	data, err := g.codec.Encode(f)
+	if err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("编码命令 %d 的帧出错，原因: %v", f.Command, err))
+		return
+	}
+	_ = c.Send(data)
+}
+
+// disconnect 断开连接，使用二进制帧协议的客户端先收到下线通知
+func (g *Gateway) disconnect(c *conn.Conn, code int, reason string) {
+	if c.Binary() {
+		if f, err := protocol.NewRequest(protocol.CmdKick, 0, &imV1.KickBody{Code: int32(code), Reason: reason}); err == nil {
+			g.sendFrame(c, f)
+		}
+	}
+	c.Close(code, reason)
+}
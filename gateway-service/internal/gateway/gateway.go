@@ -0,0 +1,386 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/MortalSC/IM-System/gateway-service/internal/auth"
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	errs "github.com/MortalSC/IM-System/gateway-service/internal/errors"
+	"github.com/MortalSC/IM-System/gateway-service/internal/registry"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/model"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	verifyTimeout = 5 * time.Second // 握手和重新认证时校验令牌的超时
+	routeTimeout  = 3 * time.Second // 单次上报路由和在线状态的超时
+)
+
+// 客户端通过文本帧发送的控制消息类型，二进制帧为 lib/protocol 定义的帧
+const (
+	typePing  = "ping" // 应用层心跳，浏览器无法主动发送 ping 帧时使用
+	typePong  = "pong"
+	typeAuth  = "auth" // 令牌过期前使用刷新后的令牌重新认证，延长连接有效期
+	typeError = "error"
+)
+
+// Config 网关配置
+type Config struct {
+	Node              string        // 本节点的推送地址，写入路由表供后端服务调用
+	RefreshInterval   time.Duration // 续期路由和节点存活标记、检查令牌过期的间隔
+	MaxConnections    int           // 本节点连接数上限，0 表示不限制
+	AllowedOrigins    []string      // 允许的浏览器来源，为空时不校验 Origin
+	CompressThreshold int           // 下发帧体超过该大小时压缩，0 表示不压缩
+	Conn              conn.Config
+}
+
+// controlMessage 文本帧控制消息
+type controlMessage struct {
+	Type     string `json:"type"`
+	Token    string `json:"token,omitempty"`
+	Time     int64  `json:"time,omitempty"`
+	ExpireAt int64  `json:"expireAt,omitempty"`
+	Status   int32  `json:"status,omitempty"` // ping 时设置设备的在线状态：1 在线 2 离开 3 忙碌，0 表示不变
+	Code     int    `json:"code,omitempty"`
+	Msg      string `json:"msg,omitempty"`
+}
+
+// Gateway 连接网关：认证并接入 WebSocket 连接，维护本节点的连接表，并向路由表上报用户所在节点、向在线状态服务上报设备状态
+type Gateway struct {
+	cfg      *Config
+	verifier auth.Verifier
+	registry *registry.Registry
+	routes   *route.Table
+	upgrader websocket.Upgrader
+	codec    *protocol.Codec
+	handlers map[uint16]Handler
+	presence Presence
+
+	connSeq  atomic.Uint64
+	connNum  atomic.Int64
+	bootTime int64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+func New(cfg *Config, verifier auth.Verifier, routes *route.Table) *Gateway {
+	g := &Gateway{
+		cfg:      cfg,
+		verifier: verifier,
+		registry: registry.New(),
+		routes:   routes,
+		codec:    &protocol.Codec{MaxFrameSize: int(cfg.Conn.MaxMessageSize), CompressThreshold: cfg.CompressThreshold},
+		handlers: make(map[uint16]Handler),
+		bootTime: time.Now().UnixNano(),
+		stop:     make(chan struct{}),
+	}
+	g.upgrader = websocket.Upgrader{
+		HandshakeTimeout: verifyTimeout,
+		CheckOrigin:      g.checkOrigin,
+	}
+	return g
+}
+
+// Start 上报节点存活并定期续期，需在接受连接前调用
+func (g *Gateway) Start() {
+	g.refresh()
+	go func() {
+		ticker := time.NewTicker(g.cfg.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-g.stop:
+				return
+			case <-ticker.C:
+				g.refresh()
+			}
+		}
+	}()
+}
+
+// Close 节点下线：删除存活标记使路由立即失效，并通知全部客户端重连到其他节点
+func (g *Gateway) Close() {
+	g.stopOnce.Do(func() {
+		close(g.stop)
+		ctx, cancel := context.WithTimeout(context.Background(), routeTimeout)
+		defer cancel()
+		if err := g.routes.NodeDown(ctx, g.cfg.Node); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("删除网关节点 %s 存活标记出错，原因: %v", g.cfg.Node, err))
+		}
+		for _, c := range g.snapshot() {
+			g.disconnect(c, conn.CloseGoingAway, "node shutdown")
+		}
+	})
+}
+
+// Serve 处理 WebSocket 握手：令牌通过 Authorization: Bearer <token> 或查询参数 token 传递（浏览器无法设置请求头）
+// 握手时未携带令牌的连接需在第一帧发送 CmdLogin 登录
+// [GET] /ws
+func (g *Gateway) Serve(ctx *gin.Context) {
+	result := model.HttpResult{}
+
+	var id *auth.Identity
+	if token := bearerToken(ctx); token != "" {
+		var (
+			code int
+			msg  string
+		)
+		if id, code, msg = g.verify(token); id == nil {
+			status := http.StatusUnauthorized
+			if code == errs.ErrFirstPartyOnly {
+				status = http.StatusForbidden
+			}
+			ctx.AbortWithStatusJSON(status, result.Failed(code, msg))
+			return
+		}
+	}
+	if max := g.cfg.MaxConnections; max > 0 && g.connNum.Load() >= int64(max) {
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, result.Failed(errs.ErrGatewayBusy, "连接数已达上限，请稍后重试"))
+		return
+	}
+
+	// 握手失败时 Upgrade 已写入错误响应
+	ws, err := g.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		return
+	}
+	var login *protocol.Frame
+	if id == nil {
+		var ok bool
+		if id, login, ok = g.loginFrame(ws); !ok {
+			return
+		}
+	}
+	c := conn.New(ws, &g.cfg.Conn, g.nextConnId(), id.UserId, id.DeviceId, ctx.ClientIP(), id.ExpireAt)
+	if login != nil {
+		c.SetBinary()
+		g.sendFrame(c, g.loginResponse(login, id))
+	}
+	g.online(c)
+	defer g.offline(c)
+	c.Run(g.onMessage)
+}
+
+// Push 向用户在本节点的连接投递二进制帧，deviceId 为空时投递到全部设备
+// 返回进入发送队列的连接数和因发送队列已满被断开的连接数
+func (g *Gateway) Push(userId int64, deviceId string, payload []byte) (delivered, dropped int) {
+	for _, c := range g.registry.Get(userId, deviceId) {
+		switch err := c.Send(payload); err {
+		case nil:
+			delivered++
+		case conn.ErrSlowConsumer:
+			dropped++
+			libLog.IMLog.Warn(fmt.Sprintf("连接 %s（用户 %d 设备 %s）发送队列已满，已断开", c.Id, c.UserId, c.DeviceId))
+		}
+	}
+	return delivered, dropped
+}
+
+// Kick 断开用户在本节点的连接，deviceId 为空时断开全部设备，返回断开的连接数
+func (g *Gateway) Kick(userId int64, deviceId, reason string) int {
+	conns := g.registry.Get(userId, deviceId)
+	for _, c := range conns {
+		g.disconnect(c, conn.CloseKicked, reason)
+	}
+	return len(conns)
+}
+
+// Node 本节点的推送地址
+func (g *Gateway) Node() string {
+	return g.cfg.Node
+}
+
+// Stats 本节点的在线用户数和连接数
+func (g *Gateway) Stats() (users, conns int) {
+	return g.registry.Stats()
+}
+
+func (g *Gateway) online(c *conn.Conn) {
+	g.connNum.Add(1)
+	if old := g.registry.Add(c); old != nil {
+		g.disconnect(old, conn.CloseReplaced, "replaced by new connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), routeTimeout)
+	defer cancel()
+	if err := g.routes.Online(ctx, c.UserId, c.DeviceId, g.cfg.Node, c.Id); err != nil {
+		// 路由上报失败不影响连接，下一次续期时重试
+		libLog.IMLog.Error(fmt.Sprintf("上报用户 %d 设备 %s 的路由出错，原因: %v", c.UserId, c.DeviceId, err))
+	}
+	g.reportPresence(ctx, deviceStatus(c, c.Status()))
+	libLog.IMLog.Info(fmt.Sprintf("连接 %s 建立，用户: %d，设备: %s，IP: %s", c.Id, c.UserId, c.DeviceId, c.Ip))
+}
+
+func (g *Gateway) offline(c *conn.Conn) {
+	g.connNum.Add(-1)
+	if g.registry.Remove(c) {
+		ctx, cancel := context.WithTimeout(context.Background(), routeTimeout)
+		defer cancel()
+		if err := g.routes.Offline(ctx, c.UserId, c.DeviceId, c.Id); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("删除用户 %d 设备 %s 的路由出错，原因: %v", c.UserId, c.DeviceId, err))
+		}
+		g.reportPresence(ctx, deviceStatus(c, protocol.PresenceOffline))
+	}
+	libLog.IMLog.Info(fmt.Sprintf("连接 %s 断开，用户: %d，设备: %s，状态码: %d，持续: %s",
+		c.Id, c.UserId, c.DeviceId, c.CloseCode(), time.Since(time.UnixMilli(c.OpenTime)).Truncate(time.Second)))
+}
+
+// onMessage 处理客户端发送的帧：文本帧为 JSON 控制消息，二进制帧为 lib/protocol 定义的帧
+func (g *Gateway) onMessage(c *conn.Conn, messageType int, data []byte) {
+	if messageType == websocket.BinaryMessage {
+		g.handleFrame(c, data)
+		return
+	}
+	var msg controlMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		g.reply(c, controlMessage{Type: typeError, Code: errs.ErrBadFrame, Msg: "无法解析的控制消息"})
+		return
+	}
+	switch msg.Type {
+	case typePing:
+		if msg.Status != 0 && !g.setStatus(c, msg.Status) {
+			g.reply(c, controlMessage{Type: typeError, Code: errs.ErrInvalidStatus, Msg: "在线状态不正确"})
+			return
+		}
+		g.reply(c, controlMessage{Type: typePong, Time: time.Now().UnixMilli()})
+	case typeAuth:
+		g.reauthenticate(c, msg.Token)
+	default:
+		g.reply(c, controlMessage{Type: typeError, Code: errs.ErrBadFrame, Msg: "不支持的控制消息: " + msg.Type})
+	}
+}
+
+// reauthenticate 使用刷新后的令牌延长连接有效期，令牌必须属于同一用户的同一设备
+func (g *Gateway) reauthenticate(c *conn.Conn, token string) {
+	id, code, msg := g.verify(token)
+	if id == nil {
+		g.reply(c, controlMessage{Type: typeError, Code: code, Msg: msg})
+		return
+	}
+	if id.UserId != c.UserId || id.DeviceId != c.DeviceId {
+		g.reply(c, controlMessage{Type: typeError, Code: errs.ErrDeviceMismatch, Msg: "令牌与连接不属于同一设备"})
+		return
+	}
+	c.Renew(id.ExpireAt)
+	g.reply(c, controlMessage{Type: typeAuth, ExpireAt: id.ExpireAt})
+}
+
+func (g *Gateway) reply(c *conn.Conn, msg controlMessage) {
+	data, _ := json.Marshal(msg)
+	_ = c.SendText(data)
+}
+
+// refresh 续期节点存活标记、本节点全部连接的路由和在线状态，并断开令牌已过期的连接
+func (g *Gateway) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), g.cfg.RefreshInterval)
+	defer cancel()
+	if err := g.routes.NodeAlive(ctx, g.cfg.Node); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("上报网关节点 %s 存活出错，原因: %v", g.cfg.Node, err))
+	}
+
+	// 1. 断开令牌已过期的连接
+	now := time.Now().UnixMilli()
+	conns := g.snapshot()
+	alive := make([]*conn.Conn, 0, len(conns))
+	for _, c := range conns {
+		if exp := c.ExpireAt(); exp > 0 && exp <= now {
+			g.disconnect(c, conn.CloseTokenExpired, "token expired")
+			continue
+		}
+		alive = append(alive, c)
+	}
+
+	// 2. 续期其余连接的路由，单个连接续期失败不影响其他连接
+	devices := make([]DeviceStatus, 0, len(alive))
+	for _, c := range alive {
+		devices = append(devices, deviceStatus(c, c.Status()))
+		if err := g.routes.Online(ctx, c.UserId, c.DeviceId, g.cfg.Node, c.Id); err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("续期连接 %s 的路由出错，原因: %v", c.Id, err))
+		}
+	}
+	g.reportPresence(ctx, devices...)
+}
+
+// snapshot 复制当前的全部连接，避免在持有连接表锁时访问 Redis 或关闭连接
+func (g *Gateway) snapshot() []*conn.Conn {
+	conns := make([]*conn.Conn, 0, g.connNum.Load())
+	g.registry.Range(func(c *conn.Conn) bool {
+		conns = append(conns, c)
+		return true
+	})
+	return conns
+}
+
+func (g *Gateway) nextConnId() string {
+	return strconv.FormatInt(g.bootTime, 36) + "-" + strconv.FormatUint(g.connSeq.Add(1), 36)
+}
+
+func (g *Gateway) checkOrigin(r *http.Request) bool {
+	if len(g.cfg.AllowedOrigins) == 0 {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// 非浏览器客户端不携带 Origin
+		return true
+	}
+	for _, o := range g.cfg.AllowedOrigins {
+		if strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(ctx *gin.Context) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(ctx.GetHeader("Authorization")), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ctx.Query("token")
+}
+
+// verify 校验令牌，只接受带设备的第一方登录令牌；校验失败时返回响应码
+func (g *Gateway) verify(token string) (*auth.Identity, int, string) {
+	if token == "" {
+		return nil, errs.ErrNoToken, "未登录"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	id, err := g.verifier.Verify(ctx, token)
+	if err != nil {
+		code, msg := libErrors.ParseGrpcError(err)
+		if code < 1000 {
+			// gRPC 标准状态码，非业务错误（如 auth-service 不可达）
+			libLog.IMLog.Error(fmt.Sprintf("校验令牌出错：%v", err))
+			return nil, errs.ErrAuthBusy, "认证服务不可用"
+		}
+		return nil, code, msg
+	}
+	if id.ClientId != "" || id.DeviceId == "" {
+		return nil, errs.ErrFirstPartyOnly, "实时连接只接受登录令牌"
+	}
+	return id, 0, ""
+}
+
+// handlerError 将后端服务返回的 gRPC 错误转换为错误响应帧的响应码
+func handlerError(err error) (int, string) {
+	code, msg := libErrors.ParseGrpcError(err)
+	if code < 1000 {
+		libLog.IMLog.Error(fmt.Sprintf("处理请求帧出错：%v", err))
+		return errs.ErrBackendBusy, "服务繁忙，请稍后重试"
+	}
+	return code, msg
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/protocol"
+)
+
+// DeviceStatus 上报的设备状态
+type DeviceStatus struct {
+	UserId   int64
+	DeviceId string
+	Status   int32 // 离线表示连接已断开
+}
+
+// Presence 上报本节点设备的在线状态，连接建立、客户端设置状态、续期和断开时调用
+type Presence interface {
+	Report(ctx context.Context, node string, devices []DeviceStatus) error
+}
+
+// SetPresence 设置在线状态上报，需在 Start 之前调用；未设置时不上报
+func (g *Gateway) SetPresence(p Presence) {
+	g.presence = p
+}
+
+// setStatus 客户端通过心跳设置设备的状态，状态变化时立即上报；离线状态只能由断开连接产生
+func (g *Gateway) setStatus(c *conn.Conn, status int32) bool {
+	if status < protocol.PresenceOnline || status >= protocol.PresenceOffline {
+		return false
+	}
+	if c.SetStatus(status) {
+		ctx, cancel := context.WithTimeout(context.Background(), routeTimeout)
+		defer cancel()
+		g.reportPresence(ctx, deviceStatus(c, status))
+	}
+	return true
+}
+
+// reportPresence 上报失败不影响连接，下一次续期时重新上报
+func (g *Gateway) reportPresence(ctx context.Context, devices ...DeviceStatus) {
+	if g.presence == nil || len(devices) == 0 {
+		return
+	}
+	if err := g.presence.Report(ctx, g.cfg.Node, devices); err != nil {
+		libLog.IMLog.Error(fmt.Sprintf("上报 %d 台设备的在线状态出错，原因: %v", len(devices), err))
+	}
+}
+
+func deviceStatus(c *conn.Conn, status int32) DeviceStatus {
+	return DeviceStatus{UserId: c.UserId, DeviceId: c.DeviceId, Status: status}
+}
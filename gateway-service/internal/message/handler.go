@@ -0,0 +1,138 @@
+package message
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	errs "github.com/MortalSC/IM-System/gateway-service/internal/errors"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	imV1 "github.com/MortalSC/IM-System/lib/protocol/im.protocol.v1"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Handler 将消息相关的请求帧转发给 message-service，发送方使用连接的认证身份
+type Handler struct {
+	client messageServiceV1.MessageServiceClient
+}
+
+func NewHandler(client messageServiceV1.MessageServiceClient) *Handler {
+	return &Handler{client: client}
+}
+
+// Send 处理 CmdSend，返回服务端确认
+func (h *Handler) Send(ctx context.Context, c *conn.Conn, f *protocol.Frame) (proto.Message, error) {
+	var req imV1.SendRequest
+	if err := f.Unmarshal(&req); err != nil {
+		return nil, libErrors.GrpcError(errs.ErrBadFrame, "无法解析的发送请求")
+	}
+	rsp, err := h.client.Send(ctx, &messageServiceV1.SendMessage{
+		SenderId:         c.UserId,
+		SenderDevice:     c.DeviceId,
+		ClientMsgId:      req.ClientMsgId,
+		ConversationType: req.ConversationType,
+		TargetId:         req.TargetId,
+		ContentType:      req.ContentType,
+		Content:          req.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &imV1.SendResponse{
+		ClientMsgId: rsp.ClientMsgId,
+		MsgId:       rsp.MsgId,
+		Seq:         rsp.Seq,
+		SendTime:    rsp.SendTime,
+		Duplicate:   rsp.Duplicate,
+	}, nil
+}
+
+// Sync 处理 CmdSync，按连接的设备同步离线消息和会话列表增量
+func (h *Handler) Sync(ctx context.Context, c *conn.Conn, f *protocol.Frame) (proto.Message, error) {
+	var req imV1.SyncRequest
+	if err := f.Unmarshal(&req); err != nil {
+		return nil, libErrors.GrpcError(errs.ErrBadFrame, "无法解析的同步请求")
+	}
+	msg := &messageServiceV1.SyncMessage{
+		UserId:              c.UserId,
+		DeviceId:            c.DeviceId,
+		Resume:              req.Resume,
+		Cursor:              req.Cursor,
+		ConversationCursors: make([]*messageServiceV1.ConversationCursor, 0, len(req.ConversationCursors)),
+		ConversationVersion: req.ConversationVersion,
+		Limit:               req.Limit,
+	}
+	for _, cc := range req.ConversationCursors {
+		msg.ConversationCursors = append(msg.ConversationCursors, &messageServiceV1.ConversationCursor{ConversationId: cc.ConversationId, Seq: cc.Seq})
+	}
+	rsp, err := h.client.Sync(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &imV1.SyncResponse{
+		Messages:            make([]*imV1.Message, 0, len(rsp.Messages)),
+		Cursor:              rsp.Cursor,
+		HasMore:             rsp.HasMore,
+		Reset_:              rsp.Reset_,
+		ConversationCursors: make([]*imV1.ConversationCursor, 0, len(rsp.ConversationCursors)),
+		Conversations:       make([]*imV1.Conversation, 0, len(rsp.Conversations)),
+		ConversationVersion: rsp.ConversationVersion,
+	}
+	for _, m := range rsp.Messages {
+		out.Messages = append(out.Messages, toMessage(m))
+	}
+	for _, cc := range rsp.ConversationCursors {
+		out.ConversationCursors = append(out.ConversationCursors, &imV1.ConversationCursor{ConversationId: cc.ConversationId, Seq: cc.Seq, Reset_: cc.Reset_})
+	}
+	for _, conv := range rsp.Conversations {
+		item := &imV1.Conversation{
+			ConversationType: conv.ConversationType,
+			ConversationId:   conv.ConversationId,
+			TargetId:         conv.TargetId,
+			MaxSeq:           conv.MaxSeq,
+			Unread:           conv.Unread,
+			Version:          conv.Version,
+		}
+		if conv.LastMessage != nil {
+			item.LastMessage = toMessage(conv.LastMessage)
+		}
+		out.Conversations = append(out.Conversations, item)
+	}
+	return out, nil
+}
+
+// Ack 处理 CmdAck，确认连接的用户在会话中的送达或已读位置
+func (h *Handler) Ack(ctx context.Context, c *conn.Conn, f *protocol.Frame) (proto.Message, error) {
+	var req imV1.AckRequest
+	if err := f.Unmarshal(&req); err != nil {
+		return nil, libErrors.GrpcError(errs.ErrBadFrame, "无法解析的确认请求")
+	}
+	rsp, err := h.client.Ack(ctx, &messageServiceV1.AckMessage{
+		UserId:         c.UserId,
+		DeviceId:       c.DeviceId,
+		Type:           req.Type,
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &imV1.AckResponse{DeliveredSeq: rsp.DeliveredSeq, ReadSeq: rsp.ReadSeq, Unread: rsp.Unread}, nil
+}
+
+func toMessage(m *messageServiceV1.MessageInfo) *imV1.Message {
+	return &imV1.Message{
+		MsgId:            m.MsgId,
+		Seq:              m.Seq,
+		ConversationType: m.ConversationType,
+		ConversationId:   m.ConversationId,
+		SenderId:         m.SenderId,
+		SenderDevice:     m.SenderDevice,
+		TargetId:         m.TargetId,
+		ContentType:      m.ContentType,
+		Content:          m.Content,
+		ClientMsgId:      m.ClientMsgId,
+		SendTime:         m.SendTime,
+	}
+}
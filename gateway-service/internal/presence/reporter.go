@@ -0,0 +1,33 @@
+package presence
+
+import (
+	"context"
+	"github.com/MortalSC/IM-System/gateway-service/internal/gateway"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
+)
+
+// Reporter 将本节点设备的在线状态上报给 presence-service，按 batchSize 分批，不超过服务端的单次上报上限
+type Reporter struct {
+	client    presenceServiceV1.PresenceServiceClient
+	batchSize int
+}
+
+func NewReporter(client presenceServiceV1.PresenceServiceClient, batchSize int) *Reporter {
+	return &Reporter{client: client, batchSize: batchSize}
+}
+
+// Report 实现 gateway.Presence，某一批失败时返回错误，之后的批次不再上报
+func (r *Reporter) Report(ctx context.Context, node string, devices []gateway.DeviceStatus) error {
+	for len(devices) > 0 {
+		n := min(len(devices), r.batchSize)
+		msg := &presenceServiceV1.ReportMessage{Node: node, Devices: make([]*presenceServiceV1.DeviceStatus, 0, n)}
+		for _, d := range devices[:n] {
+			msg.Devices = append(msg.Devices, &presenceServiceV1.DeviceStatus{UserId: d.UserId, DeviceId: d.DeviceId, Status: d.Status})
+		}
+		if _, err := r.client.Report(ctx, msg); err != nil {
+			return err
+		}
+		devices = devices[n:]
+	}
+	return nil
+}
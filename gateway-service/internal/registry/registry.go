@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"github.com/MortalSC/IM-System/gateway-service/internal/conn"
+	"sync"
+)
+
+const shardCount = 64 // 按用户分片加锁，减少大量连接同时上下线时的锁竞争
+
+type shard struct {
+	mu    sync.RWMutex
+	users map[int64]map[string]*conn.Conn // userId -> deviceId -> 连接
+}
+
+// Registry 本节点的连接表，同一用户的同一设备只保留最新的一条连接
+type Registry struct {
+	shards [shardCount]*shard
+}
+
+func New() *Registry {
+	r := &Registry{}
+	for i := range r.shards {
+		r.shards[i] = &shard{users: make(map[int64]map[string]*conn.Conn)}
+	}
+	return r
+}
+
+func (r *Registry) shard(userId int64) *shard {
+	return r.shards[uint64(userId)%shardCount]
+}
+
+// Add 登记连接，返回被替换的同设备旧连接，调用方负责关闭旧连接
+func (r *Registry) Add(c *conn.Conn) *conn.Conn {
+	s := r.shard(c.UserId)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := s.users[c.UserId]
+	if devices == nil {
+		devices = make(map[string]*conn.Conn)
+		s.users[c.UserId] = devices
+	}
+	old := devices[c.DeviceId]
+	devices[c.DeviceId] = c
+	return old
+}
+
+// Remove 移除连接，连接已被同设备的新连接替换时不做处理，返回是否移除
+func (r *Registry) Remove(c *conn.Conn) bool {
+	s := r.shard(c.UserId)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := s.users[c.UserId]
+	if devices[c.DeviceId] != c {
+		return false
+	}
+	delete(devices, c.DeviceId)
+	if len(devices) == 0 {
+		delete(s.users, c.UserId)
+	}
+	return true
+}
+
+// Get 查询用户在本节点的连接，deviceId 为空时返回全部设备的连接
+func (r *Registry) Get(userId int64, deviceId string) []*conn.Conn {
+	s := r.shard(userId)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	devices := s.users[userId]
+	if deviceId != "" {
+		if c, ok := devices[deviceId]; ok {
+			return []*conn.Conn{c}
+		}
+		return nil
+	}
+	conns := make([]*conn.Conn, 0, len(devices))
+	for _, c := range devices {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// Range 遍历全部连接，f 返回 false 时停止；遍历时持有分片读锁，f 中不能调用 Add/Remove
+func (r *Registry) Range(f func(c *conn.Conn) bool) {
+	for _, s := range r.shards {
+		s.mu.RLock()
+		for _, devices := range s.users {
+			for _, c := range devices {
+				if !f(c) {
+					s.mu.RUnlock()
+					return
+				}
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Stats 在线用户数和连接数
+func (r *Registry) Stats() (users, conns int) {
+	for _, s := range r.shards {
+		s.mu.RLock()
+		users += len(s.users)
+		for _, devices := range s.users {
+			conns += len(devices)
+		}
+		s.mu.RUnlock()
+	}
+	return users, conns
+}
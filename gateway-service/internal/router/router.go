@@ -0,0 +1,116 @@
+package router
+
+import (
+	"fmt"
+	loginServiceV1 "github.com/MortalSC/IM-System/auth-service/pkg/service/login.service.v1"
+	"github.com/MortalSC/IM-System/gateway-service/config"
+	"github.com/MortalSC/IM-System/gateway-service/internal/auth"
+	"github.com/MortalSC/IM-System/gateway-service/internal/gateway"
+	"github.com/MortalSC/IM-System/gateway-service/internal/message"
+	"github.com/MortalSC/IM-System/gateway-service/internal/presence"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	gatewayServiceV1 "github.com/MortalSC/IM-System/gateway-service/pkg/service/gateway.service.v1"
+	"github.com/MortalSC/IM-System/lib/cache"
+	"github.com/MortalSC/IM-System/lib/cache/redis"
+	"github.com/MortalSC/IM-System/lib/discovery"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	messageServiceV1 "github.com/MortalSC/IM-System/message-service/pkg/service/message.service.v1"
+	presenceServiceV1 "github.com/MortalSC/IM-System/presence-service/pkg/service/presence.service.v1"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"log"
+	"net"
+)
+
+type gRPCConfig struct {
+	Addr         string
+	RegisterFunc func(*grpc.Server)
+}
+
+// InitGateway 初始化依赖并创建网关，开始上报节点存活
+func InitGateway() *gateway.Gateway {
+	cacheInstance, err := InitDependencies()
+	if err != nil {
+		log.Fatalf("Failed to initialize dependencies: %v", err)
+	}
+
+	conn, err := grpc.Dial(config.Cfg.AuthCfg.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	verifier := auth.NewRpcVerifier(loginServiceV1.NewLoginServiceClient(conn))
+
+	gw := gateway.New(config.Cfg.GatewayCfg, verifier, route.NewTable(cacheInstance, config.Cfg.RouteCfg))
+
+	// 后端服务通过 etcd 发现，按轮询选择节点
+	resolver.Register(discovery.NewResolver(config.Cfg.EtcdCfg.Addrs, libLog.IMLog))
+	messageConn, err := grpc.Dial(discovery.BuildResolverUrl(config.Cfg.MessageCfg.Service),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+	)
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	messageHandler := message.NewHandler(messageServiceV1.NewMessageServiceClient(messageConn))
+	gw.Handle(protocol.CmdSend, messageHandler.Send)
+	gw.Handle(protocol.CmdSync, messageHandler.Sync)
+	gw.Handle(protocol.CmdAck, messageHandler.Ack)
+
+	// 在线状态服务，未配置时不上报
+	if config.Cfg.PresenceCfg.Service != "" {
+		presenceConn, err := grpc.Dial(discovery.BuildResolverUrl(config.Cfg.PresenceCfg.Service),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy": "round_robin"}`),
+		)
+		if err != nil {
+			log.Fatalf("did not connect: %v", err)
+		}
+		gw.SetPresence(presence.NewReporter(presenceServiceV1.NewPresenceServiceClient(presenceConn), config.Cfg.PresenceCfg.BatchSize))
+	}
+
+	gw.Start()
+	return gw
+}
+
+// InitRouter 注册 WebSocket 接入路由
+func InitRouter(r *gin.Engine, gw *gateway.Gateway) {
+	r.GET("/ws", gw.Serve)
+}
+
+// RegisterGrpc 注册并启动推送服务
+func RegisterGrpc(gw *gateway.Gateway) *grpc.Server {
+	c := gRPCConfig{
+		Addr: config.Cfg.GC.Addr,
+		RegisterFunc: func(g *grpc.Server) {
+			gatewayServiceV1.RegisterGatewayServiceServer(g, gatewayServiceV1.New(gw))
+		},
+	}
+	s := grpc.NewServer()
+	c.RegisterFunc(s)
+	lis, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		log.Println("cannot listen")
+	}
+	go func() {
+		err = s.Serve(lis)
+		if err != nil {
+			log.Println("server started error", err)
+			return
+		}
+	}()
+	return s
+}
+
+// InitDependencies 初始化服务依赖
+func InitDependencies() (cache.Cache, error) {
+	cfg := config.Cfg
+	cacheInstance, err := redis.NewRedisCache(cfg.InitRedisOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Redis: %w", err)
+	}
+	libLog.IMLog.Debug("Redis initialized successfully")
+	return cacheInstance, nil
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	imV1 "github.com/MortalSC/IM-System/lib/protocol/im.protocol.v1"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	writeWait                = 10 * time.Second
+	defaultHeartbeatInterval = 30 * time.Second
+)
+
+var ErrClosed = errors.New("client closed")
+
+// Error 服务端返回的错误响应
+type Error struct {
+	Code int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("im error %d: %s", e.Code, e.Msg)
+}
+
+// CloseError 服务端断开连接，Code 为 WebSocket 关闭状态码，含义见 gateway-service/internal/conn
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("connection closed %d: %s", e.Code, e.Reason)
+}
+
+// Options 客户端选项
+type Options struct {
+	Token      string
+	Platform   string
+	AppVersion string
+	Codec      *protocol.Codec          // 为空时使用 protocol.DefaultCodec
+	OnPush     func(*imV1.PushBody)     // 收到推送，在读协程中调用，不能阻塞；需调用 Ack 确认送达
+	OnKick     func(*imV1.KickBody)     // 服务端断开连接前的通知
+	OnReceipt  func(*imV1.ReceiptBody)  // 收到送达或已读回执，在读协程中调用，不能阻塞
+	OnPresence func(*imV1.PresenceBody) // 订阅的用户在线状态变化，在读协程中调用，不能阻塞
+}
+
+// Client 连接网关的参考客户端：以登录帧认证、按服务端建议的间隔发送心跳、按序列号匹配请求和响应
+type Client struct {
+	ws    *websocket.Conn
+	opts  Options
+	codec *protocol.Codec
+	Login *imV1.LoginResponse
+
+	writeMu sync.Mutex
+	seq     atomic.Uint64
+
+	mu      sync.Mutex
+	pending map[uint64]chan *protocol.Frame
+
+	done    chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// Dial 连接网关并登录，url 形如 ws://host:port/ws
+func Dial(ctx context.Context, url string, opts Options) (*Client, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		ws:      ws,
+		opts:    opts,
+		codec:   opts.Codec,
+		pending: make(map[uint64]chan *protocol.Frame),
+		done:    make(chan struct{}),
+	}
+	if c.codec == nil {
+		c.codec = protocol.DefaultCodec
+	}
+	go c.readLoop()
+
+	login := &imV1.LoginResponse{}
+	err = c.Request(ctx, protocol.CmdLogin, &imV1.LoginRequest{
+		Token:      opts.Token,
+		Platform:   opts.Platform,
+		AppVersion: opts.AppVersion,
+	}, login)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	c.Login = login
+
+	interval := time.Duration(login.HeartbeatInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	go c.heartbeat(interval)
+	return c, nil
+}
+
+// Request 发送请求帧并等待响应，rsp 为空时忽略响应帧体
+func (c *Client) Request(ctx context.Context, cmd uint16, req, rsp proto.Message) error {
+	f, err := protocol.NewRequest(cmd, c.seq.Add(1), req)
+	if err != nil {
+		return err
+	}
+	ch := make(chan *protocol.Frame, 1)
+	c.mu.Lock()
+	c.pending[f.Seq] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, f.Seq)
+		c.mu.Unlock()
+	}()
+
+	if err = c.write(f); err != nil {
+		return err
+	}
+	select {
+	case r := <-ch:
+		if e := r.Error(); e != nil {
+			return &Error{Code: int(e.Code), Msg: e.Msg}
+		}
+		if rsp == nil {
+			return nil
+		}
+		return r.Unmarshal(rsp)
+	case <-c.done:
+		return c.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send 发送消息，ClientMsgId 为空时服务端不做去重
+func (c *Client) Send(ctx context.Context, req *imV1.SendRequest) (*imV1.SendResponse, error) {
+	rsp := &imV1.SendResponse{}
+	return rsp, c.Request(ctx, protocol.CmdSend, req, rsp)
+}
+
+// Ack 确认送达或已读，返回确认后的位置和会话未读数；不能在 OnPush 中同步调用
+func (c *Client) Ack(ctx context.Context, req *imV1.AckRequest) (*imV1.AckResponse, error) {
+	rsp := &imV1.AckResponse{}
+	return rsp, c.Request(ctx, protocol.CmdAck, req, rsp)
+}
+
+// Sync 从请求中的游标开始同步离线消息，HasMore 为 true 时以返回的游标继续请求
+func (c *Client) Sync(ctx context.Context, req *imV1.SyncRequest) (*imV1.SyncResponse, error) {
+	rsp := &imV1.SyncResponse{}
+	return rsp, c.Request(ctx, protocol.CmdSync, req, rsp)
+}
+
+// SetStatus 设置本设备的在线状态（在线、离开、忙碌），用户的状态由全部在线设备聚合
+func (c *Client) SetStatus(ctx context.Context, status int32) error {
+	return c.Request(ctx, protocol.CmdHeartbeat, &imV1.HeartbeatRequest{ClientTime: time.Now().UnixMilli(), Status: status}, nil)
+}
+
+// Relogin 令牌刷新后重新发送登录帧，延长连接有效期
+func (c *Client) Relogin(ctx context.Context, token string) error {
+	login := &imV1.LoginResponse{}
+	if err := c.Request(ctx, protocol.CmdLogin, &imV1.LoginRequest{Token: token, Platform: c.opts.Platform, AppVersion: c.opts.AppVersion}, login); err != nil {
+		return err
+	}
+	c.Login = login
+	return nil
+}
+
+// Done 连接断开后返回的 channel 被关闭
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 连接断开的原因，服务端主动断开时为 *CloseError
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close 主动断开连接
+func (c *Client) Close() error {
+	c.writeMu.Lock()
+	_ = c.ws.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(writeWait))
+	c.writeMu.Unlock()
+	c.fail(ErrClosed)
+	return c.ws.Close()
+}
+
+func (c *Client) write(f *protocol.Frame) error {
+	data, err := c.codec.Encode(f)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
+
+func (c *Client) readLoop() {
+	for {
+		messageType, data, err := c.ws.ReadMessage()
+		if err != nil {
+			var ce *websocket.CloseError
+			if errors.As(err, &ce) {
+				err = &CloseError{Code: ce.Code, Reason: ce.Text}
+			}
+			c.fail(err)
+			return
+		}
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+		f, err := c.codec.Decode(data)
+		if err != nil {
+			c.fail(err)
+			_ = c.ws.Close()
+			return
+		}
+		c.dispatch(f)
+	}
+}
+
+func (c *Client) dispatch(f *protocol.Frame) {
+	if f.IsResponse() {
+		c.mu.Lock()
+		ch := c.pending[f.Seq]
+		c.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- f:
+			default:
+			}
+		}
+		return
+	}
+	switch f.Command {
+	case protocol.CmdPush:
+		body := &imV1.PushBody{}
+		if f.Unmarshal(body) == nil && c.opts.OnPush != nil {
+			c.opts.OnPush(body)
+		}
+	case protocol.CmdReceipt:
+		body := &imV1.ReceiptBody{}
+		if f.Unmarshal(body) == nil && c.opts.OnReceipt != nil {
+			c.opts.OnReceipt(body)
+		}
+	case protocol.CmdPresence:
+		body := &imV1.PresenceBody{}
+		if f.Unmarshal(body) == nil && c.opts.OnPresence != nil {
+			c.opts.OnPresence(body)
+		}
+	case protocol.CmdKick:
+		body := &imV1.KickBody{}
+		if f.Unmarshal(body) == nil && c.opts.OnKick != nil {
+			c.opts.OnKick(body)
+		}
+	}
+}
+
+func (c *Client) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+			_ = c.Request(ctx, protocol.CmdHeartbeat, &imV1.HeartbeatRequest{ClientTime: time.Now().UnixMilli()}, nil)
+			cancel()
+		}
+	}
+}
+
+func (c *Client) fail(err error) {
+	c.errOnce.Do(func() {
+		c.err = err
+		close(c.done)
+	})
+}
@@ -0,0 +1,146 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"github.com/MortalSC/IM-System/gateway-service/pkg/route"
+	gatewayServiceV1 "github.com/MortalSC/IM-System/gateway-service/pkg/service/gateway.service.v1"
+	libLog "github.com/MortalSC/IM-System/lib/log"
+	"github.com/MortalSC/IM-System/lib/protocol"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// Config 推送配置
+type Config struct {
+	Timeout           time.Duration // 调用单个网关节点的超时
+	CompressThreshold int           // 推送帧体超过该大小时压缩，0 表示不压缩
+}
+
+// Pusher 供后端服务使用：通过路由表查询用户在线设备所在的网关节点，调用节点的 GatewayService
+// 推送是尽力而为的，未送达的数据由客户端通过同步或重新查询补齐
+type Pusher struct {
+	routes *route.Table
+	codec  *protocol.Codec
+	cfg    *Config
+
+	mu      sync.Mutex
+	conns   map[string]*grpc.ClientConn
+	clients map[string]gatewayServiceV1.GatewayServiceClient
+}
+
+func New(routes *route.Table, cfg *Config) *Pusher {
+	return &Pusher{
+		routes:  routes,
+		codec:   &protocol.Codec{MaxFrameSize: protocol.DefaultMaxFrameSize, CompressThreshold: cfg.CompressThreshold},
+		cfg:     cfg,
+		conns:   make(map[string]*grpc.ClientConn),
+		clients: make(map[string]gatewayServiceV1.GatewayServiceClient),
+	}
+}
+
+// Push 将 cmd 帧推送到用户的全部在线设备，skipDevice 不为空时跳过该设备，返回进入发送队列的连接数
+// 帧只编码一次，逐个调用设备所在的网关节点
+func (p *Pusher) Push(ctx context.Context, userId int64, skipDevice string, cmd uint16, body proto.Message) (int, error) {
+	routes, err := p.routes.Lookup(ctx, userId)
+	if err != nil || len(routes) == 0 {
+		return 0, err
+	}
+	f, err := protocol.NewRequest(cmd, 0, body)
+	if err != nil {
+		return 0, err
+	}
+	payload, err := p.codec.Encode(f)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, r := range routes {
+		if r.DeviceId == skipDevice {
+			continue
+		}
+		client, err := p.client(r.Node)
+		if err != nil {
+			libLog.IMLog.Error(fmt.Sprintf("连接网关节点 %s 出错，原因: %v", r.Node, err))
+			continue
+		}
+		callCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+		rsp, err := client.Push(callCtx, &gatewayServiceV1.PushMessage{UserId: userId, DeviceId: r.DeviceId, Payload: payload})
+		cancel()
+		if err != nil {
+			libLog.IMLog.Warn(fmt.Sprintf("推送到网关节点 %s 出错，用户: %d，设备: %s，原因: %v", r.Node, userId, r.DeviceId, err))
+			continue
+		}
+		delivered += int(rsp.Delivered)
+	}
+	return delivered, nil
+}
+
+// Kick 断开用户在各网关节点上的连接，deviceId 为空时断开全部设备，返回关闭的连接数
+// 某个节点调用失败时继续处理其他节点，返回遇到的第一个错误
+func (p *Pusher) Kick(ctx context.Context, userId int64, deviceId, reason string) (int, error) {
+	routes, err := p.routes.Lookup(ctx, userId)
+	if err != nil || len(routes) == 0 {
+		return 0, err
+	}
+	// 同一节点上的多个设备只需调用一次
+	nodes := make(map[string]struct{}, len(routes))
+	for _, r := range routes {
+		if deviceId == "" || r.DeviceId == deviceId {
+			nodes[r.Node] = struct{}{}
+		}
+	}
+
+	closed := 0
+	var firstErr error
+	for node := range nodes {
+		client, err := p.client(node)
+		if err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+			var rsp *gatewayServiceV1.KickResponse
+			rsp, err = client.Kick(callCtx, &gatewayServiceV1.KickMessage{UserId: userId, DeviceId: deviceId, Reason: reason})
+			cancel()
+			if err == nil {
+				closed += int(rsp.Closed)
+				continue
+			}
+		}
+		libLog.IMLog.Warn(fmt.Sprintf("断开网关节点 %s 上的连接出错，用户: %d，设备: %s，原因: %v", node, userId, deviceId, err))
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return closed, firstErr
+}
+
+// Close 关闭到网关节点的连接
+func (p *Pusher) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for node, conn := range p.conns {
+		_ = conn.Close()
+		delete(p.conns, node)
+		delete(p.clients, node)
+	}
+}
+
+// client 每个网关节点复用一个连接，节点地址来自路由表
+func (p *Pusher) client(node string) (gatewayServiceV1.GatewayServiceClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[node]; ok {
+		return c, nil
+	}
+	conn, err := grpc.Dial(node, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	c := gatewayServiceV1.NewGatewayServiceClient(conn)
+	p.conns[node] = conn
+	p.clients[node] = c
+	return c, nil
+}
@@ -0,0 +1,111 @@
+package route
+
+import (
+	"context"
+	LibCache "github.com/MortalSC/IM-System/lib/cache"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	routeKeyPrefix = "GATEWAY_ROUTE_" // GATEWAY_ROUTE_<userId> -> 哈希表 deviceId -> <node>|<connId>
+	nodeKeyPrefix  = "GATEWAY_NODE_"  // GATEWAY_NODE_<node> -> 网关节点存活标记
+	valueSep       = "|"
+)
+
+// Config 路由表配置
+type Config struct {
+	RouteTTL time.Duration // 用户路由的过期时间，网关按 RefreshInterval 续期，节点异常退出后路由在该时间后过期
+	NodeTTL  time.Duration // 节点存活标记的过期时间，过期后该节点上的路由不再返回
+}
+
+// Route 设备当前所在的网关节点
+type Route struct {
+	DeviceId string
+	Node     string // 网关节点的推送地址，后端服务通过该地址调用 GatewayService
+	ConnId   string
+}
+
+// Table 记录每个用户的每台设备连接在哪个网关节点，供后端服务路由推送
+// 网关节点上报和续期，后端服务通过 Lookup 查询
+type Table struct {
+	cache LibCache.Cache
+	cfg   *Config
+}
+
+func NewTable(cache LibCache.Cache, cfg *Config) *Table {
+	return &Table{cache: cache, cfg: cfg}
+}
+
+// Online 上报设备连接到了 node，同一设备重复上报时覆盖，也用于续期
+func (t *Table) Online(ctx context.Context, userId int64, deviceId, node, connId string) error {
+	key := routeKey(userId)
+	if err := t.cache.HSet(ctx, key, deviceId, node+valueSep+connId); err != nil {
+		return err
+	}
+	return t.cache.Expire(ctx, key, t.cfg.RouteTTL)
+}
+
+// Offline 设备连接断开，只有路由仍指向该连接时才删除，避免删掉设备在其他节点上的新连接
+// 读取和删除之间设备恰好重连时可能误删，网关下一次续期会重新写入
+func (t *Table) Offline(ctx context.Context, userId int64, deviceId, connId string) error {
+	key := routeKey(userId)
+	routes, err := t.cache.HGetAll(ctx, key)
+	if err != nil {
+		return err
+	}
+	if _, id, _ := strings.Cut(routes[deviceId], valueSep); id != connId {
+		return nil
+	}
+	_, err = t.cache.HDel(ctx, key, deviceId)
+	return err
+}
+
+// NodeAlive 上报节点存活，网关按 RefreshInterval 调用
+func (t *Table) NodeAlive(ctx context.Context, node string) error {
+	return t.cache.Put(ctx, nodeKeyPrefix+node, strconv.FormatInt(time.Now().UnixMilli(), 10), t.cfg.NodeTTL)
+}
+
+// NodeDown 节点正常下线时删除存活标记，该节点上的路由立即失效
+func (t *Table) NodeDown(ctx context.Context, node string) error {
+	_, err := t.cache.Delete(ctx, nodeKeyPrefix+node)
+	return err
+}
+
+// IsNodeAlive 查询节点是否存活，节点正常下线或异常退出超过 NodeTTL 后返回 false
+func (t *Table) IsNodeAlive(ctx context.Context, node string) (bool, error) {
+	mark, err := t.cache.Get(ctx, nodeKeyPrefix+node)
+	if err != nil {
+		return false, err
+	}
+	return mark != "", nil
+}
+
+// Lookup 查询用户在线设备所在的节点，已下线节点上的路由会被过滤
+func (t *Table) Lookup(ctx context.Context, userId int64) ([]Route, error) {
+	routes, err := t.cache.HGetAll(ctx, routeKey(userId))
+	if err != nil {
+		return nil, err
+	}
+	alive := make(map[string]bool)
+	result := make([]Route, 0, len(routes))
+	for deviceId, v := range routes {
+		node, connId, _ := strings.Cut(v, valueSep)
+		ok, checked := alive[node]
+		if !checked {
+			if ok, err = t.IsNodeAlive(ctx, node); err != nil {
+				return nil, err
+			}
+			alive[node] = ok
+		}
+		if ok {
+			result = append(result, Route{DeviceId: deviceId, Node: node, ConnId: connId})
+		}
+	}
+	return result, nil
+}
+
+func routeKey(userId int64) string {
+	return routeKeyPrefix + strconv.FormatInt(userId, 10)
+}
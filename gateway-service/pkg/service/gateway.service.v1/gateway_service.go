@@ -0,0 +1,42 @@
+package gateway_service_v1
+
+import (
+	"context"
+	errs "github.com/MortalSC/IM-System/gateway-service/internal/errors"
+	"github.com/MortalSC/IM-System/gateway-service/internal/gateway"
+	libErrors "github.com/MortalSC/IM-System/lib/errors"
+)
+
+// GatewayService 供后端服务调用的推送接口，后端先通过 route.Table 查询用户所在节点，再调用对应节点
+type GatewayService struct {
+	UnimplementedGatewayServiceServer
+	gateway *gateway.Gateway
+}
+
+func New(gw *gateway.Gateway) *GatewayService {
+	return &GatewayService{gateway: gw}
+}
+
+// Push 向用户在本节点的连接推送数据，用户不在本节点时 delivered 为 0，由调用方按离线处理
+func (gs *GatewayService) Push(ctx context.Context, msg *PushMessage) (*PushResponse, error) {
+	if msg.UserId <= 0 || len(msg.Payload) == 0 {
+		return nil, libErrors.GrpcError(errs.ErrInvalidPush, "用户和推送内容不能为空")
+	}
+	delivered, dropped := gs.gateway.Push(msg.UserId, msg.DeviceId, msg.Payload)
+	return &PushResponse{Delivered: int32(delivered), Dropped: int32(dropped)}, nil
+}
+
+// Kick 断开用户在本节点的连接，用于设备下线、账号注销等场景
+func (gs *GatewayService) Kick(ctx context.Context, msg *KickMessage) (*KickResponse, error) {
+	if msg.UserId <= 0 {
+		return nil, libErrors.GrpcError(errs.ErrInvalidPush, "用户不能为空")
+	}
+	closed := gs.gateway.Kick(msg.UserId, msg.DeviceId, msg.Reason)
+	return &KickResponse{Closed: int32(closed)}, nil
+}
+
+// NodeStats 本节点的在线用户数和连接数
+func (gs *GatewayService) NodeStats(ctx context.Context, msg *NodeStatsMessage) (*NodeStatsResponse, error) {
+	users, conns := gs.gateway.Stats()
+	return &NodeStatsResponse{Node: gs.gateway.Node(), Users: int32(users), Connections: int32(conns)}, nil
+}